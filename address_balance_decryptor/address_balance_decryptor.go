@@ -8,8 +8,11 @@ import (
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/cryptography/crypto/balance_decryptor"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 )
 
+var metricQueueDepth = metrics.NewGauge("pandora_balance_decryptor_queue", "Number of balances waiting to be decrypted")
+
 type AddressBalanceDecryptor struct {
 	all                   *generics.Map[string, *addressBalanceDecryptorWork]
 	previousValues        *generics.Map[string, uint64]
@@ -43,6 +46,7 @@ func (decryptor *AddressBalanceDecryptor) DecryptBalance(decryptionName string,
 
 	foundWork, loaded := decryptor.all.LoadOrStore(string(publicKey)+"_"+string(encryptedBalance), &addressBalanceDecryptorWork{balancePoint, previousValue, make(chan struct{}), ADDRESS_BALANCE_DECRYPTED_INIT, 0, nil, ctx, statusCallback})
 	if !loaded {
+		metricQueueDepth.Add(1)
 		decryptor.newWorkCn <- foundWork
 	}
 
@@ -21,6 +21,9 @@ var (
 )
 
 func Close() {
+	if err := Mempool.SaveToStore(); err != nil {
+		gui.GUI.Error("Error saving mempool", err)
+	}
 	store.DBClose()
 	gui.GUI.Close()
 	Forging.Close()
@@ -13,6 +13,7 @@ import (
 	"pandora-pay/blockchain/data_storage"
 	"pandora-pay/blockchain/data_storage/assets/asset"
 	"pandora-pay/blockchain/forging/forging_block_work"
+	"pandora-pay/blockchain/genesis"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/blockchain/transactions/transaction/transaction_type"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
@@ -24,6 +25,7 @@ import (
 	"pandora-pay/gui"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/mempool"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
@@ -49,7 +51,31 @@ type Blockchain struct {
 	UpdateNewChainUpdate                    *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]
 	UpdateSocketsSubscriptionsTransactions  *multicast.MulticastChannel[[]*blockchain_types.BlockchainTransactionUpdate]
 	UpdateSocketsSubscriptionsNotifications *multicast.MulticastChannel[*data_storage.DataStorage]
+	UpdateSocketsSubscriptionsChain         *multicast.MulticastChannel[*blockchain_types.BlockchainChainUpdate]
+	UpdateSocketsSubscriptionsReorgAlert    *multicast.MulticastChannel[*BlockchainReorgAlert]
 	NextBlockCreatedCn                      chan *forging_block_work.ForgingWork
+	snapshotHeight                          uint64 //blocks below the trusted block of an imported snapshot can't be removed
+}
+
+var (
+	metricAddBlocksDuration = metrics.NewHistogram("pandora_chain_add_blocks_duration_seconds", "Time spent processing the blocks in AddBlocks", metrics.DefaultBuckets)
+	metricReorgs            = metrics.NewCounter("pandora_chain_reorgs_total", "Number of chain reorganizations")
+	metricRemovedBlocks     = metrics.NewCounter("pandora_chain_removed_blocks_total", "Number of blocks removed by reorganizations")
+	metricInsertedBlocks    = metrics.NewCounter("pandora_chain_inserted_blocks_total", "Number of blocks inserted")
+)
+
+// BlockInvalidError is returned by AddBlocks when a block is rejected because of its content, so the peers which sent it can be penalized
+type BlockInvalidError struct {
+	Height uint64
+	Err    error
+}
+
+func (err *BlockInvalidError) Error() string {
+	return fmt.Sprintf("Block %d is invalid: %s", err.Height, err.Err.Error())
+}
+
+func (err *BlockInvalidError) Unwrap() error {
+	return err.Err
 }
 
 func (chain *Blockchain) validateBlocks(blocksComplete []*block_complete.BlockComplete) (err error) {
@@ -61,11 +87,11 @@ func (chain *Blockchain) validateBlocks(blocksComplete []*block_complete.BlockCo
 	for _, blkComplete := range blocksComplete {
 
 		if err = blkComplete.Verify(); err != nil {
-			return
+			return &BlockInvalidError{blkComplete.Height, err}
 		}
 
 		if err = txs_validator.TxsValidator.ValidateTxs(blkComplete.Txs); err != nil {
-			return
+			return &BlockInvalidError{blkComplete.Height, err}
 		}
 
 	}
@@ -83,6 +109,8 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 	chain.mutex.Lock()
 	defer chain.mutex.Unlock()
 
+	start := time.Now()
+
 	chainData := chain.GetChainData()
 
 	if calledByForging && blocksComplete[len(blocksComplete)-1].Height == chainData.Height-1 && chainData.ConsecutiveSelfForged > 0 {
@@ -98,6 +126,7 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 		helpers.CloneBytes(chainData.PrevHash),         //atomic copy
 		helpers.CloneBytes(chainData.KernelHash),       //atomic copy
 		helpers.CloneBytes(chainData.PrevKernelHash),   //atomic copy
+		helpers.CloneBytes(chainData.StateRoot),        //atomic copy
 		chainData.Height,                               //atomic copy
 		chainData.Timestamp,                            //atomic copy
 		new(big.Int).Set(chainData.Target),             //atomic copy
@@ -164,6 +193,10 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 			}
 
 			firstBlockComplete := blocksComplete[0]
+			if err = chain.verifyReorg(newChainData.Height, firstBlockComplete.Block.Height); err != nil {
+				return
+			}
+
 			if firstBlockComplete.Block.Height < newChainData.Height {
 
 				index := newChainData.Height - 1
@@ -199,6 +232,7 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 				if err = dataStorage.CommitChanges(); err != nil {
 					return
 				}
+				newChainData.StateRoot = dataStorage.StateTree.Root()
 
 			}
 
@@ -216,13 +250,27 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 
 			err = func() (err error) {
 
+				//the errors returned while a block is verified are caused by its content
+				var invalidBlock *block_complete.BlockComplete
+				defer func() {
+					if err != nil && invalidBlock != nil {
+						err = &BlockInvalidError{invalidBlock.Height, err}
+					}
+				}()
+
 				for _, blkComplete := range blocksComplete {
 
+					invalidBlock = blkComplete
+
 					//check block height
 					if blkComplete.Block.Height != newChainData.Height {
 						return errors.New("Block Height is not right!")
 					}
 
+					if err = genesis.VerifyCheckpoint(blkComplete.Block.Height, blkComplete.Block.Bloom.Hash); err != nil {
+						return
+					}
+
 					//check existance of a tx with payloads
 					var foundStakingRewardTx *transaction.Transaction
 					for index, tx := range blkComplete.Txs {
@@ -311,6 +359,10 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 						return errors.New("Timestamp is too much into the future")
 					}
 
+					if blkComplete.Block.Height >= config.FORK_HEIGHT_STATE_ROOT && !bytes.Equal(blkComplete.Block.StateRoot, newChainData.StateRoot) {
+						return errors.New("Block StateRoot is not matching the state")
+					}
+
 					if err = blkComplete.IncludeBlockComplete(dataStorage); err != nil {
 						return fmt.Errorf("Error including block %d into Blockchain: %s", blkComplete.Height, err.Error())
 					}
@@ -323,6 +375,8 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 						return errors.New("Error Processing Pending Future: " + err.Error())
 					}
 
+					invalidBlock = nil
+
 					//to detect if the savedBlock was done correctly
 					savedBlock = false
 
@@ -334,6 +388,7 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 						removedBlocksHeights = removedBlocksHeights[1:]
 					}
 
+					newChainData.StateRoot = dataStorage.StateTree.Root()
 					newChainData.PrevHash = newChainData.Hash
 					newChainData.Hash = blkComplete.Block.Bloom.Hash
 					newChainData.PrevKernelHash = newChainData.KernelHash
@@ -468,6 +523,13 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 		kernelHash = newChainData.KernelHash
 		chain.ChainData.Store(newChainData)
 		chain.mempool.ContinueProcessingCn <- mempool.CONTINUE_PROCESSING_NO_ERROR
+
+		metricAddBlocksDuration.Observe(time.Since(start).Seconds())
+		metricInsertedBlocks.Add(uint64(len(insertedBlocks)))
+		if removed := chainData.Height + uint64(len(insertedBlocks)) - newChainData.Height; removed > 0 {
+			metricReorgs.Inc()
+			metricRemovedBlocks.Add(removed)
+		}
 	} else {
 		chain.mempool.ContinueProcessingCn <- mempool.CONTINUE_PROCESSING_ERROR
 	}
@@ -486,6 +548,11 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 		update.insertedTxs = insertedTxs
 		update.insertedTxsList = insertedTxsList
 		update.insertedBlocks = insertedBlocks
+
+		//all the heights replaced by the inserted blocks
+		for height := insertedBlocks[0].Height; height < chainData.Height; height++ {
+			update.removedBlocksHeights = append(update.removedBlocksHeights, height)
+		}
 		update.allTransactionsChanges = allTransactionsChanges
 	}
 
@@ -511,9 +578,19 @@ func CreateBlockchain(mempool *mempool.Mempool) (*Blockchain, error) {
 		multicast.NewMulticastChannel[*blockchain_types.BlockchainUpdates](),
 		multicast.NewMulticastChannel[[]*blockchain_types.BlockchainTransactionUpdate](),
 		multicast.NewMulticastChannel[*data_storage.DataStorage](),
+		multicast.NewMulticastChannel[*blockchain_types.BlockchainChainUpdate](),
+		multicast.NewMulticastChannel[*BlockchainReorgAlert](),
 		make(chan *forging_block_work.ForgingWork),
+		0,
 	}
 
+	metrics.NewGaugeFunc("pandora_chain_height", "Height of the chain", func() float64 {
+		if chainData := chain.GetChainData(); chainData != nil {
+			return float64(chainData.Height)
+		}
+		return 0
+	})
+
 	chain.updatesQueue.chain = chain
 	chain.updatesQueue.processBlockchainUpdatesQueue()
 	chain.updatesQueue.processBlockchainUpdateMempool()
@@ -536,6 +613,12 @@ func (chain *Blockchain) InitializeChain() (err error) {
 		}
 	}
 
+	if config.NODE_CONSENSUS == config.NODE_CONSENSUS_TYPE_FULL {
+		if err = chain.rebuildStateTree(); err != nil {
+			return
+		}
+	}
+
 	chainData := chain.GetChainData()
 	chainData.updateChainInfo()
 
@@ -43,6 +43,7 @@ func (chain *Blockchain) createGenesisBlockchainData() *BlockchainData {
 		helpers.CloneBytes(genesis.GenesisData.Hash),
 		helpers.CloneBytes(genesis.GenesisData.KernelHash),
 		helpers.CloneBytes(genesis.GenesisData.KernelHash),
+		nil,
 		0,
 		0,
 		new(big.Int).SetBytes(helpers.CloneBytes(genesis.GenesisData.Target)),
@@ -109,6 +110,8 @@ func (chain *Blockchain) initializeNewChain(chainData *BlockchainData, dataStora
 		false,
 		false,
 		false,
+		false,
+		false,
 		byte(config_coins.DECIMAL_SEPARATOR),
 		config_coins.MAX_SUPPLY_COINS_UNITS,
 		supply,
@@ -131,6 +134,7 @@ func (chain *Blockchain) initializeNewChain(chainData *BlockchainData, dataStora
 
 	chainData.AssetsCount = dataStorage.Asts.Count
 	chainData.AccountsCount = dataStorage.Regs.Count + dataStorage.PlainAccs.Count
+	chainData.StateRoot = dataStorage.StateTree.Root()
 
 	return
 }
@@ -209,6 +213,9 @@ func (chain *Blockchain) createNextBlockForForging(chainData *BlockchainData, ne
 			}
 		}
 
+		if blk.Height >= config.FORK_HEIGHT_STATE_ROOT {
+			blk.StateRoot = chainData.StateRoot
+		}
 		blk.StakingNonce = make([]byte, 32)
 
 		blk.BloomSerializedNow(blk.SerializeManualToBytes())
@@ -0,0 +1,72 @@
+package blockchain
+
+import (
+	"fmt"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/genesis"
+	"pandora-pay/config"
+	"pandora-pay/config/globals"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/metrics"
+)
+
+var metricRejectedReorgs = metrics.NewCounter("pandora_chain_rejected_reorgs_total", "Number of reorganizations rejected by the maximum reorg depth or by a checkpoint")
+
+// BlockchainReorgAlert is broadcasted as "chain/reorg-alert" and to the ReorgAlert subscriptions when a fork tries to roll back the chain deeper than allowed
+type BlockchainReorgAlert struct {
+	ChainHeight uint64 `json:"chainHeight" msgpack:"chainHeight"`
+	ForkHeight  uint64 `json:"forkHeight" msgpack:"forkHeight"`
+	Depth       uint64 `json:"depth" msgpack:"depth"`
+	Reason      string `json:"reason" msgpack:"reason"`
+}
+
+// RejectReorg reports a reorganization which was refused and returns the error for it
+func (chain *Blockchain) RejectReorg(chainHeight, forkHeight uint64, reason string) error {
+
+	alert := &BlockchainReorgAlert{chainHeight, forkHeight, chainHeight - forkHeight, reason}
+
+	metricRejectedReorgs.Inc()
+	gui.GUI.Warning(fmt.Sprintf("Reorg rejected. Height %d Fork %d Depth %d. %s", alert.ChainHeight, alert.ForkHeight, alert.Depth, reason))
+	globals.MainEvents.BroadcastEvent("chain/reorg-alert", alert)
+	chain.UpdateSocketsSubscriptionsReorgAlert.Broadcast(alert)
+
+	return fmt.Errorf("Reorg rejected. %s", reason)
+}
+
+// the blocks above forkHeight will be removed
+func (chain *Blockchain) verifyReorg(chainHeight, forkHeight uint64) error {
+
+	if forkHeight >= chainHeight {
+		return nil
+	}
+
+	if config.FORK_MAX_REORG_DEPTH > 0 && chainHeight-forkHeight > config.FORK_MAX_REORG_DEPTH {
+		return chain.RejectReorg(chainHeight, forkHeight, fmt.Sprintf("Depth is bigger than %d", config.FORK_MAX_REORG_DEPTH))
+	}
+
+	if checkpointHeight, found := genesis.GetLastCheckpoint(chainHeight); found && checkpointHeight >= forkHeight {
+		return chain.RejectReorg(chainHeight, forkHeight, fmt.Sprintf("Checkpoint %d would be removed", checkpointHeight))
+	}
+
+	if chain.snapshotHeight > forkHeight {
+		return chain.RejectReorg(chainHeight, forkHeight, fmt.Sprintf("Blocks below the imported snapshot %d can't be removed", chain.snapshotHeight))
+	}
+
+	return nil
+}
+
+// VerifyFork checks a fork against the maximum reorg depth and the checkpoints before its blocks are downloaded
+func (chain *Blockchain) VerifyFork(forkHeight uint64, headers []*block.Block) error {
+
+	if err := chain.verifyReorg(chain.GetChainData().Height, forkHeight); err != nil {
+		return err
+	}
+
+	for _, header := range headers {
+		if err := genesis.VerifyCheckpoint(header.Height, header.Bloom.Hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
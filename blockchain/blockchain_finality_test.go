@@ -0,0 +1,107 @@
+package blockchain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/genesis"
+	"pandora-pay/config"
+	"pandora-pay/cryptography"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/multicast"
+	"pandora-pay/mempool"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"pandora-pay/txs_validator"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestAddBlocksRejectsReorg(t *testing.T) {
+
+	guiInterface := gui.GUI
+	defer func() {
+		gui.GUI = guiInterface
+	}()
+
+	var err error
+	gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+	assert.Nil(t, err)
+	assert.Nil(t, txs_validator.NewTxsValidator())
+
+	db, err := store_db_memory.CreateStoreDBMemory("/blockchain")
+	assert.Nil(t, err)
+	defer db.Close()
+
+	store.StoreBlockchain = &store.Store{Name: "blockchain", Opened: true, DB: db}
+	defer func() {
+		store.StoreBlockchain = nil
+	}()
+
+	checkpoints := genesis.Checkpoints
+	defer func() {
+		genesis.Checkpoints = checkpoints
+	}()
+	genesis.Checkpoints = nil
+
+	const chainHeight = uint64(1000)
+
+	chain := &Blockchain{
+		ChainData:                            &generics.Value[*BlockchainData]{},
+		mempool:                              &mempool.Mempool{SuspendProcessingCn: make(chan struct{}, 1), ContinueProcessingCn: make(chan mempool.ContinueProcessingType, 1)},
+		mutex:                                &sync.Mutex{},
+		updatesQueue:                         &BlockchainUpdatesQueue{updatesCn: make(chan *BlockchainUpdate, 1)},
+		UpdateSocketsSubscriptionsReorgAlert: multicast.NewMulticastChannel[*BlockchainReorgAlert](),
+	}
+	chainData := &BlockchainData{Height: chainHeight, Target: big.NewInt(1), BigTotalDifficulty: big.NewInt(1)}
+	chain.ChainData.Store(chainData)
+
+	//our blocks are different from the fork blocks
+	assert.Nil(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		for height := uint64(0); height < chainHeight; height++ {
+			writer.Put("blockHash_ByHeight"+strconv.FormatUint(height, 10), cryptography.RandomHash())
+		}
+		return nil
+	}))
+
+	addBlock := func(height uint64) error {
+
+		blkComplete := block_complete.CreateEmptyBlockComplete()
+		blkComplete.Block.Height = height
+		blkComplete.Block.MerkleHash = blkComplete.MerkleHash()
+		blkComplete.Block.StateRoot = cryptography.RandomHash()
+		blkComplete.Block.PrevHash = cryptography.RandomHash()
+		blkComplete.Block.PrevKernelHash = cryptography.RandomHash()
+		blkComplete.Block.StakingAmount = 1
+		assert.Nil(t, blkComplete.BloomAll())
+
+		_, err := chain.AddBlocks([]*block_complete.BlockComplete{blkComplete}, false, advanced_connection_types.UUID_ALL)
+
+		<-chain.mempool.SuspendProcessingCn
+		assert.Equal(t, mempool.CONTINUE_PROCESSING_ERROR, <-chain.mempool.ContinueProcessingCn)
+		assert.Equal(t, err, (<-chain.updatesQueue.updatesCn).err)
+		assert.Same(t, chainData, chain.GetChainData(), "the chain must not change")
+
+		return err
+	}
+
+	err = addBlock(chainHeight - config.FORK_MAX_REORG_DEPTH - 1)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Reorg rejected. Depth is bigger than")
+
+	genesis.Checkpoints = map[uint64][]byte{950: cryptography.RandomHash()}
+
+	err = addBlock(950)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Reorg rejected. Checkpoint 950 would be removed")
+
+	//the blocks above the checkpoint can be replaced. The test chain has no blocks to remove
+	err = addBlock(951)
+	assert.NotNil(t, err)
+	assert.NotContains(t, err.Error(), "Reorg rejected")
+}
@@ -0,0 +1,555 @@
+package blockchain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/sha3"
+	"hash"
+	"io"
+	"math"
+	"os"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/config"
+	"pandora-pay/cryptography"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/store"
+	"pandora-pay/store/hash_map"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+	"strings"
+)
+
+const (
+	SNAPSHOT_MAGIC   = "PANDORA-SNAPSHOT"
+	SNAPSHOT_VERSION = uint64(1)
+)
+
+//history that is not required to continue the chain from the snapshot
+var snapshotHistoryPrefixes = []string{"blockInfo_ByHash", "txInfo_ByHash", "txPreview_ByHash", "txHash_ByHeight", "txKeys:", "addrTx:", "addrTxsCount:"}
+
+//data stored by height, kept only for the last blocks to allow rollbacks and difficulty computation
+var snapshotHeightPrefixes = []string{"blockchainInfo_", "totalDifficulty", "blockHash_ByHeight", "blockKernelHash_ByHeight", "blockTxs", "dataStorage:transitionsCollectionsKeys:"}
+
+//data stored by block hash and tx hash, kept only for the last blocks
+var snapshotBlockPrefixes = []string{"block_ByHash", "blockHeight_ByHash"}
+var snapshotTxPrefixes = []string{"tx:", "txHash:", "txBlock:"}
+
+//the height of the trusted block of an imported snapshot. The transitions of the older blocks are not imported, so they can't be removed
+const snapshotHeightKey = "snapshotHeight"
+
+type snapshotHeader struct {
+	Version     uint64
+	Network     uint64
+	Height      uint64 //chain height
+	Hash        []byte //chain hash
+	WindowStart uint64 //first height of the blocks included
+}
+
+type snapshotWriter struct {
+	w      *bufio.Writer
+	hasher hash.Hash
+	err    error
+}
+
+func (w *snapshotWriter) write(data []byte) {
+	if w.err != nil {
+		return
+	}
+	if _, w.err = w.w.Write(data); w.err == nil {
+		w.hasher.Write(data)
+	}
+}
+
+func (w *snapshotWriter) writeUvarint(value uint64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, value)
+	w.write(buf[:n])
+}
+
+func (w *snapshotWriter) writeVariableBytes(data []byte) {
+	w.writeUvarint(uint64(len(data)))
+	w.write(data)
+}
+
+type snapshotReader struct {
+	r      *bufio.Reader
+	hasher hash.Hash
+}
+
+func (r *snapshotReader) ReadByte() (byte, error) {
+	b, err := r.r.ReadByte()
+	if err == nil {
+		r.hasher.Write([]byte{b})
+	}
+	return b, err
+}
+
+func (r *snapshotReader) readBytes(count uint64) ([]byte, error) {
+	if count > config.BLOCK_MAX_SIZE*16 {
+		return nil, errors.New("Snapshot entry is too big")
+	}
+	data := make([]byte, count)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return nil, err
+	}
+	r.hasher.Write(data)
+	return data, nil
+}
+
+func (r *snapshotReader) readVariableBytes() ([]byte, error) {
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.readBytes(count)
+}
+
+func hasAnyPrefix(key string, prefixes []string) (string, bool) {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+func isSnapshotKey(key string, windowStart uint64, windowBlocks, windowTxs map[string]bool) bool {
+
+	if _, found := hasAnyPrefix(key, snapshotHistoryPrefixes); found {
+		return false
+	}
+
+	heightStr := ""
+	if prefix, found := hasAnyPrefix(key, snapshotHeightPrefixes); found {
+		heightStr = key[len(prefix):]
+	} else if index := strings.LastIndex(key, ":transitions:"); index >= 0 {
+		heightStr = key[index+len(":transitions:"):]
+	}
+	if heightStr != "" {
+		height, err := strconv.ParseUint(heightStr, 10, 64)
+		return err != nil || height >= windowStart
+	}
+
+	if prefix, found := hasAnyPrefix(key, snapshotBlockPrefixes); found {
+		return windowBlocks[key[len(prefix):]]
+	}
+	if prefix, found := hasAnyPrefix(key, snapshotTxPrefixes); found {
+		return windowTxs[key[len(prefix):]]
+	}
+
+	return true
+}
+
+// the keys imported from a snapshot: the chain info, the last blocks with their txs and the elements of the hash maps with their indexes and transitions.
+// The other keys of the hash maps and the state tree are rebuilt locally from the elements
+func isSnapshotImportedKey(key string) bool {
+
+	if key == "blockchainInfo" {
+		return true
+	}
+	if _, found := hasAnyPrefix(key, snapshotHeightPrefixes); found {
+		return true
+	}
+	if _, found := hasAnyPrefix(key, snapshotBlockPrefixes); found {
+		return true
+	}
+	if _, found := hasAnyPrefix(key, snapshotTxPrefixes); found {
+		return true
+	}
+
+	if strings.HasPrefix(key, "stateTree:") {
+		return false
+	}
+
+	_, suffix, found := data_storage.SplitStateKey(key)
+	return found && (strings.HasPrefix(suffix, ":map:") || strings.HasPrefix(suffix, ":listKeys:") || strings.HasPrefix(suffix, ":transitions:"))
+}
+
+// the imported state is rolled back to the state on top of which the trusted block was included and it must match its StateRoot.
+// The trusted block and the blocks after it are removed and will be downloaded again
+func (chain *Blockchain) rollbackSnapshot(writer store_db_interface.StoreDBTransactionInterface, header *snapshotHeader, hashes map[uint64][]byte, trusted *block.Block, transitions []string) (err error) {
+
+	dataStorage := data_storage.NewDataStorage(writer)
+
+	for height := header.Height; height > trusted.Height; height-- {
+		if err = dataStorage.ReadTransitionalChangesFromStore(strconv.FormatUint(height-1, 10)); err != nil {
+			return
+		}
+	}
+
+	if err = dataStorage.CommitChanges(); err != nil {
+		return
+	}
+
+	if !bytes.Equal(dataStorage.StateTree.Root(), trusted.StateRoot) {
+		return errors.New("Snapshot state root is not matching the trusted block")
+	}
+
+	for height := trusted.Height; height < header.Height; height++ {
+
+		hash := hashes[height]
+		writer.Delete("block_ByHash" + string(hash))
+		writer.Delete("blockHeight_ByHash" + string(hash))
+
+		var txHashes [][]byte
+		if err = msgpack.Unmarshal(writer.Get("blockTxs"+strconv.FormatUint(height, 10)), &txHashes); err != nil {
+			return
+		}
+		for _, txHash := range txHashes {
+			writer.Delete("tx:" + string(txHash))
+			writer.Delete("txHash:" + string(txHash))
+			writer.Delete("txBlock:" + string(txHash))
+		}
+
+		if err = chain.deleteUnusedBlocksComplete(writer, height, dataStorage); err != nil {
+			return
+		}
+	}
+
+	//the transitions of the blocks before the trusted block are not authenticated, so they are removed
+	for height := header.WindowStart; height < header.Height; height++ {
+		writer.Delete("dataStorage:transitionsCollectionsKeys:" + strconv.FormatUint(height, 10))
+	}
+	for _, key := range transitions {
+		writer.Delete(key)
+	}
+
+	chainData := &BlockchainData{}
+	if err = chainData.loadBlockchainInfo(writer, trusted.Height); err != nil {
+		return
+	}
+	if chainData.Height != trusted.Height || !bytes.Equal(chainData.Hash, trusted.PrevHash) {
+		return errors.New("Snapshot chain info is not matching the trusted block")
+	}
+
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, trusted.Height)
+	writer.Put(snapshotHeightKey, buf[:n])
+
+	chainData.StateRoot = dataStorage.StateTree.Root()
+	chainData.AssetsCount = dataStorage.Asts.Count
+	chainData.AccountsCount = dataStorage.Regs.Count + dataStorage.PlainAccs.Count
+
+	chainData.saveBlockchainHeight(writer)
+	return chainData.saveBlockchain(writer)
+}
+
+// ExportSnapshot writes the committed state and the last blocks required to continue the chain into a versioned file.
+// The blocks history, the txs and the extended info of older blocks are not included
+func (chain *Blockchain) ExportSnapshot(path string) (err error) {
+
+	file, err := os.Create(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	w := &snapshotWriter{bufio.NewWriter(file), sha3.New256(), nil}
+
+	count := 0
+	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		chainInfoData := reader.Get("blockchainInfo")
+		if chainInfoData == nil {
+			return errors.New("Chain not found")
+		}
+
+		chainData := &BlockchainData{}
+		if err = msgpack.Unmarshal(chainInfoData, chainData); err != nil {
+			return
+		}
+		if chainData.Height == 0 {
+			return errors.New("Chain has no blocks")
+		}
+
+		header := &snapshotHeader{SNAPSHOT_VERSION, config.NETWORK_SELECTED, chainData.Height, chainData.Hash, 0}
+
+		window := config.FORK_MAX_UNCLE_ALLOWED + config.DIFFICULTY_BLOCK_WINDOW
+		if chainData.Height > window {
+			header.WindowStart = chainData.Height - window
+		}
+
+		windowBlocks := make(map[string]bool)
+		windowTxs := make(map[string]bool)
+		for height := header.WindowStart; height < chainData.Height; height++ {
+
+			var hash []byte
+			if hash, err = chain.LoadBlockHash(reader, height); err != nil {
+				return
+			}
+			windowBlocks[string(hash)] = true
+
+			var txHashes [][]byte
+			if err = msgpack.Unmarshal(reader.Get("blockTxs"+strconv.FormatUint(height, 10)), &txHashes); err != nil {
+				return
+			}
+			for _, txHash := range txHashes {
+				windowTxs[string(txHash)] = true
+			}
+		}
+
+		w.write([]byte(SNAPSHOT_MAGIC))
+		w.writeUvarint(header.Version)
+		w.writeUvarint(header.Network)
+		w.writeUvarint(header.Height)
+		w.writeVariableBytes(header.Hash)
+		w.writeUvarint(header.WindowStart)
+
+		reader.Iterate("", "", 0, func(key string, value []byte) bool {
+			if isSnapshotKey(key, header.WindowStart, windowBlocks, windowTxs) && isSnapshotImportedKey(key) {
+				w.writeVariableBytes([]byte(key))
+				w.writeVariableBytes(value)
+				count++
+			}
+			return w.err == nil
+		})
+
+		//an empty key marks the end of the entries
+		w.writeUvarint(0)
+
+		return w.err
+	}); err != nil {
+		return
+	}
+
+	if _, err = w.w.Write(w.hasher.Sum(nil)); err != nil {
+		return
+	}
+	if err = w.w.Flush(); err != nil {
+		return
+	}
+
+	gui.GUI.Info("Snapshot exported", path, count)
+	return
+}
+
+// ImportSnapshot loads a snapshot created by ExportSnapshot into an empty blockchain store.
+// The last blocks included in the snapshot must link to each other and one of them must match the trusted block hash.
+// The state tree is rebuilt from the imported elements and the chain is rolled back to the StateRoot of the trusted block.
+// In case the verification fails, nothing is stored
+func (chain *Blockchain) ImportSnapshot(path string, trustedHash []byte) (err error) {
+
+	if config.FORK_HEIGHT_STATE_ROOT == math.MaxUint64 {
+		return errors.New("Snapshots can be imported only on networks where the blocks include the StateRoot")
+	}
+	if len(trustedHash) != cryptography.HashSize {
+		return errors.New("Trusted block hash is invalid")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	r := &snapshotReader{bufio.NewReader(file), sha3.New256()}
+
+	magic, err := r.readBytes(uint64(len(SNAPSHOT_MAGIC)))
+	if err != nil {
+		return
+	}
+	if string(magic) != SNAPSHOT_MAGIC {
+		return errors.New("Invalid snapshot file")
+	}
+
+	header := &snapshotHeader{}
+	if header.Version, err = binary.ReadUvarint(r); err != nil {
+		return
+	}
+	if header.Version != SNAPSHOT_VERSION {
+		return fmt.Errorf("Snapshot version %d is not supported", header.Version)
+	}
+	if header.Network, err = binary.ReadUvarint(r); err != nil {
+		return
+	}
+	if header.Network != config.NETWORK_SELECTED {
+		return errors.New("Snapshot was created for a different network")
+	}
+	if header.Height, err = binary.ReadUvarint(r); err != nil {
+		return
+	}
+	if header.Hash, err = r.readVariableBytes(); err != nil {
+		return
+	}
+	if header.WindowStart, err = binary.ReadUvarint(r); err != nil {
+		return
+	}
+	if header.Height == 0 || header.WindowStart >= header.Height {
+		return errors.New("Snapshot header is invalid")
+	}
+
+	count := 0
+	var trustedHeight uint64
+	if err = store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if writer.Exists("blockchainInfo") {
+			return errors.New("The blockchain store must be empty to import a snapshot")
+		}
+
+		blocks := make(map[string][]byte)
+		hashes := make(map[uint64][]byte)
+		var chainInfoData []byte
+
+		//the elements of each hash map and their indexes. The other keys of the hash maps and the state tree are not trusted and are rebuilt
+		elements := make(map[string][]string)
+		indexes := make(map[string]map[string]uint64)
+		transitions := make([]string, 0)
+
+		for {
+
+			var key, value []byte
+			if key, err = r.readVariableBytes(); err != nil {
+				return
+			}
+			if len(key) == 0 {
+				break
+			}
+			if value, err = r.readVariableBytes(); err != nil {
+				return
+			}
+
+			keyStr := string(key)
+			if !isSnapshotImportedKey(keyStr) {
+				return errors.New("Snapshot contains a key that can't be imported")
+			}
+
+			if strings.HasPrefix(keyStr, "block_ByHash") {
+				blocks[keyStr[len("block_ByHash"):]] = value
+			} else if strings.HasPrefix(keyStr, "blockHash_ByHeight") {
+				var height uint64
+				if height, err = strconv.ParseUint(keyStr[len("blockHash_ByHeight"):], 10, 64); err != nil {
+					return
+				}
+				hashes[height] = value
+			} else if keyStr == "blockchainInfo" {
+				chainInfoData = value
+			} else if name, suffix, found := data_storage.SplitStateKey(keyStr); found {
+
+				if strings.HasPrefix(suffix, ":map:") {
+					elements[name] = append(elements[name], suffix[len(":map:"):])
+				} else if strings.HasPrefix(suffix, ":listKeys:") {
+					var index uint64
+					if index, err = strconv.ParseUint(string(value), 10, 64); err != nil {
+						return
+					}
+					if indexes[name] == nil {
+						indexes[name] = make(map[string]uint64)
+					}
+					indexes[name][suffix[len(":listKeys:"):]] = index
+					continue
+				} else {
+					transitions = append(transitions, keyStr)
+				}
+
+			}
+
+			writer.Put(keyStr, value)
+			count++
+		}
+
+		checksum := r.hasher.Sum(nil)
+		expected := make([]byte, len(checksum))
+		if _, err = io.ReadFull(r.r, expected); err != nil {
+			return
+		}
+		if !bytes.Equal(checksum, expected) {
+			return errors.New("Snapshot checksum is invalid")
+		}
+
+		chainData := &BlockchainData{}
+		if chainInfoData == nil {
+			return errors.New("Snapshot is missing the chain info")
+		}
+		if err = msgpack.Unmarshal(chainInfoData, chainData); err != nil {
+			return
+		}
+		if chainData.Height != header.Height || !bytes.Equal(chainData.Hash, header.Hash) {
+			return errors.New("Snapshot chain info is not matching the header")
+		}
+
+		//the elements are stored again with their indexes to rebuild the lists, the counters and the state tree
+		dataStorage := data_storage.NewDataStorage(writer)
+		for name, keys := range elements {
+
+			var hashMap hash_map.HashMapInterface
+			if hashMap, err = dataStorage.GetStateHashMap(name); err != nil {
+				return
+			}
+
+			//the elements that are deletable are not stored again
+			for _, key := range keys {
+				value := writer.Get(name + ":map:" + key)
+				writer.Delete(name + ":map:" + key)
+				if err = hashMap.ImportSerialized(key, value, indexes[name][key]); err != nil {
+					return
+				}
+			}
+			if err = dataStorage.CommitChanges(); err != nil {
+				return
+			}
+		}
+		dataStorage.SaveStateTreeVersion()
+
+		if !bytes.Equal(chainData.StateRoot, dataStorage.StateTree.Root()) {
+			return errors.New("Snapshot state root is not matching the chain info")
+		}
+
+		var trusted *block.Block
+		var prevHash []byte
+		for height := header.WindowStart; height < header.Height; height++ {
+
+			hash := hashes[height]
+			data := blocks[string(hash)]
+			if hash == nil || data == nil {
+				return fmt.Errorf("Snapshot is missing the block %d", height)
+			}
+			if !bytes.Equal(cryptography.SHA3(data), hash) {
+				return fmt.Errorf("Snapshot block %d hash is invalid", height)
+			}
+
+			blk := block.CreateEmptyBlock()
+			if err = blk.Deserialize(advanced_buffers.NewBufferReader(data)); err != nil {
+				return
+			}
+			if blk.Height != height {
+				return fmt.Errorf("Snapshot block %d height is invalid", height)
+			}
+			if prevHash != nil && !bytes.Equal(blk.PrevHash, prevHash) {
+				return fmt.Errorf("Snapshot block %d is not linked to the previous block", height)
+			}
+			prevHash = hash
+
+			if bytes.Equal(hash, trustedHash) {
+				trusted = blk
+			}
+		}
+
+		if !bytes.Equal(prevHash, header.Hash) {
+			return errors.New("Snapshot last block is not matching the chain hash")
+		}
+		if trusted == nil {
+			return errors.New("Snapshot doesn't contain the trusted block hash")
+		}
+		if trusted.Height == 0 || header.Height-trusted.Height > config.FORK_MAX_UNCLE_ALLOWED {
+			return errors.New("Snapshot trusted block is too old")
+		}
+		if trusted.Height < config.FORK_HEIGHT_STATE_ROOT {
+			return errors.New("Snapshot trusted block doesn't include the StateRoot")
+		}
+
+		trustedHeight = trusted.Height
+		return chain.rollbackSnapshot(writer, header, hashes, trusted, transitions)
+	}); err != nil {
+		return
+	}
+
+	gui.GUI.Info("Snapshot imported", path, count, trustedHeight, base64.StdEncoding.EncodeToString(trustedHash))
+	return
+}
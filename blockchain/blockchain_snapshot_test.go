@@ -0,0 +1,277 @@
+package blockchain
+
+import (
+	"bufio"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/sha3"
+	"math"
+	"math/big"
+	"os"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/blockchain/data_storage/conditional_payments_list/conditional_payment"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/asset_fee_liquidity"
+	"pandora-pay/config"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const snapshotTestBlocks = uint64(5)
+const snapshotTestTrusted = uint64(3)
+
+// every block registers an account and creates a conditional payment. The last block deletes the first conditional payment
+func createSnapshotTestChain(t *testing.T, chain *Blockchain, db store_db_interface.StoreDBInterface) (hashes [][]byte, publicKeys [][]byte) {
+
+	assert.Nil(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		chainData := &BlockchainData{Hash: cryptography.RandomHash(), Target: big.NewInt(1), BigTotalDifficulty: big.NewInt(1)}
+		var firstPayment string
+
+		for height := uint64(0); height < snapshotTestBlocks; height++ {
+
+			dataStorage := data_storage.NewDataStorage(writer)
+
+			blkComplete := block_complete.CreateEmptyBlockComplete()
+			blkComplete.Block.Height = height
+			blkComplete.Block.MerkleHash = blkComplete.MerkleHash()
+			blkComplete.Block.StateRoot = dataStorage.StateTree.Root()
+			blkComplete.Block.PrevHash = chainData.Hash
+			blkComplete.Block.PrevKernelHash = cryptography.RandomHash()
+			blkComplete.Block.StakingAmount = 1
+			blkComplete.Block.StakingNonce = cryptography.RandomHash()
+			assert.Nil(t, blkComplete.BloomAll())
+
+			if height == 0 {
+				assert.Nil(t, dataStorage.Asts.CreateAsset(config_coins.NATIVE_ASSET_FULL, &asset.Asset{
+					DecimalSeparator: byte(config_coins.DECIMAL_SEPARATOR),
+					MaxSupply:        config_coins.MAX_SUPPLY_COINS_UNITS,
+					UpdatePublicKey:  config_coins.BURN_PUBLIC_KEY,
+					SupplyPublicKey:  config_coins.BURN_PUBLIC_KEY,
+					Name:             config_coins.NATIVE_ASSET_NAME,
+					Ticker:           config_coins.NATIVE_ASSET_TICKER,
+					Identification:   config_coins.NATIVE_ASSET_IDENTIFICATION,
+					Description:      config_coins.NATIVE_ASSET_DESCRIPTION,
+				}))
+			}
+
+			publicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+			publicKeys = append(publicKeys, publicKey)
+
+			_, err = dataStorage.CreateRegistration(publicKey, false, nil)
+			assert.Nil(t, err)
+
+			accs, acc, err2 := dataStorage.CreateAccount(config_coins.NATIVE_ASSET_FULL, publicKey, true)
+			assert.Nil(t, err2)
+			acc.Balance.AddBalanceUint(100 + height)
+			assert.Nil(t, accs.Update(string(publicKey), acc))
+
+			if height == 1 {
+				plainAcc, err := dataStorage.CreatePlainAccount(publicKey, false)
+				assert.Nil(t, err)
+				liquidity := &asset_fee_liquidity.AssetFeeLiquidity{Asset: config_coins.NATIVE_ASSET_FULL, Rate: 1}
+				status, err := plainAcc.AssetFeeLiquidities.UpdateLiquidity(liquidity)
+				assert.Nil(t, err)
+				plainAcc.AssetFeeLiquidities.Collector = publicKey
+				plainAcc.AssetFeeLiquidities.Version = asset_fee_liquidity.SIMPLE
+				assert.Nil(t, dataStorage.AstsFeeLiquidityCollection.UpdateLiquidity(publicKey, liquidity.Rate, liquidity.LeadingZeros, liquidity.Asset, status))
+				assert.Nil(t, dataStorage.PlainAccs.Update(string(publicKey), plainAcc))
+			}
+
+			conditionalPayments, err2 := dataStorage.ConditionalPaymentsCollection.GetMap(10)
+			assert.Nil(t, err2)
+			if height == snapshotTestBlocks-1 {
+				conditionalPayments.Delete(firstPayment)
+			} else {
+				condPayment := conditional_payment.NewConditionalPayment(nil, 0, 10)
+				condPayment.TxId = cryptography.RandomHash()
+				condPayment.Asset = config_coins.NATIVE_ASSET_FULL
+				condPayment.Hashlock = cryptography.RandomHash()
+				assert.Nil(t, conditionalPayments.Create(string(condPayment.TxId)+"_0", condPayment))
+				if height == 0 {
+					firstPayment = string(condPayment.TxId) + "_0"
+				}
+			}
+
+			if _, err = chain.saveBlockComplete(writer, blkComplete, 0, make(map[string][]byte), nil, dataStorage); err != nil {
+				return
+			}
+			hashes = append(hashes, blkComplete.Block.Bloom.Hash)
+
+			chainData.StateRoot = dataStorage.StateTree.Root()
+			chainData.PrevHash = chainData.Hash
+			chainData.Hash = blkComplete.Block.Bloom.Hash
+			chainData.Height += 1
+			if err = chainData.saveBlockchainInfo(writer); err != nil {
+				return
+			}
+		}
+
+		return chainData.saveBlockchain(writer)
+	}))
+
+	return
+}
+
+// copies the snapshot entries changing them with modify and appending the extra entries
+func rewriteSnapshot(t *testing.T, path, newPath string, modify func(key string, value []byte) []byte, extra map[string][]byte) {
+
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	newFile, err := os.Create(newPath)
+	assert.Nil(t, err)
+	defer newFile.Close()
+
+	r := &snapshotReader{bufio.NewReader(file), sha3.New256()}
+	w := &snapshotWriter{bufio.NewWriter(newFile), sha3.New256(), nil}
+
+	magic, err := r.readBytes(uint64(len(SNAPSHOT_MAGIC)))
+	assert.Nil(t, err)
+	w.write(magic)
+	for i := 0; i < 3; i++ {
+		value, err := binary.ReadUvarint(r)
+		assert.Nil(t, err)
+		w.writeUvarint(value)
+	}
+	hash, err := r.readVariableBytes()
+	assert.Nil(t, err)
+	w.writeVariableBytes(hash)
+	windowStart, err := binary.ReadUvarint(r)
+	assert.Nil(t, err)
+	w.writeUvarint(windowStart)
+
+	for {
+		key, err := r.readVariableBytes()
+		assert.Nil(t, err)
+		if len(key) == 0 {
+			break
+		}
+		value, err := r.readVariableBytes()
+		assert.Nil(t, err)
+		w.writeVariableBytes(key)
+		w.writeVariableBytes(modify(string(key), value))
+	}
+	for key, value := range extra {
+		w.writeVariableBytes([]byte(key))
+		w.writeVariableBytes(value)
+	}
+	w.writeUvarint(0)
+	assert.Nil(t, w.err)
+
+	_, err = w.w.Write(w.hasher.Sum(nil))
+	assert.Nil(t, err)
+	assert.Nil(t, w.w.Flush())
+}
+
+func TestSnapshot(t *testing.T) {
+
+	guiInterface := gui.GUI
+	forkHeightStateRoot := config.FORK_HEIGHT_STATE_ROOT
+	defer func() {
+		gui.GUI = guiInterface
+		config.FORK_HEIGHT_STATE_ROOT = forkHeightStateRoot
+		store.StoreBlockchain = nil
+	}()
+
+	var err error
+	gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+	assert.Nil(t, err)
+	config.FORK_HEIGHT_STATE_ROOT = 0
+
+	db, err := store_db_memory.CreateStoreDBMemory("/blockchain")
+	assert.Nil(t, err)
+	defer db.Close()
+	store.StoreBlockchain = &store.Store{Name: "blockchain", Opened: true, DB: db}
+
+	chain := &Blockchain{}
+	hashes, publicKeys := createSnapshotTestChain(t, chain, db)
+
+	path := filepath.Join(t.TempDir(), "snapshot")
+	assert.Nil(t, chain.ExportSnapshot(path))
+
+	importSnapshot := func(path string) error {
+		importDB, err := store_db_memory.CreateStoreDBMemory("/blockchain")
+		assert.Nil(t, err)
+		store.StoreBlockchain = &store.Store{Name: "blockchain", Opened: true, DB: importDB}
+		return chain.ImportSnapshot(path, hashes[snapshotTestTrusted])
+	}
+
+	//the state is rebuilt and rolled back to the trusted block
+	assert.Nil(t, importSnapshot(path))
+	assert.Nil(t, store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		chainData := &BlockchainData{}
+		assert.Nil(t, chainData.loadBlockchainInfo(reader, snapshotTestTrusted))
+
+		dataStorage := data_storage.NewDataStorage(reader)
+		assert.False(t, dataStorage.IsStateTreeOutdated())
+		assert.Equal(t, chainData.StateRoot, dataStorage.StateTree.Root())
+
+		assert.Equal(t, snapshotTestTrusted, dataStorage.Regs.Count)
+		for i := uint64(0); i < snapshotTestTrusted; i++ {
+			key, err := dataStorage.Regs.GetKeyByIndex(i)
+			assert.Nil(t, err)
+			assert.Equal(t, publicKeys[i], key)
+		}
+
+		conditionalPayments, err := dataStorage.ConditionalPaymentsCollection.GetMap(10)
+		assert.Nil(t, err)
+		assert.Equal(t, snapshotTestTrusted, conditionalPayments.Count)
+
+		assert.Nil(t, reader.Get("block_ByHash"+string(hashes[snapshotTestTrusted])))
+		assert.NotNil(t, reader.Get("block_ByHash"+string(hashes[snapshotTestTrusted-1])))
+
+		reader.Iterate("", "", 0, func(key string, value []byte) bool {
+			assert.False(t, strings.Contains(key, ":transitions:"))
+			return true
+		})
+
+		return
+	}))
+
+	//the elements and the indexes are authenticated by the state root
+	tamperedPath := filepath.Join(t.TempDir(), "tampered")
+	rewriteSnapshot(t, path, tamperedPath, func(key string, value []byte) []byte {
+		if strings.HasPrefix(key, "conditionalPayments_10:map:") {
+			condPayment := conditional_payment.NewConditionalPayment(nil, 0, 10)
+			assert.Nil(t, condPayment.Deserialize(advanced_buffers.NewBufferReader(value)))
+			condPayment.Hashlock = cryptography.RandomHash()
+			return helpers.SerializeToBytes(condPayment)
+		}
+		return value
+	}, nil)
+	assert.EqualError(t, importSnapshot(tamperedPath), "Snapshot state root is not matching the chain info")
+
+	rewriteSnapshot(t, path, tamperedPath, func(key string, value []byte) []byte {
+		if key == "registrations:listKeys:"+string(publicKeys[0]) {
+			return []byte("1")
+		} else if key == "registrations:listKeys:"+string(publicKeys[1]) {
+			return []byte("0")
+		}
+		return value
+	}, nil)
+	assert.EqualError(t, importSnapshot(tamperedPath), "Snapshot state root is not matching the chain info")
+
+	rewriteSnapshot(t, path, tamperedPath, func(key string, value []byte) []byte {
+		return value
+	}, map[string][]byte{"assets:tickers:used:FAKE": {1}})
+	assert.EqualError(t, importSnapshot(tamperedPath), "Snapshot contains a key that can't be imported")
+
+	//the blocks of mainnet and testnet don't include the StateRoot
+	config.FORK_HEIGHT_STATE_ROOT = math.MaxUint64
+	assert.NotNil(t, importSnapshot(path))
+}
@@ -7,6 +7,7 @@ import (
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/data_storage"
 	"pandora-pay/config"
+	"pandora-pay/gui"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/msgpack"
 	"pandora-pay/store"
@@ -213,6 +214,27 @@ func (chain *Blockchain) saveBlockchain() error {
 	})
 }
 
+// the state tree is rebuilt when it is missing or it was built by an older version, otherwise the StateRoot would be different
+func (chain *Blockchain) rebuildStateTree() error {
+
+	return store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		dataStorage := data_storage.NewDataStorage(writer)
+		if !dataStorage.IsStateTreeOutdated() {
+			return
+		}
+
+		gui.GUI.Info("Rebuilding the state tree")
+		if err = dataStorage.RebuildStateTree(); err != nil {
+			return
+		}
+
+		chainData := chain.GetChainData()
+		chainData.StateRoot = dataStorage.StateTree.Root()
+		return chainData.saveBlockchain(writer)
+	})
+}
+
 func (chain *Blockchain) loadBlockchain() error {
 
 	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
@@ -229,6 +251,10 @@ func (chain *Blockchain) loadBlockchain() error {
 		}
 		chain.ChainData.Store(chainData)
 
+		if data := reader.Get(snapshotHeightKey); data != nil {
+			chain.snapshotHeight, _ = binary.Uvarint(data)
+		}
+
 		return
 	})
 
@@ -10,12 +10,22 @@ import (
 	"time"
 )
 
+// BlockchainSyncDownload is the progress of the headers-first download of a fork
+type BlockchainSyncDownload struct {
+	Start   uint64 `json:"start" msgpack:"start"`     //first height which is downloaded
+	Target  uint64 `json:"target" msgpack:"target"`   //end of the fork
+	Headers uint64 `json:"headers" msgpack:"headers"` //headers validated up to this height
+	Blocks  uint64 `json:"blocks" msgpack:"blocks"`   //blocks downloaded up to this height
+	Peers   int    `json:"peers" msgpack:"peers"`     //peers used to download the blocks
+}
+
 type BlockchainSyncData struct {
-	SyncTime                      uint64 `json:"syncTime" msgpack:"syncTime" `
-	BlocksChangedLastInterval     uint32 `json:"blocksChangedLastInterval" msgpack:"blocksChangedLastInterval"`
-	BlocksChangedPreviousInterval uint32 `json:"blocksChangedPreviousInterval" msgpack:"blocksChangedPreviousInterval"`
-	Sync                          bool   `json:"sync" msgpack:"sync" `
-	Started                       bool   `json:"started" msgpack:"started" `
+	SyncTime                      uint64                  `json:"syncTime" msgpack:"syncTime" `
+	BlocksChangedLastInterval     uint32                  `json:"blocksChangedLastInterval" msgpack:"blocksChangedLastInterval"`
+	BlocksChangedPreviousInterval uint32                  `json:"blocksChangedPreviousInterval" msgpack:"blocksChangedPreviousInterval"`
+	Sync                          bool                    `json:"sync" msgpack:"sync" `
+	Started                       bool                    `json:"started" msgpack:"started" `
+	Download                      *BlockchainSyncDownload `json:"download,omitempty" msgpack:"download,omitempty"`
 }
 
 type BlockchainSync struct {
@@ -40,6 +50,7 @@ func (self *BlockchainSync) AddBlocksChanged(blocks uint32, propagateNotificatio
 		BlocksChangedPreviousInterval: chainSyncData.BlocksChangedPreviousInterval,
 		BlocksChangedLastInterval:     chainSyncData.BlocksChangedLastInterval + blocks,
 		Started:                       chainSyncData.Started,
+		Download:                      chainSyncData.Download,
 	}
 
 	if newChainSyncData.BlocksChangedLastInterval < 3 {
@@ -58,6 +69,23 @@ func (self *BlockchainSync) AddBlocksChanged(blocks uint32, propagateNotificatio
 	return newChainSyncData
 }
 
+// UpdateDownload stores the progress of the fork download. A nil download means there is no download in progress
+func (self *BlockchainSync) UpdateDownload(download *BlockchainSyncDownload) {
+
+	chainSyncData := self.syncData.Load()
+	if chainSyncData.Download == nil && download == nil {
+		return
+	}
+
+	newChainSyncData := *chainSyncData
+	newChainSyncData.Download = download
+
+	self.syncData.Store(&newChainSyncData)
+	self.UpdateSyncMulticast.Broadcast(&newChainSyncData)
+
+	self.updateCn <- &newChainSyncData
+}
+
 func (self *BlockchainSync) resetBlocksChanged(propagateNotification bool) *BlockchainSyncData {
 
 	chainSyncData := self.syncData.Load()
@@ -65,6 +93,7 @@ func (self *BlockchainSync) resetBlocksChanged(propagateNotification bool) *Bloc
 	newChainSyncData := &BlockchainSyncData{
 		BlocksChangedPreviousInterval: chainSyncData.BlocksChangedLastInterval,
 		Started:                       chainSyncData.Started,
+		Download:                      chainSyncData.Download,
 	}
 
 	if chainSyncData.BlocksChangedLastInterval < 5 && (chainSyncData.Started || chainSyncData.BlocksChangedPreviousInterval < 5) {
@@ -101,6 +130,12 @@ func (self *BlockchainSync) start() {
 				return
 			}
 
+			if download := chainSyncData.Download; download != nil {
+				gui.GUI.Info2Update("Download", fmt.Sprintf("%d/%d headers %d peers %d", download.Blocks, download.Target, download.Headers, download.Peers))
+			} else {
+				gui.GUI.Info2Update("Download", "")
+			}
+
 			if chainSyncData.SyncTime != 0 {
 				gui.GUI.Info2Update("Sync", fmt.Sprintf("%s %d", time.Unix(int64(chainSyncData.SyncTime), 0).Format("15:04:05"), chainSyncData.BlocksChangedLastInterval))
 			} else {
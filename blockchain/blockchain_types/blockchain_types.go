@@ -31,6 +31,7 @@ type MempoolTransactionUpdate struct {
 	Inserted                         bool
 	Tx                               *transaction.Transaction
 	IncludedInBlockchainNotification bool
+	EvictedReason                    string
 	Keys                             map[string]bool
 }
 
@@ -43,6 +44,17 @@ type BlockchainUpdates struct {
 	BlockHash      []byte
 }
 
+type BlockchainChainUpdate struct {
+	Height               uint64
+	Hash                 []byte
+	PrevHash             []byte
+	KernelHash           []byte
+	Timestamp            uint64
+	TransactionsCount    uint64
+	RemovedBlocksHeights []uint64 //heights replaced by a reorg
+	InsertedBlocks       []*block_complete.BlockComplete
+}
+
 type BlockchainSolutionAnswer struct {
 	Err             error
 	ChainKernelHash []byte
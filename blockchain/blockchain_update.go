@@ -29,6 +29,7 @@ type BlockchainUpdate struct {
 	insertedTxs            map[string]*transaction.Transaction
 	insertedTxsList        []*transaction.Transaction
 	insertedBlocks         []*block_complete.BlockComplete
+	removedBlocksHeights   []uint64
 	calledByForging        bool
 	exceptSocketUUID       advanced_connection_types.UUID
 }
@@ -2,6 +2,7 @@ package blockchain
 
 import (
 	"bytes"
+	"pandora-pay/blockchain/blockchain_types"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/helpers/recovery"
@@ -18,6 +19,17 @@ func (queue *BlockchainUpdatesQueue) processBlockchainUpdateNotifications() {
 			update := <-updatesNotificationsCn
 
 			queue.chain.UpdateSocketsSubscriptionsNotifications.Broadcast(update.dataStorage)
+
+			queue.chain.UpdateSocketsSubscriptionsChain.Broadcast(&blockchain_types.BlockchainChainUpdate{
+				update.newChainData.Height,
+				update.newChainData.Hash,
+				update.newChainData.PrevHash,
+				update.newChainData.KernelHash,
+				update.newChainData.Timestamp,
+				update.newChainData.TransactionsCount,
+				update.removedBlocksHeights,
+				update.insertedBlocks,
+			})
 		}
 
 	})
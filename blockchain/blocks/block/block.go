@@ -1,6 +1,7 @@
 package block
 
 import (
+	"pandora-pay/config"
 	"pandora-pay/cryptography"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
@@ -9,6 +10,7 @@ import (
 type Block struct {
 	*BlockHeader
 	MerkleHash     []byte      `json:"merkleHash" msgpack:"merkleHash"`          //32 byte
+	StateRoot      []byte      `json:"stateRoot" msgpack:"stateRoot"`            //32 byte, root of the state on top of which the block is included. Only from config.FORK_HEIGHT_STATE_ROOT
 	PrevHash       []byte      `json:"prevHash"  msgpack:"prevHash"`             //32 byte
 	PrevKernelHash []byte      `json:"prevKernelHash"  msgpack:"prevKernelHash"` //32 byte
 	Timestamp      uint64      `json:"timestamp" msgpack:"timestamp"`
@@ -57,6 +59,9 @@ func (blk *Block) AdvancedSerialization(w *advanced_buffers.BufferWriter, kernel
 
 	if !kernelHash {
 		w.Write(blk.MerkleHash)
+		if blk.Height >= config.FORK_HEIGHT_STATE_ROOT {
+			w.Write(blk.StateRoot)
+		}
 		w.Write(blk.PrevHash)
 	}
 
@@ -96,6 +101,11 @@ func (blk *Block) Deserialize(r *advanced_buffers.BufferReader) (err error) {
 	if blk.MerkleHash, err = r.ReadHash(); err != nil {
 		return
 	}
+	if blk.Height >= config.FORK_HEIGHT_STATE_ROOT {
+		if blk.StateRoot, err = r.ReadHash(); err != nil {
+			return
+		}
+	}
 	if blk.PrevHash, err = r.ReadHash(); err != nil {
 		return
 	}
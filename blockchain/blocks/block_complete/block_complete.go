@@ -45,6 +45,9 @@ func (blkComplete *BlockComplete) MerkleHash() []byte {
 		for i, tx := range blkComplete.Txs {
 			hashes[i] = tx.Bloom.Hash
 		}
+		if blkComplete.Block.Height < config.FORK_HEIGHT_MERKLE_TREE {
+			return merkle_tree.MerkleRootLegacy(hashes)
+		}
 		return merkle_tree.MerkleRoot(hashes)
 	} else {
 		return cryptography.SHA3([]byte{})
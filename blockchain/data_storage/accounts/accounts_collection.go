@@ -5,14 +5,16 @@ import (
 	"pandora-pay/config/config_coins"
 	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/store/hash_map"
+	"pandora-pay/store/sparse_merkle_tree"
 	"pandora-pay/store/store_db/store_db_interface"
 	"strconv"
 )
 
 type AccountsCollection struct {
-	tx   store_db_interface.StoreDBTransactionInterface
-	maps map[string]*Accounts
-	list []hash_map.HashMapInterface
+	tx        store_db_interface.StoreDBTransactionInterface
+	maps      map[string]*Accounts
+	list      []hash_map.HashMapInterface
+	StateTree *sparse_merkle_tree.SparseMerkleTree
 }
 
 func (this *AccountsCollection) SetTx(tx store_db_interface.StoreDBTransactionInterface) {
@@ -39,6 +41,7 @@ func (this *AccountsCollection) GetMap(assetId []byte) (*Accounts, error) {
 		if accs, err = NewAccounts(this.tx, assetId); err != nil {
 			return nil, err
 		}
+		accs.HashMap.StateTree = this.StateTree
 		this.list = append(this.list, accs.HashMap)
 		this.maps[string(assetId)] = accs
 	}
@@ -88,5 +91,6 @@ func NewAccountsCollection(tx store_db_interface.StoreDBTransactionInterface) *A
 		tx,
 		make(map[string]*Accounts),
 		make([]hash_map.HashMapInterface, 0),
+		nil,
 	}
 }
@@ -19,6 +19,13 @@ var regexAssetName = regexp.MustCompile("^([a-zA-Z0-9]+ )+[a-zA-Z0-9]+$|^[a-zA-Z
 var regexAssetTicker = regexp.MustCompile("^[A-Z0-9]+$") // only lowercase ascii is allowed. No space allowed
 var regexAssetDescription = regexp.MustCompile("[\\w|\\W]+")
 
+// the assets created before the pause and freeze payloads don't serialize Paused and Frozen.
+// An asset is upgraded to ASSET_VERSION_PAUSE_FREEZE only when it is paused or frozen, so the existing assets keep their serialization and hash
+const (
+	ASSET_VERSION_INITIAL      uint64 = 0
+	ASSET_VERSION_PAUSE_FREEZE uint64 = 1
+)
+
 type Asset struct {
 	PublicKeyHash            []byte `json:"-" msgpack:"-"` //hashmap key
 	Index                    uint64 `json:"-" msgpack:"-"` //hashMap index
@@ -30,6 +37,8 @@ type Asset struct {
 	CanChangeSupplyPublicKey bool   `json:"canChangeSupplyPublicKey,omitempty" msgpack:"canChangeSupplyPublicKey,omitempty"` //can change supply key
 	CanPause                 bool   `json:"canPause,omitempty" msgpack:"canPause,omitempty"`                                 //can pause (suspend transactions)
 	CanFreeze                bool   `json:"canFreeze,omitempty" msgpack:"canFreeze,omitempty"`                               //freeze supply changes
+	Paused                   bool   `json:"paused,omitempty" msgpack:"paused,omitempty"`                                     //transactions are suspended
+	Frozen                   bool   `json:"frozen,omitempty" msgpack:"frozen,omitempty"`                                     //supply changes are disabled forever
 	DecimalSeparator         byte   `json:"decimalSeparator,omitempty" msgpack:"decimalSeparator,omitempty"`
 	MaxSupply                uint64 `json:"maxSupply,omitempty" msgpack:"maxSupply,omitempty"`
 	Supply                   uint64 `json:"supply,omitempty" msgpack:"supply,omitempty"`
@@ -62,6 +71,12 @@ func (asset *Asset) GetIndex() uint64 {
 }
 
 func (asset *Asset) Validate() error {
+	if asset.Version > ASSET_VERSION_PAUSE_FREEZE {
+		return errors.New("asset version is invalid")
+	}
+	if asset.Version == ASSET_VERSION_INITIAL && (asset.Paused || asset.Frozen) {
+		return errors.New("asset version doesn't support paused or frozen")
+	}
 	if asset.DecimalSeparator > config_assets.ASSETS_DECIMAL_SEPARATOR_MAX_BYTE {
 		return errors.New("asset decimal separator is invalid")
 	}
@@ -153,6 +168,10 @@ func (asset *Asset) AddSupply(sign bool, amount uint64) error {
 		return errors.New("BURN PUBLIC KEY")
 	}
 
+	if asset.Frozen {
+		return errors.New("Supply is frozen")
+	}
+
 	if sign {
 		if !asset.CanMint {
 			return errors.New("Can't mint")
@@ -172,6 +191,82 @@ func (asset *Asset) AddSupply(sign bool, amount uint64) error {
 	return helpers.SafeUint64Sub(&asset.Supply, amount)
 }
 
+func (asset *Asset) SetPaused(paused bool) error {
+	if !asset.CanPause {
+		return errors.New("Can't pause")
+	}
+	if asset.Paused == paused {
+		return errors.New("Asset paused is already set")
+	}
+	asset.Paused = paused
+	asset.Version = ASSET_VERSION_PAUSE_FREEZE
+	return nil
+}
+
+func (asset *Asset) Freeze() error {
+	if !asset.CanFreeze {
+		return errors.New("Can't freeze")
+	}
+	if asset.Frozen {
+		return errors.New("Asset is already frozen")
+	}
+	asset.Frozen = true
+	asset.Version = ASSET_VERSION_PAUSE_FREEZE
+	return nil
+}
+
+func (asset *Asset) SetDescription(description string) error {
+	if !asset.CanUpgrade {
+		return errors.New("Can't upgrade")
+	}
+	asset.Description = description
+	return nil
+}
+
+func (asset *Asset) SetData(data []byte) error {
+	if !asset.CanUpgrade {
+		return errors.New("Can't upgrade")
+	}
+	asset.Data = data
+	return nil
+}
+
+func (asset *Asset) SetMaxSupply(maxSupply uint64) error {
+	if !asset.CanUpgrade {
+		return errors.New("Can't upgrade")
+	}
+	if asset.Frozen {
+		return errors.New("Supply is frozen")
+	}
+	if maxSupply < asset.Supply {
+		return errors.New("MaxSupply can not be less than the current supply")
+	}
+	asset.MaxSupply = maxSupply
+	return nil
+}
+
+func (asset *Asset) SetUpdatePublicKey(publicKey []byte) error {
+	if !asset.CanChangeUpdatePublicKey {
+		return errors.New("Can't change update public key")
+	}
+	if len(publicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid update public key")
+	}
+	asset.UpdatePublicKey = publicKey
+	return nil
+}
+
+func (asset *Asset) SetSupplyPublicKey(publicKey []byte) error {
+	if !asset.CanChangeSupplyPublicKey {
+		return errors.New("Can't change supply public key")
+	}
+	if len(publicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid supply public key")
+	}
+	asset.SupplyPublicKey = publicKey
+	return nil
+}
+
 func (asset *Asset) Serialize(w *advanced_buffers.BufferWriter) {
 
 	w.WriteUvarint(asset.Version)
@@ -183,6 +278,10 @@ func (asset *Asset) Serialize(w *advanced_buffers.BufferWriter) {
 	w.WriteBool(asset.CanChangeSupplyPublicKey)
 	w.WriteBool(asset.CanPause)
 	w.WriteBool(asset.CanFreeze)
+	if asset.Version >= ASSET_VERSION_PAUSE_FREEZE {
+		w.WriteBool(asset.Paused)
+		w.WriteBool(asset.Frozen)
+	}
 	w.WriteByte(asset.DecimalSeparator)
 
 	w.WriteUvarint(asset.MaxSupply)
@@ -210,6 +309,9 @@ func (asset *Asset) Deserialize(r *advanced_buffers.BufferReader) (err error) {
 	if asset.Version, err = r.ReadUvarint(); err != nil {
 		return
 	}
+	if asset.Version > ASSET_VERSION_PAUSE_FREEZE {
+		return errors.New("Asset version is invalid")
+	}
 	if asset.CanUpgrade, err = r.ReadBool(); err != nil {
 		return
 	}
@@ -231,6 +333,14 @@ func (asset *Asset) Deserialize(r *advanced_buffers.BufferReader) (err error) {
 	if asset.CanFreeze, err = r.ReadBool(); err != nil {
 		return
 	}
+	if asset.Version >= ASSET_VERSION_PAUSE_FREEZE {
+		if asset.Paused, err = r.ReadBool(); err != nil {
+			return
+		}
+		if asset.Frozen, err = r.ReadBool(); err != nil {
+			return
+		}
+	}
 	if asset.DecimalSeparator, err = r.ReadByte(); err != nil {
 		return
 	}
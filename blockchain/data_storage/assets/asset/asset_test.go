@@ -0,0 +1,48 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"testing"
+)
+
+func serializeAsset(ast *Asset) []byte {
+	w := advanced_buffers.NewBufferWriter()
+	ast.Serialize(w)
+	return w.Bytes()
+}
+
+func TestAssetSerializationVersion(t *testing.T) {
+
+	ast := NewAsset(helpers.RandomBytes(cryptography.PublicKeyHashSize), 0)
+	ast.CanPause = true
+	ast.CanFreeze = true
+	ast.UpdatePublicKey = helpers.RandomBytes(cryptography.PublicKeySize)
+	ast.SupplyPublicKey = helpers.RandomBytes(cryptography.PublicKeySize)
+	ast.Name = "TEST"
+	ast.Ticker = "TST"
+
+	//the initial version doesn't contain Paused and Frozen
+	initial := serializeAsset(ast)
+	ast.Paused = true
+	assert.Equal(t, initial, serializeAsset(ast))
+	ast.Paused = false
+
+	ast2 := NewAsset(ast.PublicKeyHash, 0)
+	assert.NoError(t, ast2.Deserialize(advanced_buffers.NewBufferReader(initial)))
+	assert.Equal(t, ASSET_VERSION_INITIAL, ast2.Version)
+
+	assert.NoError(t, ast.SetPaused(true))
+	assert.NoError(t, ast.Freeze())
+	assert.Equal(t, ASSET_VERSION_PAUSE_FREEZE, ast.Version)
+
+	ast3 := NewAsset(ast.PublicKeyHash, 0)
+	assert.NoError(t, ast3.Deserialize(advanced_buffers.NewBufferReader(serializeAsset(ast))))
+	assert.True(t, ast3.Paused)
+	assert.True(t, ast3.Frozen)
+
+	ast.Version = 2
+	assert.Error(t, NewAsset(ast.PublicKeyHash, 0).Deserialize(advanced_buffers.NewBufferReader(serializeAsset(ast))))
+}
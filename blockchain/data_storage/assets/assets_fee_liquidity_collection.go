@@ -7,6 +7,7 @@ import (
 	"pandora-pay/config/config_coins"
 	"pandora-pay/store/hash_map"
 	"pandora-pay/store/min_max_heap"
+	"pandora-pay/store/sparse_merkle_tree"
 	"pandora-pay/store/store_db/store_db_interface"
 )
 
@@ -16,6 +17,7 @@ type AssetsFeeLiquidityCollection struct {
 	tx                store_db_interface.StoreDBTransactionInterface
 	liquidityMaxHeaps map[string]*min_max_heap.HeapStoreHashMap
 	listMaps          []hash_map.HashMapInterface
+	StateTree         *sparse_merkle_tree.SparseMerkleTree
 }
 
 func (collection *AssetsFeeLiquidityCollection) GetAllMaps() map[string]*min_max_heap.HeapStoreHashMap {
@@ -41,6 +43,8 @@ func (collection *AssetsFeeLiquidityCollection) GetMaxHeap(assetId []byte) (*min
 	}
 
 	maxheap := min_max_heap.NewMaxHeapStoreHashMap(collection.tx, string(assetId))
+	maxheap.HashMap.StateTree = collection.StateTree
+	maxheap.DictMap.StateTree = collection.StateTree
 	collection.listMaps = append(collection.listMaps, maxheap.HashMap, maxheap.DictMap)
 
 	collection.liquidityMaxHeaps[string(assetId)] = maxheap
@@ -90,5 +94,6 @@ func NewAssetsFeeLiquidityCollection(tx store_db_interface.StoreDBTransactionInt
 		tx,
 		make(map[string]*min_max_heap.HeapStoreHashMap),
 		make([]hash_map.HashMapInterface, 0),
+		nil,
 	}
 }
@@ -1,6 +1,7 @@
 package conditional_payment
 
 import (
+	"crypto/sha256"
 	"errors"
 	"pandora-pay/cryptography"
 	"pandora-pay/helpers/advanced_buffers"
@@ -22,6 +23,13 @@ type ConditionalPayment struct {
 	SenderAmounts      [][]byte `json:"senderAmounts" msgpack:"senderAmounts"`
 	MultisigThreshold  byte     `json:"multisigThreshold" msgpack:"multisigThreshold"`
 	MultisigPublicKeys [][]byte `json:"multisigPublicKeys" msgpack:"multisigPublicKeys"`
+	Hashlock           []byte   `json:"hashlock,omitempty" msgpack:"hashlock,omitempty"` //only when MultisigThreshold is zero
+}
+
+// ComputeHashlock is SHA256 to be compatible with the HTLC of other chains
+func ComputeHashlock(preimage []byte) []byte {
+	hash := sha256.Sum256(preimage)
+	return hash[:]
 }
 
 func (this *ConditionalPayment) IsDeletable() bool {
@@ -60,7 +68,13 @@ func (this *ConditionalPayment) Validate() error {
 			return errors.New("PendingStake PublicKey size is invalid")
 		}
 	}
-	if this.MultisigThreshold == 0 || int(this.MultisigThreshold) > len(this.MultisigPublicKeys) {
+	if this.MultisigThreshold == 0 {
+		if len(this.Hashlock) != cryptography.HashSize || len(this.MultisigPublicKeys) != 0 {
+			return errors.New("Invalid hashlock")
+		}
+		return nil
+	}
+	if int(this.MultisigThreshold) > len(this.MultisigPublicKeys) {
 		return errors.New("Invali Multisig threshold")
 	}
 	unique := make(map[string]bool)
@@ -95,6 +109,10 @@ func (this *ConditionalPayment) Serialize(w *advanced_buffers.BufferWriter) {
 			w.Write(p)
 		}
 		w.WriteByte(this.MultisigThreshold)
+		if this.MultisigThreshold == 0 {
+			w.Write(this.Hashlock)
+			return
+		}
 		w.WriteByte(byte(len(this.MultisigPublicKeys)))
 		for _, pb := range this.MultisigPublicKeys {
 			w.Write(pb)
@@ -156,6 +174,10 @@ func (this *ConditionalPayment) Deserialize(r *advanced_buffers.BufferReader) (e
 		if this.MultisigThreshold, err = r.ReadByte(); err != nil {
 			return
 		}
+		if this.MultisigThreshold == 0 {
+			this.Hashlock, err = r.ReadBytes(cryptography.HashSize)
+			return
+		}
 		var m byte
 		if m, err = r.ReadByte(); err != nil {
 			return
@@ -179,6 +201,6 @@ func NewConditionalPayment(key []byte, index uint64, blockHeight uint64) *Condit
 		index,
 		0,
 		nil, 0,
-		false, nil, false, nil, nil, nil, nil, 0, nil,
+		false, nil, false, nil, nil, nil, nil, 0, nil, nil,
 	}
 }
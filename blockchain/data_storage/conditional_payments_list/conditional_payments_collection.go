@@ -2,13 +2,15 @@ package conditional_payments_list
 
 import (
 	"pandora-pay/store/hash_map"
+	"pandora-pay/store/sparse_merkle_tree"
 	"pandora-pay/store/store_db/store_db_interface"
 )
 
 type ConditionalPaymentsCollection struct {
-	tx   store_db_interface.StoreDBTransactionInterface
-	maps map[string]*ConditionalPaymentsHashMap
-	list []hash_map.HashMapInterface
+	tx        store_db_interface.StoreDBTransactionInterface
+	maps      map[string]*ConditionalPaymentsHashMap
+	list      []hash_map.HashMapInterface
+	StateTree *sparse_merkle_tree.SparseMerkleTree
 }
 
 func (collection *ConditionalPaymentsCollection) SetTx(tx store_db_interface.StoreDBTransactionInterface) {
@@ -28,6 +30,7 @@ func (this *ConditionalPaymentsCollection) GetMap(blockHeight uint64) (*Conditio
 	it := this.maps[string(blockHeight)]
 	if it == nil {
 		it = NewConditionalPaymentsHashMap(this.tx, blockHeight)
+		it.HashMap.StateTree = this.StateTree
 		this.list = append(this.list, it.HashMap)
 		this.maps[string(blockHeight)] = it
 	}
@@ -40,5 +43,6 @@ func NewConditionalPaymentsCollection(tx store_db_interface.StoreDBTransactionIn
 		tx,
 		make(map[string]*ConditionalPaymentsHashMap),
 		make([]hash_map.HashMapInterface, 0),
+		nil,
 	}
 }
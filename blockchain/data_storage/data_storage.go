@@ -13,11 +13,13 @@ import (
 	"pandora-pay/blockchain/data_storage/plain_accounts"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/asset_fee_liquidity"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account_multisig"
 	"pandora-pay/blockchain/data_storage/registrations"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
 	"pandora-pay/config/config_asset_fee"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography/crypto"
+	"pandora-pay/store/sparse_merkle_tree"
 	"pandora-pay/store/store_db/store_db_interface"
 	"strconv"
 )
@@ -26,11 +28,13 @@ type DataStorage struct {
 	DBTx                          store_db_interface.StoreDBTransactionInterface
 	Regs                          *registrations.Registrations
 	PlainAccs                     *plain_accounts.PlainAccounts
+	PlainAccsMultisig             *plain_accounts.PlainAccountsMultisig
 	AccsCollection                *accounts.AccountsCollection
 	PendingStakes                 *pending_stakes_list.PendingStakesList
 	ConditionalPaymentsCollection *conditional_payments_list.ConditionalPaymentsCollection
 	Asts                          *assets.Assets
 	AstsFeeLiquidityCollection    *assets.AssetsFeeLiquidityCollection
+	StateTree                     *sparse_merkle_tree.SparseMerkleTree
 }
 
 func (dataStorage *DataStorage) GetOrCreateAccount(assetId, publicKey []byte, validateRegistration bool) (*accounts.Accounts, *account.Account, error) {
@@ -126,6 +130,38 @@ func (dataStorage *DataStorage) CreatePlainAccount(publicKey []byte, validateReg
 	return dataStorage.PlainAccs.CreateNewPlainAccount(publicKey)
 }
 
+func (dataStorage *DataStorage) CreatePlainAccountMultisig(threshold byte, publicKeys [][]byte) (*plain_account_multisig.PlainAccountMultisig, error) {
+
+	if err := plain_account_multisig.ValidateKeys(threshold, publicKeys); err != nil {
+		return nil, err
+	}
+
+	key := plain_account_multisig.ComputeMultisigKey(threshold, publicKeys)
+	exists, err := dataStorage.PlainAccsMultisig.Exists(string(key))
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("Multisig was already registered")
+	}
+
+	return dataStorage.PlainAccsMultisig.CreateNewPlainAccountMultisig(threshold, publicKeys)
+}
+
+// VerifyPlainAccountMultisig checks that the signers of a multisig plain account reach its threshold
+func (dataStorage *DataStorage) VerifyPlainAccountMultisig(key []byte, signers [][]byte) error {
+
+	multisig, err := dataStorage.PlainAccsMultisig.Get(string(key))
+	if err != nil {
+		return err
+	}
+	if multisig == nil {
+		return errors.New("Multisig was not registered")
+	}
+
+	return multisig.VerifySigners(signers)
+}
+
 func (dataStorage *DataStorage) CreateRegistration(publicKey []byte, staked bool, spendPublicKey []byte) (*registration.Registration, error) {
 
 	exists, err := dataStorage.PlainAccs.Exists(string(publicKey))
@@ -211,11 +247,12 @@ func (dataStorage *DataStorage) ProcessPendingStakes(blockHeight uint64) error {
 		}
 	}
 
+	dataStorage.PendingStakes.Processed = append(dataStorage.PendingStakes.Processed, pendingStakes)
 	dataStorage.PendingStakes.Delete(strconv.FormatUint(blockHeight, 10))
 	return nil
 }
 
-func (dataStorage *DataStorage) AddConditionalPayment(blockHeight uint64, txId []byte, payloadIndex byte, asset []byte, defaultResolution bool, parity bool, publicKeyList [][]byte, echangesAll []*crypto.ElGamal, multisigThreshold byte, multisigPublicKeys [][]byte) error {
+func (dataStorage *DataStorage) AddConditionalPayment(blockHeight uint64, txId []byte, payloadIndex byte, asset []byte, defaultResolution bool, parity bool, publicKeyList [][]byte, echangesAll []*crypto.ElGamal, multisigThreshold byte, multisigPublicKeys [][]byte, hashlock []byte) error {
 
 	for i, publicKey := range publicKeyList {
 		reg, err := dataStorage.Regs.Get(string(publicKey))
@@ -269,6 +306,7 @@ func (dataStorage *DataStorage) AddConditionalPayment(blockHeight uint64, txId [
 
 	condPayment.MultisigThreshold = multisigThreshold
 	condPayment.MultisigPublicKeys = multisigPublicKeys
+	condPayment.Hashlock = hashlock
 
 	return conditionalPaymentsMap.Update(key, condPayment)
 }
@@ -402,12 +440,24 @@ func NewDataStorage(dbTx store_db_interface.StoreDBTransactionInterface) (out *D
 		dbTx,
 		registrations.NewRegistrations(dbTx),
 		plain_accounts.NewPlainAccounts(dbTx),
+		plain_accounts.NewPlainAccountsMultisig(dbTx),
 		accounts.NewAccountsCollection(dbTx),
 		pending_stakes_list.NewPendingStakesList(dbTx),
 		conditional_payments_list.NewConditionalPaymentsCollection(dbTx),
 		assets.NewAssets(dbTx),
 		assets.NewAssetsFeeLiquidityCollection(dbTx),
-	}
+		sparse_merkle_tree.NewSparseMerkleTree(dbTx, "stateTree"),
+	}
+
+	//all the hash maps are authenticated by the state root
+	out.Regs.HashMap.StateTree = out.StateTree
+	out.PlainAccs.HashMap.StateTree = out.StateTree
+	out.PlainAccsMultisig.HashMap.StateTree = out.StateTree
+	out.AccsCollection.StateTree = out.StateTree
+	out.PendingStakes.HashMap.StateTree = out.StateTree
+	out.ConditionalPaymentsCollection.StateTree = out.StateTree
+	out.Asts.HashMap.StateTree = out.StateTree
+	out.AstsFeeLiquidityCollection.StateTree = out.StateTree
 
 	return
 }
@@ -17,6 +17,7 @@ func (dataStorage *DataStorage) GetListWithoutCollections() (list []hash_map.Has
 	return []hash_map.HashMapInterface{
 		dataStorage.Regs.HashMap,
 		dataStorage.PlainAccs.HashMap,
+		dataStorage.PlainAccsMultisig.HashMap,
 		dataStorage.PendingStakes.HashMap,
 		dataStorage.Asts.HashMap,
 	}
@@ -27,6 +28,7 @@ func (dataStorage *DataStorage) GetList(computeChangesSize bool) (list []hash_ma
 	list = []hash_map.HashMapInterface{
 		dataStorage.Regs.HashMap,
 		dataStorage.PlainAccs.HashMap,
+		dataStorage.PlainAccsMultisig.HashMap,
 		dataStorage.PendingStakes.HashMap,
 		dataStorage.Asts.HashMap,
 	}
@@ -82,6 +84,7 @@ func (dataStorage *DataStorage) SetTx(dbTx store_db_interface.StoreDBTransaction
 	dataStorage.AccsCollection.SetTx(dbTx)
 	dataStorage.AstsFeeLiquidityCollection.SetTx(dbTx)
 	dataStorage.ConditionalPaymentsCollection.SetTx(dbTx)
+	dataStorage.StateTree.SetTx(dbTx)
 }
 
 func (dataStorage *DataStorage) WriteTransitionalChangesToStore(prefix string) (err error) {
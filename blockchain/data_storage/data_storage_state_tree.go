@@ -0,0 +1,205 @@
+package data_storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"pandora-pay/blockchain/data_storage/accounts"
+	"pandora-pay/blockchain/data_storage/conditional_payments_list"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/store/hash_map"
+	"strconv"
+	"strings"
+)
+
+// STATE_TREE_VERSION is increased every time the elements authenticated by the state tree change
+const STATE_TREE_VERSION = uint64(1)
+
+const stateTreeVersionKey = "dataStorage:stateTreeVersion"
+
+//hash maps with a fixed name. The accounts and the conditional payments are stored in a hash map for each asset and each height, the fee liquidities in a heap for each asset
+var stateHashMapNames = []string{"registrations", "plainAccs", "plainAccsMultisig", "pendingStakes", "assets"}
+
+// IsStateTreeOutdated returns true when the state tree is missing or it was built by an older version
+func (dataStorage *DataStorage) IsStateTreeOutdated() bool {
+	version, n := binary.Uvarint(dataStorage.DBTx.Get(stateTreeVersionKey))
+	return n <= 0 || version != STATE_TREE_VERSION
+}
+
+// RebuildStateTree removes the state tree and builds it again from all the committed hash maps
+func (dataStorage *DataStorage) RebuildStateTree() (err error) {
+
+	nodes := make([]string, 0)
+	dataStorage.DBTx.IteratePrefix("stateTree:", 0, func(key string, value []byte) bool {
+		nodes = append(nodes, key)
+		return true
+	})
+	for _, key := range nodes {
+		dataStorage.DBTx.Delete(key)
+	}
+
+	list := dataStorage.GetListWithoutCollections()
+
+	//the collections are found using the indexes of the accounts assets, the liquidities of the plain accounts and the conditional payments
+	accountsAssets := make(map[string]bool)
+	dataStorage.DBTx.IteratePrefix("accounts:assetByIndex:", 0, func(key string, value []byte) bool {
+		accountsAssets[string(value)] = true
+		return true
+	})
+	for assetId := range accountsAssets {
+		var accs *accounts.Accounts
+		if accs, err = dataStorage.AccsCollection.GetMap([]byte(assetId)); err != nil {
+			return
+		}
+		list = append(list, accs.HashMap)
+	}
+
+	liquidityAssets := make(map[string]bool)
+	for cursor := ""; ; {
+		_, plainAccs, next, err := dataStorage.PlainAccs.Iterate(cursor, 1000)
+		if err != nil {
+			return err
+		}
+		for _, plainAcc := range plainAccs {
+			for _, liquidity := range plainAcc.AssetFeeLiquidities.List {
+				liquidityAssets[string(liquidity.Asset)] = true
+			}
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	for assetId := range liquidityAssets {
+		maxHeap, err := dataStorage.AstsFeeLiquidityCollection.GetMaxHeap([]byte(assetId))
+		if err != nil {
+			return err
+		}
+		list = append(list, maxHeap.HashMap, maxHeap.DictMap)
+	}
+
+	conditionalPaymentsHeights := make(map[string]bool)
+	dataStorage.DBTx.IteratePrefix("conditionalPayments:all:", 0, func(key string, value []byte) bool {
+		conditionalPaymentsHeights[string(value)] = true
+		return true
+	})
+	for heightStr := range conditionalPaymentsHeights {
+		var height uint64
+		if height, err = strconv.ParseUint(heightStr, 10, 64); err != nil {
+			return
+		}
+		var conditionalPayments *conditional_payments_list.ConditionalPaymentsHashMap
+		if conditionalPayments, err = dataStorage.ConditionalPaymentsCollection.GetMap(height); err != nil {
+			return
+		}
+		list = append(list, conditionalPayments.HashMap)
+	}
+
+	for _, it := range list {
+		if err = it.RebuildStateTree(); err != nil {
+			return
+		}
+	}
+
+	dataStorage.SaveStateTreeVersion()
+	return
+}
+
+// SaveStateTreeVersion marks the state tree as built by the current version
+func (dataStorage *DataStorage) SaveStateTreeVersion() {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, STATE_TREE_VERSION)
+	dataStorage.DBTx.Put(stateTreeVersionKey, buf[:n])
+}
+
+// SplitStateKey splits a stored key of a hash map authenticated by the state tree into the name of the hash map and the rest of the key starting with ":"
+func SplitStateKey(key string) (name, suffix string, found bool) {
+
+	for _, name = range stateHashMapNames {
+		if strings.HasPrefix(key, name+":") {
+			return name, key[len(name):], true
+		}
+	}
+
+	if strings.HasPrefix(key, "accounts_") {
+		length := len("accounts_") + config_coins.ASSET_LENGTH
+		if len(key) > length && key[length] == ':' {
+			return key[:length], key[length:], true
+		}
+		return "", "", false
+	}
+
+	if strings.HasPrefix(key, "conditionalPayments_") {
+		index := strings.IndexByte(key, ':')
+		if index > len("conditionalPayments_") {
+			if _, err := strconv.ParseUint(key[len("conditionalPayments_"):index], 10, 64); err == nil {
+				return key[:index], key[index:], true
+			}
+		}
+		return "", "", false
+	}
+
+	//the heaps of the fee liquidities are named by the asset
+	if len(key) > config_coins.ASSET_LENGTH {
+		if key[config_coins.ASSET_LENGTH] == ':' {
+			return key[:config_coins.ASSET_LENGTH], key[config_coins.ASSET_LENGTH:], true
+		}
+		if strings.HasPrefix(key[config_coins.ASSET_LENGTH:], "_dict:") {
+			length := config_coins.ASSET_LENGTH + len("_dict")
+			return key[:length], key[length:], true
+		}
+	}
+
+	return "", "", false
+}
+
+// GetStateHashMap returns the hash map with a name returned by SplitStateKey
+func (dataStorage *DataStorage) GetStateHashMap(name string) (hash_map.HashMapInterface, error) {
+
+	switch name {
+	case "registrations":
+		return dataStorage.Regs.HashMap, nil
+	case "plainAccs":
+		return dataStorage.PlainAccs.HashMap, nil
+	case "plainAccsMultisig":
+		return dataStorage.PlainAccsMultisig.HashMap, nil
+	case "pendingStakes":
+		return dataStorage.PendingStakes.HashMap, nil
+	case "assets":
+		return dataStorage.Asts.HashMap, nil
+	}
+
+	if strings.HasPrefix(name, "accounts_") {
+		accs, err := dataStorage.AccsCollection.GetMap([]byte(name[len("accounts_"):]))
+		if err != nil {
+			return nil, err
+		}
+		return accs.HashMap, nil
+	}
+
+	if strings.HasPrefix(name, "conditionalPayments_") {
+		height, err := strconv.ParseUint(name[len("conditionalPayments_"):], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		conditionalPayments, err := dataStorage.ConditionalPaymentsCollection.GetMap(height)
+		if err != nil {
+			return nil, err
+		}
+		return conditionalPayments.HashMap, nil
+	}
+
+	if len(name) >= config_coins.ASSET_LENGTH {
+		maxHeap, err := dataStorage.AstsFeeLiquidityCollection.GetMaxHeap([]byte(name[:config_coins.ASSET_LENGTH]))
+		if err != nil {
+			return nil, err
+		}
+		if name[config_coins.ASSET_LENGTH:] == "_dict" {
+			return maxHeap.DictMap, nil
+		}
+		if len(name) == config_coins.ASSET_LENGTH {
+			return maxHeap.HashMap, nil
+		}
+	}
+
+	return nil, errors.New("HashMap not found")
+}
@@ -0,0 +1,102 @@
+package data_storage
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/blockchain/data_storage/conditional_payments_list/conditional_payment"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/asset_fee_liquidity"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
+	"pandora-pay/store/sparse_merkle_tree"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+// every hash map has elements: registrations, accounts, plain accounts with fee liquidities, conditional payments and the native asset
+func createTestState(t *testing.T, dataStorage *DataStorage) {
+
+	assert.Nil(t, dataStorage.Asts.CreateAsset(config_coins.NATIVE_ASSET_FULL, &asset.Asset{
+		DecimalSeparator: byte(config_coins.DECIMAL_SEPARATOR),
+		MaxSupply:        config_coins.MAX_SUPPLY_COINS_UNITS,
+		UpdatePublicKey:  config_coins.BURN_PUBLIC_KEY,
+		SupplyPublicKey:  config_coins.BURN_PUBLIC_KEY,
+		Name:             config_coins.NATIVE_ASSET_NAME,
+		Ticker:           config_coins.NATIVE_ASSET_TICKER,
+		Identification:   config_coins.NATIVE_ASSET_IDENTIFICATION,
+		Description:      config_coins.NATIVE_ASSET_DESCRIPTION,
+	}))
+
+	for i := uint64(0); i < 3; i++ {
+
+		publicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+
+		_, err := dataStorage.CreateRegistration(publicKey, false, nil)
+		assert.Nil(t, err)
+
+		accs, acc, err := dataStorage.CreateAccount(config_coins.NATIVE_ASSET_FULL, publicKey, true)
+		assert.Nil(t, err)
+		acc.Balance.AddBalanceUint(100 + i)
+		assert.Nil(t, accs.Update(string(publicKey), acc))
+
+		plainAcc, err := dataStorage.CreatePlainAccount(publicKey, false)
+		assert.Nil(t, err)
+		liquidity := &asset_fee_liquidity.AssetFeeLiquidity{Asset: config_coins.NATIVE_ASSET_FULL, Rate: i + 1}
+		status, err := plainAcc.AssetFeeLiquidities.UpdateLiquidity(liquidity)
+		assert.Nil(t, err)
+		plainAcc.AssetFeeLiquidities.Collector = publicKey
+		plainAcc.AssetFeeLiquidities.Version = asset_fee_liquidity.SIMPLE
+		assert.Nil(t, dataStorage.AstsFeeLiquidityCollection.UpdateLiquidity(publicKey, liquidity.Rate, liquidity.LeadingZeros, liquidity.Asset, status))
+		assert.Nil(t, dataStorage.PlainAccs.Update(string(publicKey), plainAcc))
+
+		conditionalPayments, err := dataStorage.ConditionalPaymentsCollection.GetMap(10 + i)
+		assert.Nil(t, err)
+		condPayment := conditional_payment.NewConditionalPayment(nil, 0, 10+i)
+		condPayment.TxId = cryptography.RandomHash()
+		condPayment.Asset = config_coins.NATIVE_ASSET_FULL
+		condPayment.Hashlock = cryptography.RandomHash()
+		assert.Nil(t, conditionalPayments.Create(string(condPayment.TxId)+"_0", condPayment))
+	}
+
+	assert.Nil(t, dataStorage.CommitChanges())
+}
+
+func TestRebuildStateTree(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("/blockchain")
+	assert.Nil(t, err)
+	defer db.Close()
+
+	assert.Nil(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+
+		dataStorage := NewDataStorage(writer)
+		createTestState(t, dataStorage)
+
+		root := dataStorage.StateTree.Root()
+		assert.NotEqual(t, sparse_merkle_tree.EmptyHash, root)
+
+		//the conditional payments are authenticated
+		conditionalPayments, err := dataStorage.ConditionalPaymentsCollection.GetMap(10)
+		assert.Nil(t, err)
+		keys, _, _, err := conditionalPayments.Iterate("", 1)
+		assert.Nil(t, err)
+		serialized, proof, err := conditionalPayments.StateProof(keys[0])
+		assert.Nil(t, err)
+		assert.True(t, sparse_merkle_tree.VerifyProof(root, conditionalPayments.StateKey(keys[0]), sparse_merkle_tree.ValueHash(serialized), proof))
+
+		//the version of the state tree is missing in the stores of the older versions
+		assert.True(t, dataStorage.IsStateTreeOutdated())
+		assert.Nil(t, dataStorage.RebuildStateTree())
+		assert.False(t, dataStorage.IsStateTreeOutdated())
+		assert.Equal(t, root, NewDataStorage(writer).StateTree.Root())
+
+		//the tree is replaced even when it has different leaves
+		assert.Nil(t, dataStorage.StateTree.Update(sparse_merkle_tree.Key("registrations", "missing"), cryptography.RandomHash()))
+		assert.NotEqual(t, root, dataStorage.StateTree.Root())
+		assert.Nil(t, dataStorage.RebuildStateTree())
+		assert.Equal(t, root, NewDataStorage(writer).StateTree.Root())
+
+		return nil
+	}))
+}
@@ -9,6 +9,7 @@ import (
 
 type PendingStakesList struct {
 	*hash_map.HashMap[*pending_stakes.PendingStakes]
+	Processed []*pending_stakes.PendingStakes //deleted after they were processed, required for notifications
 }
 
 func (this *PendingStakesList) CreateNewPendingStakes(blockHeight uint64) (*pending_stakes.PendingStakes, error) {
@@ -31,6 +32,7 @@ func NewPendingStakesList(tx store_db_interface.StoreDBTransactionInterface) (th
 
 	this = &PendingStakesList{
 		hash_map.CreateNewHashMap[*pending_stakes.PendingStakes](tx, "pendingStakes", 0, false),
+		nil,
 	}
 
 	this.HashMap.CreateObject = func(key []byte, index uint64) (*pending_stakes.PendingStakes, error) {
@@ -0,0 +1,144 @@
+package plain_account_multisig
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers/advanced_buffers"
+)
+
+const (
+	MULTISIG_MAX_PUBLIC_KEYS = 5
+	MULTISIG_KEY_SUFFIX      = byte(0x02) //compressed public keys end with 0x00 or 0x01, hence multisig keys can't collide with them
+)
+
+// PlainAccountMultisig is a M-of-N key set. The hash of the set is the key of its plain account
+type PlainAccountMultisig struct {
+	Key        []byte   `json:"-" msgpack:"-"` //hashMap key
+	Index      uint64   `json:"-" msgpack:"-"` //hashMap index
+	Version    uint64   `json:"version" msgpack:"version"`
+	Threshold  byte     `json:"threshold" msgpack:"threshold"`
+	PublicKeys [][]byte `json:"publicKeys" msgpack:"publicKeys"`
+}
+
+// IsMultisigKey returns true when the plain account key is the hash of a multisig key set
+func IsMultisigKey(key []byte) bool {
+	return len(key) == cryptography.PublicKeySize && key[cryptography.PublicKeySize-1] == MULTISIG_KEY_SUFFIX
+}
+
+// ComputeMultisigKey requires the public keys to be sorted, so the same set always gets the same key
+func ComputeMultisigKey(threshold byte, publicKeys [][]byte) []byte {
+	w := advanced_buffers.NewBufferWriter()
+	w.WriteByte(threshold)
+	w.WriteByte(byte(len(publicKeys)))
+	for _, publicKey := range publicKeys {
+		w.Write(publicKey)
+	}
+	return append(cryptography.SHA3(w.Bytes()), MULTISIG_KEY_SUFFIX)
+}
+
+// ValidateKeys checks the threshold and that the public keys are sorted and unique
+func ValidateKeys(threshold byte, publicKeys [][]byte) error {
+	if len(publicKeys) < 2 || len(publicKeys) > MULTISIG_MAX_PUBLIC_KEYS {
+		return errors.New("Multisig must have between 2 and 5 public keys")
+	}
+	if threshold == 0 || int(threshold) > len(publicKeys) {
+		return errors.New("Invalid multisig threshold")
+	}
+	for i, publicKey := range publicKeys {
+		if len(publicKey) != cryptography.PublicKeySize {
+			return errors.New("Multisig public key length is invalid")
+		}
+		if IsMultisigKey(publicKey) {
+			return errors.New("Multisig public key can not be a multisig")
+		}
+		if i > 0 && bytes.Compare(publicKeys[i-1], publicKey) >= 0 {
+			return errors.New("Multisig public keys must be sorted and unique")
+		}
+	}
+	return nil
+}
+
+func (this *PlainAccountMultisig) HasPublicKey(signer []byte) bool {
+	for _, publicKey := range this.PublicKeys {
+		if bytes.Equal(signer, publicKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifySigners checks that the signers belong to the set and reach the threshold. The signatures are verified by the input
+func (this *PlainAccountMultisig) VerifySigners(signers [][]byte) error {
+	if len(signers) < int(this.Threshold) {
+		return errors.New("Multisig threshold not met")
+	}
+	for _, signer := range signers {
+		if !this.HasPublicKey(signer) {
+			return errors.New("Signer is not part of the multisig")
+		}
+	}
+	return nil
+}
+
+func (this *PlainAccountMultisig) IsDeletable() bool {
+	return false
+}
+
+func (this *PlainAccountMultisig) SetKey(key []byte) {
+	this.Key = key
+}
+
+func (this *PlainAccountMultisig) SetIndex(value uint64) {
+	this.Index = value
+}
+
+func (this *PlainAccountMultisig) GetIndex() uint64 {
+	return this.Index
+}
+
+func (this *PlainAccountMultisig) Validate() error {
+	if this.Version != 0 {
+		return errors.New("Multisig Version is invalid")
+	}
+	return ValidateKeys(this.Threshold, this.PublicKeys)
+}
+
+func (this *PlainAccountMultisig) Serialize(w *advanced_buffers.BufferWriter) {
+	w.WriteUvarint(this.Version)
+	w.WriteByte(this.Threshold)
+	w.WriteByte(byte(len(this.PublicKeys)))
+	for _, publicKey := range this.PublicKeys {
+		w.Write(publicKey)
+	}
+}
+
+func (this *PlainAccountMultisig) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+	if this.Version, err = r.ReadUvarint(); err != nil {
+		return
+	}
+	if this.Threshold, err = r.ReadByte(); err != nil {
+		return
+	}
+	var n byte
+	if n, err = r.ReadByte(); err != nil {
+		return
+	}
+	if n > MULTISIG_MAX_PUBLIC_KEYS {
+		return errors.New("Too many multisig public keys")
+	}
+	this.PublicKeys = make([][]byte, n)
+	for i := range this.PublicKeys {
+		if this.PublicKeys[i], err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func NewPlainAccountMultisig(key []byte, index uint64) *PlainAccountMultisig {
+	return &PlainAccountMultisig{
+		Key:   key,
+		Index: index,
+	}
+}
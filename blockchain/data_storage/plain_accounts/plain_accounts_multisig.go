@@ -0,0 +1,37 @@
+package plain_accounts
+
+import (
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account_multisig"
+	"pandora-pay/cryptography"
+	hash_map "pandora-pay/store/hash_map"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type PlainAccountsMultisig struct {
+	*hash_map.HashMap[*plain_account_multisig.PlainAccountMultisig]
+}
+
+// WARNING: should NOT be used manually without being called from DataStorage
+func (this *PlainAccountsMultisig) CreateNewPlainAccountMultisig(threshold byte, publicKeys [][]byte) (*plain_account_multisig.PlainAccountMultisig, error) {
+	key := plain_account_multisig.ComputeMultisigKey(threshold, publicKeys)
+	multisig := plain_account_multisig.NewPlainAccountMultisig(key, 0) //index will be set by update
+	multisig.Threshold = threshold
+	multisig.PublicKeys = publicKeys
+	if err := this.Create(string(key), multisig); err != nil {
+		return nil, err
+	}
+	return multisig, nil
+}
+
+func NewPlainAccountsMultisig(tx store_db_interface.StoreDBTransactionInterface) (this *PlainAccountsMultisig) {
+
+	this = &PlainAccountsMultisig{
+		hash_map.CreateNewHashMap[*plain_account_multisig.PlainAccountMultisig](tx, "plainAccsMultisig", cryptography.PublicKeySize, false),
+	}
+
+	this.HashMap.CreateObject = func(key []byte, index uint64) (*plain_account_multisig.PlainAccountMultisig, error) {
+		return plain_account_multisig.NewPlainAccountMultisig(key, index), nil
+	}
+
+	return
+}
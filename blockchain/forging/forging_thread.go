@@ -11,6 +11,7 @@ import (
 	"pandora-pay/gui"
 	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/mempool"
 	"strconv"
@@ -18,6 +19,8 @@ import (
 	"time"
 )
 
+var metricHashRate = metrics.NewGauge("pandora_forging_hash_rate", "Hashes per second computed by all the forging workers")
+
 type ForgingThread struct {
 	mempool                   *mempool.Mempool
 	addressBalanceDecryptor   *address_balance_decryptor.AddressBalanceDecryptor
@@ -53,11 +56,14 @@ func (thread *ForgingThread) startForging() {
 		for {
 
 			s := ""
+			total := uint64(0)
 			for i := 0; i < thread.threads; i++ {
 				hashesPerSecond := atomic.SwapUint32(&thread.workers[i].hashes, 0)
 				s += strconv.FormatUint(uint64(hashesPerSecond), 10) + " "
+				total += uint64(hashesPerSecond)
 			}
 			gui.GUI.InfoUpdate("Hashes/s", s)
+			metricHashRate.Set(float64(total))
 
 			time.Sleep(time.Second)
 		}
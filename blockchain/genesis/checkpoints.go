@@ -0,0 +1,110 @@
+package genesis
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"pandora-pay/config"
+	"pandora-pay/config/arguments"
+	"pandora-pay/cryptography"
+	"strconv"
+	"strings"
+)
+
+// hard-coded block hashes. A chain which doesn't contain these blocks is rejected.
+// No checkpoints ship yet for any network, they can be set with --checkpoint until the first releases include them
+var checkpointsMainnet = map[uint64][]byte{}
+
+var checkpointsTestnet = map[uint64][]byte{}
+
+var checkpointsDevnet = map[uint64][]byte{}
+
+var Checkpoints map[uint64][]byte
+
+func getCheckpoints() (map[uint64][]byte, error) {
+
+	switch config.NETWORK_SELECTED {
+	case config.MAIN_NET_NETWORK_BYTE:
+		return checkpointsMainnet, nil
+	case config.TEST_NET_NETWORK_BYTE:
+		return checkpointsTestnet, nil
+	case config.DEV_NET_NETWORK_BYTE:
+		return checkpointsDevnet, nil
+	default:
+		return nil, errors.New("Invalid Network")
+	}
+}
+
+// the argument has the format height:hash,height:hash with the hash encoded as hex
+func parseCheckpoints(data string, out map[uint64][]byte) (err error) {
+
+	for _, checkpoint := range strings.Split(data, ",") {
+
+		v := strings.Split(checkpoint, ":")
+		if len(v) != 2 {
+			return errors.New("--checkpoint must be height:hash")
+		}
+
+		var height uint64
+		if height, err = strconv.ParseUint(v[0], 10, 64); err != nil {
+			return
+		}
+
+		var hash []byte
+		if hash, err = hex.DecodeString(v[1]); err != nil {
+			return
+		}
+		if len(hash) != cryptography.HashSize {
+			return errors.New("--checkpoint hash length is invalid")
+		}
+
+		out[height] = hash
+	}
+
+	return
+}
+
+func initCheckpoints() (err error) {
+
+	var checkpoints map[uint64][]byte
+	if checkpoints, err = getCheckpoints(); err != nil {
+		return
+	}
+
+	Checkpoints = make(map[uint64][]byte)
+	for height, hash := range checkpoints {
+		Checkpoints[height] = hash
+	}
+
+	if dataArgument := arguments.Arguments["--checkpoint"]; dataArgument != nil {
+		if err = parseCheckpoints(dataArgument.(string), Checkpoints); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// VerifyCheckpoint returns an error if there is a checkpoint at this height with a different hash
+func VerifyCheckpoint(height uint64, hash []byte) error {
+	if checkpoint := Checkpoints[height]; checkpoint != nil && !bytes.Equal(checkpoint, hash) {
+		return fmt.Errorf("Block %d is not matching the checkpoint", height)
+	}
+	return nil
+}
+
+// GetLastCheckpoint returns the highest checkpoint height lower than the height
+func GetLastCheckpoint(height uint64) (uint64, bool) {
+
+	found := false
+	last := uint64(0)
+	for checkpointHeight := range Checkpoints {
+		if checkpointHeight < height && (!found || checkpointHeight > last) {
+			last = checkpointHeight
+			found = true
+		}
+	}
+
+	return last, found
+}
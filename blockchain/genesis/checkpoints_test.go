@@ -0,0 +1,31 @@
+package genesis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/helpers"
+	"strings"
+	"testing"
+)
+
+func TestCheckpoints(t *testing.T) {
+
+	hash1 := strings.Repeat("01", 32)
+	hash2 := strings.Repeat("02", 32)
+
+	Checkpoints = make(map[uint64][]byte)
+	assert.NoError(t, parseCheckpoints("100:"+hash1+",250:"+hash2, Checkpoints))
+	assert.Error(t, parseCheckpoints("100", Checkpoints))
+	assert.Error(t, parseCheckpoints("100:0102", Checkpoints))
+
+	assert.NoError(t, VerifyCheckpoint(100, helpers.DecodeHex(hash1)))
+	assert.Error(t, VerifyCheckpoint(100, helpers.DecodeHex(hash2)))
+	assert.NoError(t, VerifyCheckpoint(101, helpers.DecodeHex(hash2)))
+
+	_, found := GetLastCheckpoint(100)
+	assert.False(t, found)
+
+	height, found := GetLastCheckpoint(300)
+	assert.True(t, found)
+	assert.Equal(t, uint64(250), height)
+
+}
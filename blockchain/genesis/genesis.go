@@ -219,6 +219,10 @@ func GenesisInit(walletGetFirstAddressForDevnetGenesisAirdrop func() (string, *s
 		return
 	}
 
+	if err = initCheckpoints(); err != nil {
+		return
+	}
+
 	if dataArguments := arguments.Arguments["--create-new-genesis"]; dataArguments != nil {
 		if err = createNewGenesis(strings.Split(dataArguments.(string), ",")); err != nil {
 			return
@@ -56,6 +56,23 @@ func CreateTxPreviewFromTx(tx *transaction.Transaction) (*TxPreview, error) {
 				txBaseExtra.PayloadIndex,
 				txBaseExtra.Resolution,
 			}
+		case transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK:
+
+			txBaseExtra := txBase.Extra.(*transaction_simple_extra.TransactionSimpleExtraResolutionConditionalPaymentHashlock)
+
+			previewBase.Extra = &TxPreviewSimpleExtraResolutionConditionalPaymentHashlock{
+				txBaseExtra.TxId,
+				txBaseExtra.PayloadIndex,
+				txBaseExtra.Preimage,
+			}
+		case transaction_simple.SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER:
+
+			txBaseExtra := txBase.Extra.(*transaction_simple_extra.TransactionSimpleExtraPlainAccountMultisigRegister)
+
+			previewBase.Extra = &TxPreviewSimpleExtraPlainAccountMultisigRegister{
+				txBaseExtra.Threshold,
+				txBaseExtra.PublicKeys,
+			}
 		}
 
 		base = previewBase
@@ -84,9 +101,21 @@ func CreateTxPreviewFromTx(tx *transaction.Transaction) (*TxPreview, error) {
 				payloadExtra = &TxPreviewZetherPayloadExtraStaking{}
 			case transaction_zether_payload_script.SCRIPT_SPEND:
 				payloadExtra = &TxPreviewZetherPayloadExtraSpend{}
+			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
+				txPayloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease)
+				payloadExtra = &TxPreviewZetherPayloadExtraAssetSupplyDecrease{txPayloadExtra.AssetSupplyPublicKey}
+			case transaction_zether_payload_script.SCRIPT_ASSET_PAUSE:
+				txPayloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause)
+				payloadExtra = &TxPreviewZetherPayloadExtraAssetPause{txPayloadExtra.AssetId, txPayloadExtra.Paused}
+			case transaction_zether_payload_script.SCRIPT_ASSET_FREEZE:
+				txPayloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreeze)
+				payloadExtra = &TxPreviewZetherPayloadExtraAssetFreeze{txPayloadExtra.AssetId}
+			case transaction_zether_payload_script.SCRIPT_ASSET_UPDATE:
+				txPayloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdate)
+				payloadExtra = &TxPreviewZetherPayloadExtraAssetUpdate{txPayloadExtra.AssetId, txPayloadExtra.ChangeDescription, txPayloadExtra.ChangeData, txPayloadExtra.ChangeMaxSupply, txPayloadExtra.NewUpdatePublicKey, txPayloadExtra.NewSupplyPublicKey}
 			case transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT:
 				txPayloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraConditionalPayment)
-				payloadExtra = &TxPreviewZetherPayloadExtraPayToScript{txPayloadExtra.Deadline, txPayloadExtra.DefaultResolution, txPayloadExtra.MultisigThreshold}
+				payloadExtra = &TxPreviewZetherPayloadExtraPayToScript{txPayloadExtra.Deadline, txPayloadExtra.DefaultResolution, txPayloadExtra.MultisigThreshold, txPayloadExtra.Hashlock}
 			}
 
 			payloads[i] = &TxPreviewZetherPayload{
@@ -11,6 +11,17 @@ type TxPreviewSimpleExtraResolutionConditionalPayment struct {
 	Resolution   bool   `json:"resolution" msgpack:"resolution"`
 }
 
+type TxPreviewSimpleExtraResolutionConditionalPaymentHashlock struct {
+	TxId         []byte `json:"txId" msgpack:"txId"`
+	PayloadIndex byte   `json:"payloadIndex" msgpack:"payloadIndex"`
+	Preimage     []byte `json:"preimage" msgpack:"preimage"`
+}
+
+type TxPreviewSimpleExtraPlainAccountMultisigRegister struct {
+	Threshold  byte     `json:"threshold" msgpack:"threshold"`
+	PublicKeys [][]byte `json:"publicKeys" msgpack:"publicKeys"`
+}
+
 type TxPreviewSimple struct {
 	TxScript    transaction_simple.ScriptType           `json:"txScript" msgpack:"txScript"`
 	DataVersion transaction_data.TransactionDataVersion `json:"dataVersion" msgpack:"dataVersion"`
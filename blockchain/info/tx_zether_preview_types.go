@@ -15,10 +15,33 @@ type TxPreviewZetherPayloadExtraStaking struct {
 type TxPreviewZetherPayloadExtraSpend struct {
 }
 
+type TxPreviewZetherPayloadExtraAssetSupplyDecrease struct {
+	AssetSupplyPublicKey []byte `json:"assetSupplyPublicKey" msgpack:"assetSupplyPublicKey"`
+}
+
+type TxPreviewZetherPayloadExtraAssetPause struct {
+	AssetId []byte `json:"assetId" msgpack:"assetId"`
+	Paused  bool   `json:"paused" msgpack:"paused"`
+}
+
+type TxPreviewZetherPayloadExtraAssetFreeze struct {
+	AssetId []byte `json:"assetId" msgpack:"assetId"`
+}
+
+type TxPreviewZetherPayloadExtraAssetUpdate struct {
+	AssetId            []byte `json:"assetId" msgpack:"assetId"`
+	ChangeDescription  bool   `json:"changeDescription" msgpack:"changeDescription"`
+	ChangeData         bool   `json:"changeData" msgpack:"changeData"`
+	ChangeMaxSupply    bool   `json:"changeMaxSupply" msgpack:"changeMaxSupply"`
+	NewUpdatePublicKey []byte `json:"newUpdatePublicKey,omitempty" msgpack:"newUpdatePublicKey,omitempty"`
+	NewSupplyPublicKey []byte `json:"newSupplyPublicKey,omitempty" msgpack:"newSupplyPublicKey,omitempty"`
+}
+
 type TxPreviewZetherPayloadExtraPayToScript struct {
 	Deadline          uint64 `json:"deadline" msgpack:"dealine"`
 	DefaultResolution bool   `json:"defaultResolution" msgpack:"defaultResolution"`
 	Threshold         byte   `json:"threshold" msgpack:"threshold"`
+	Hashlock          []byte `json:"hashlock,omitempty" msgpack:"hashlock,omitempty"`
 }
 
 type TxPreviewZetherPayload struct {
@@ -51,8 +51,10 @@ type json_TransactionSimple struct {
 }
 
 type json_TransactionSimpleInput struct {
-	PublicKey []byte `json:"publicKey,omitempty" msgpack:"publicKey,omitempty"` //32
-	Signature []byte `json:"signature" msgpack:"signature"`                     //64
+	PublicKey          []byte   `json:"publicKey,omitempty" msgpack:"publicKey,omitempty"` //32
+	Signature          []byte   `json:"signature" msgpack:"signature"`                     //64
+	MultisigPublicKeys [][]byte `json:"multisigPublicKeys,omitempty" msgpack:"multisigPublicKeys,omitempty"`
+	MultisigSignatures [][]byte `json:"multisigSignatures,omitempty" msgpack:"multisigSignatures,omitempty"`
 }
 
 type json_Only_TransactionSimpleExtraUpdateAssetFeeLiquidity struct {
@@ -69,6 +71,17 @@ type json_Only_TransactionSimpleExtraResolutionConditionalPayment struct {
 	Signatures         [][]byte `json:"signatures"`
 }
 
+type json_Only_TransactionSimpleExtraResolutionConditionalPaymentHashlock struct {
+	TxId         []byte `json:"txId"`
+	PayloadIndex byte   `json:"payloadIndex"`
+	Preimage     []byte `json:"preimage"`
+}
+
+type json_Only_TransactionSimpleExtraPlainAccountMultisigRegister struct {
+	Threshold  byte     `json:"threshold"`
+	PublicKeys [][]byte `json:"publicKeys"`
+}
+
 type json_Only_TransactionZether struct {
 	ChainHeight     uint64                          `json:"chainHeight"  msgpack:"chainHeight"`
 	ChainKernelHash []byte                          `json:"chainKernelHash"  msgpack:"chainKernelHash"`
@@ -100,6 +113,38 @@ type json_Only_TransactionZetherPayloadExtraAssetSupplyIncrease struct {
 	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
 }
 
+type json_Only_TransactionZetherPayloadExtraAssetSupplyDecrease struct {
+	AssetSupplyPublicKey []byte `json:"assetSupplyPublicKey"  msgpack:"assetSupplyPublicKey"`
+	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
+}
+
+type json_Only_TransactionZetherPayloadExtraAssetPause struct {
+	AssetId              []byte `json:"assetId"  msgpack:"assetId"`
+	Paused               bool   `json:"paused"  msgpack:"paused"`
+	AssetUpdatePublicKey []byte `json:"assetUpdatePublicKey"  msgpack:"assetUpdatePublicKey"`
+	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
+}
+
+type json_Only_TransactionZetherPayloadExtraAssetFreeze struct {
+	AssetId              []byte `json:"assetId"  msgpack:"assetId"`
+	AssetUpdatePublicKey []byte `json:"assetUpdatePublicKey"  msgpack:"assetUpdatePublicKey"`
+	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
+}
+
+type json_Only_TransactionZetherPayloadExtraAssetUpdate struct {
+	AssetId              []byte `json:"assetId"  msgpack:"assetId"`
+	ChangeDescription    bool   `json:"changeDescription"  msgpack:"changeDescription"`
+	Description          string `json:"description"  msgpack:"description"`
+	ChangeData           bool   `json:"changeData"  msgpack:"changeData"`
+	Data                 []byte `json:"data"  msgpack:"data"`
+	ChangeMaxSupply      bool   `json:"changeMaxSupply"  msgpack:"changeMaxSupply"`
+	MaxSupply            uint64 `json:"maxSupply"  msgpack:"maxSupply"`
+	NewUpdatePublicKey   []byte `json:"newUpdatePublicKey"  msgpack:"newUpdatePublicKey"`
+	NewSupplyPublicKey   []byte `json:"newSupplyPublicKey"  msgpack:"newSupplyPublicKey"`
+	AssetUpdatePublicKey []byte `json:"assetUpdatePublicKey"  msgpack:"assetUpdatePublicKey"`
+	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
+}
+
 type json_Only_TransactionZetherPayloadExtraPlainAccountFund struct {
 	PlainAccountPublicKey []byte `json:"plainAccountPublicKey"  msgpack:"plainAccountPublicKey"`
 }
@@ -109,6 +154,7 @@ type json_Only_TransactionZetherPayloadExtraConditionalPayment struct {
 	DefaultResolution  bool     `json:"defaultResolution" msgpack:"defaultResolution"`
 	MultisigThreshold  byte     `json:"multisigThreshold" msgpack:"multisigThreshold"`
 	MultisigPublicKeys [][]byte `json:"multisigPublicKeys" msgpack:"multisigPublicKeys"`
+	Hashlock           []byte   `json:"hashlock,omitempty" msgpack:"hashlock,omitempty"`
 }
 
 type json_Only_TransactionZetherStatement struct {
@@ -162,6 +208,8 @@ func marshalJSON(tx *Transaction, marshal func(any) ([]byte, error)) ([]byte, er
 			vinJson = &json_TransactionSimpleInput{
 				base.Vin.PublicKey,
 				base.Vin.Signature,
+				base.Vin.MultisigPublicKeys,
+				base.Vin.MultisigSignatures,
 			}
 		}
 
@@ -193,6 +241,19 @@ func marshalJSON(tx *Transaction, marshal func(any) ([]byte, error)) ([]byte, er
 				extra.MultisigPublicKeys,
 				extra.Signatures,
 			}
+		case transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK:
+			extra := base.Extra.(*transaction_simple_extra.TransactionSimpleExtraResolutionConditionalPaymentHashlock)
+			simpleJson.Extra = json_Only_TransactionSimpleExtraResolutionConditionalPaymentHashlock{
+				extra.TxId,
+				extra.PayloadIndex,
+				extra.Preimage,
+			}
+		case transaction_simple.SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER:
+			extra := base.Extra.(*transaction_simple_extra.TransactionSimpleExtraPlainAccountMultisigRegister)
+			simpleJson.Extra = json_Only_TransactionSimpleExtraPlainAccountMultisigRegister{
+				extra.Threshold,
+				extra.PublicKeys,
+			}
 		default:
 			return nil, errors.New("Invalid simple.TxScript")
 		}
@@ -265,6 +326,42 @@ func marshalJSON(tx *Transaction, marshal func(any) ([]byte, error)) ([]byte, er
 					payloadExtra.AssetSignature,
 					payloadExtra.AssetSupplyPublicKey,
 				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
+				payloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease)
+				extra = &json_Only_TransactionZetherPayloadExtraAssetSupplyDecrease{
+					payloadExtra.AssetSupplyPublicKey,
+					payloadExtra.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_PAUSE:
+				payloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause)
+				extra = &json_Only_TransactionZetherPayloadExtraAssetPause{
+					payloadExtra.AssetId,
+					payloadExtra.Paused,
+					payloadExtra.AssetUpdatePublicKey,
+					payloadExtra.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_FREEZE:
+				payloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreeze)
+				extra = &json_Only_TransactionZetherPayloadExtraAssetFreeze{
+					payloadExtra.AssetId,
+					payloadExtra.AssetUpdatePublicKey,
+					payloadExtra.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_UPDATE:
+				payloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdate)
+				extra = &json_Only_TransactionZetherPayloadExtraAssetUpdate{
+					payloadExtra.AssetId,
+					payloadExtra.ChangeDescription,
+					payloadExtra.Description,
+					payloadExtra.ChangeData,
+					payloadExtra.Data,
+					payloadExtra.ChangeMaxSupply,
+					payloadExtra.MaxSupply,
+					payloadExtra.NewUpdatePublicKey,
+					payloadExtra.NewSupplyPublicKey,
+					payloadExtra.AssetUpdatePublicKey,
+					payloadExtra.AssetSignature,
+				}
 			case transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND:
 				payloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraPlainAccountFund)
 				extra = &json_Only_TransactionZetherPayloadExtraPlainAccountFund{
@@ -277,6 +374,7 @@ func marshalJSON(tx *Transaction, marshal func(any) ([]byte, error)) ([]byte, er
 					payloadExtra.DefaultResolution,
 					payloadExtra.MultisigThreshold,
 					payloadExtra.MultisigPublicKeys,
+					payloadExtra.Hashlock,
 				}
 			default:
 				return nil, errors.New("Invalid zether.TxScript")
@@ -367,9 +465,19 @@ func (tx *Transaction) UnmarshalJSON(data []byte) (err error) {
 			return errors.New("Invalid tx.DataVersion")
 		}
 
-		vin := &transaction_simple_parts.TransactionSimpleInput{
-			PublicKey: simpleJson.Vin.PublicKey,
-			Signature: simpleJson.Vin.Signature,
+		var vin *transaction_simple_parts.TransactionSimpleInput
+		if simpleJson.Vin != nil { //resolutions don't have a vin
+			vin = &transaction_simple_parts.TransactionSimpleInput{
+				PublicKey:          simpleJson.Vin.PublicKey,
+				Signature:          simpleJson.Vin.Signature,
+				MultisigPublicKeys: simpleJson.Vin.MultisigPublicKeys,
+				MultisigSignatures: simpleJson.Vin.MultisigSignatures,
+			}
+		}
+
+		var extraData []byte
+		if extraData, err = json.Marshal(simpleJson.Extra); err != nil {
+			return
 		}
 
 		base := &transaction_simple.TransactionSimple{
@@ -388,7 +496,7 @@ func (tx *Transaction) UnmarshalJSON(data []byte) (err error) {
 		switch simpleJson.TxScript {
 		case transaction_simple.SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY:
 			extraJson := &json_Only_TransactionSimpleExtraUpdateAssetFeeLiquidity{}
-			if err = json.Unmarshal(data, extraJson); err != nil {
+			if err = json.Unmarshal(extraData, extraJson); err != nil {
 				return
 			}
 
@@ -399,7 +507,7 @@ func (tx *Transaction) UnmarshalJSON(data []byte) (err error) {
 			}
 		case transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT:
 			extraJson := &json_Only_TransactionSimpleExtraResolutionConditionalPayment{}
-			if err = json.Unmarshal(data, extraJson); err != nil {
+			if err = json.Unmarshal(extraData, extraJson); err != nil {
 				return
 			}
 
@@ -410,6 +518,27 @@ func (tx *Transaction) UnmarshalJSON(data []byte) (err error) {
 				extraJson.MultisigPublicKeys,
 				extraJson.Signatures,
 			}
+		case transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK:
+			extraJson := &json_Only_TransactionSimpleExtraResolutionConditionalPaymentHashlock{}
+			if err = json.Unmarshal(extraData, extraJson); err != nil {
+				return
+			}
+
+			base.Extra = &transaction_simple_extra.TransactionSimpleExtraResolutionConditionalPaymentHashlock{nil,
+				extraJson.TxId,
+				extraJson.PayloadIndex,
+				extraJson.Preimage,
+			}
+		case transaction_simple.SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER:
+			extraJson := &json_Only_TransactionSimpleExtraPlainAccountMultisigRegister{}
+			if err = json.Unmarshal(extraData, extraJson); err != nil {
+				return
+			}
+
+			base.Extra = &transaction_simple_extra.TransactionSimpleExtraPlainAccountMultisigRegister{nil,
+				extraJson.Threshold,
+				extraJson.PublicKeys,
+			}
 		default:
 			return errors.New("Invalid json Simple TxScript")
 		}
@@ -547,6 +676,58 @@ func (tx *Transaction) UnmarshalJSON(data []byte) (err error) {
 					extraJson.AssetSignature,
 					extraJson.AssetSupplyPublicKey,
 				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
+				extraJson := &json_Only_TransactionZetherPayloadExtraAssetSupplyDecrease{}
+				if err = json.Unmarshal(data, extraJson); err != nil {
+					return err
+				}
+				payloads[i].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease{
+					nil,
+					extraJson.AssetSupplyPublicKey,
+					extraJson.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_PAUSE:
+				extraJson := &json_Only_TransactionZetherPayloadExtraAssetPause{}
+				if err = json.Unmarshal(data, extraJson); err != nil {
+					return err
+				}
+				payloads[i].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause{
+					nil,
+					extraJson.AssetId,
+					extraJson.Paused,
+					extraJson.AssetUpdatePublicKey,
+					extraJson.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_FREEZE:
+				extraJson := &json_Only_TransactionZetherPayloadExtraAssetFreeze{}
+				if err = json.Unmarshal(data, extraJson); err != nil {
+					return err
+				}
+				payloads[i].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreeze{
+					nil,
+					extraJson.AssetId,
+					extraJson.AssetUpdatePublicKey,
+					extraJson.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_UPDATE:
+				extraJson := &json_Only_TransactionZetherPayloadExtraAssetUpdate{}
+				if err = json.Unmarshal(data, extraJson); err != nil {
+					return err
+				}
+				payloads[i].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdate{
+					nil,
+					extraJson.AssetId,
+					extraJson.ChangeDescription,
+					extraJson.Description,
+					extraJson.ChangeData,
+					extraJson.Data,
+					extraJson.ChangeMaxSupply,
+					extraJson.MaxSupply,
+					extraJson.NewUpdatePublicKey,
+					extraJson.NewSupplyPublicKey,
+					extraJson.AssetUpdatePublicKey,
+					extraJson.AssetSignature,
+				}
 			case transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND:
 				extraJson := &json_Only_TransactionZetherPayloadExtraPlainAccountFund{}
 				if err = json.Unmarshal(data, extraJson); err != nil {
@@ -567,6 +748,7 @@ func (tx *Transaction) UnmarshalJSON(data []byte) (err error) {
 					extraJson.DefaultResolution,
 					extraJson.MultisigThreshold,
 					extraJson.MultisigPublicKeys,
+					extraJson.Hashlock,
 				}
 			default:
 				return errors.New("Invalid Zether TxScript")
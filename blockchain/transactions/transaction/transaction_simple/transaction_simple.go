@@ -10,7 +10,6 @@ import (
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_parts"
 	"pandora-pay/config"
-	"pandora-pay/cryptography/crypto"
 	"pandora-pay/helpers/advanced_buffers"
 )
 
@@ -38,6 +37,12 @@ func (tx *TransactionSimple) IncludeTransaction(blockHeight uint64, txHash []byt
 			return errors.New("Plain Account was not found")
 		}
 
+		if tx.Vin.IsMultisig() {
+			if err = dataStorage.VerifyPlainAccountMultisig(tx.Vin.PublicKey, tx.Vin.MultisigPublicKeys); err != nil {
+				return
+			}
+		}
+
 		if plainAcc.Nonce != tx.Nonce {
 			return fmt.Errorf("Account nonce doesn't match %d %d", plainAcc.Nonce, tx.Nonce)
 		}
@@ -78,7 +83,7 @@ func (tx *TransactionSimple) ComputeAllKeys(out map[string]bool) {
 
 func (tx *TransactionSimple) VerifySignatureManually(hashForSignature []byte) bool {
 	if tx.HasVin() {
-		if !crypto.VerifySignature(hashForSignature, tx.Vin.Signature, tx.Vin.PublicKey) {
+		if !tx.Vin.VerifySignature(hashForSignature) {
 			return false
 		}
 	}
@@ -101,7 +106,7 @@ func (tx *TransactionSimple) Validate() (err error) {
 	}
 
 	switch tx.TxScript {
-	case SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY, SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT:
+	case SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY, SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT, SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK, SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER:
 		if tx.Extra == nil {
 			return errors.New("extra is not assigned")
 		}
@@ -152,6 +157,10 @@ func (tx *TransactionSimple) Deserialize(r *advanced_buffers.BufferReader) (err
 		tx.Extra = &transaction_simple_extra.TransactionSimpleExtraUpdateAssetFeeLiquidity{}
 	case SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT:
 		tx.Extra = &transaction_simple_extra.TransactionSimpleExtraResolutionConditionalPayment{}
+	case SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK:
+		tx.Extra = &transaction_simple_extra.TransactionSimpleExtraResolutionConditionalPaymentHashlock{}
+	case SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER:
+		tx.Extra = &transaction_simple_extra.TransactionSimpleExtraPlainAccountMultisigRegister{}
 	default:
 		return errors.New("INVALID SCRIPT TYPE")
 	}
@@ -194,7 +203,7 @@ func (tx *TransactionSimple) Deserialize(r *advanced_buffers.BufferReader) (err
 
 func (tx *TransactionSimple) HasVin() bool {
 	switch tx.TxScript {
-	case SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY:
+	case SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY, SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER:
 		return true
 	default:
 		return false
@@ -0,0 +1,54 @@
+package transaction_simple_extra
+
+import (
+	"errors"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account_multisig"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers/advanced_buffers"
+)
+
+// TransactionSimpleExtraPlainAccountMultisigRegister registers a M-of-N key set. The plain account of the set is plain_account_multisig.ComputeMultisigKey
+type TransactionSimpleExtraPlainAccountMultisigRegister struct {
+	TransactionSimpleExtraInterface
+	Threshold  byte
+	PublicKeys [][]byte
+}
+
+func (this *TransactionSimpleExtraPlainAccountMultisigRegister) IncludeTransactionVin0(blockHeight uint64, plainAcc *plain_account.PlainAccount, dataStorage *data_storage.DataStorage) (err error) {
+	_, err = dataStorage.CreatePlainAccountMultisig(this.Threshold, this.PublicKeys)
+	return
+}
+
+func (this *TransactionSimpleExtraPlainAccountMultisigRegister) Validate(fee uint64) error {
+	return plain_account_multisig.ValidateKeys(this.Threshold, this.PublicKeys)
+}
+
+func (this *TransactionSimpleExtraPlainAccountMultisigRegister) Serialize(w *advanced_buffers.BufferWriter, inclSignature bool) {
+	w.WriteByte(this.Threshold)
+	w.WriteByte(byte(len(this.PublicKeys)))
+	for _, publicKey := range this.PublicKeys {
+		w.Write(publicKey)
+	}
+}
+
+func (this *TransactionSimpleExtraPlainAccountMultisigRegister) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+	if this.Threshold, err = r.ReadByte(); err != nil {
+		return
+	}
+	var n byte
+	if n, err = r.ReadByte(); err != nil {
+		return
+	}
+	if n > plain_account_multisig.MULTISIG_MAX_PUBLIC_KEYS {
+		return errors.New("Too many multisig public keys")
+	}
+	this.PublicKeys = make([][]byte, n)
+	for i := range this.PublicKeys {
+		if this.PublicKeys[i], err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+			return
+		}
+	}
+	return
+}
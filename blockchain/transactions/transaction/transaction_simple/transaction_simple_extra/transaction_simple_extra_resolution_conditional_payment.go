@@ -3,6 +3,8 @@ package transaction_simple_extra
 import (
 	"errors"
 	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/conditional_payments_list"
+	"pandora-pay/blockchain/data_storage/conditional_payments_list/conditional_payment"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
 	"pandora-pay/cryptography"
 	"pandora-pay/cryptography/crypto"
@@ -19,13 +21,15 @@ type TransactionSimpleExtraResolutionConditionalPayment struct {
 	Signatures         [][]byte
 }
 
-func (this *TransactionSimpleExtraResolutionConditionalPayment) IncludeTransactionVin0(blockHeight uint64, plainAcc *plain_account.PlainAccount, dataStorage *data_storage.DataStorage) (err error) {
+// getConditionalPayment returns a conditional payment which was not processed and is not expired
+func getConditionalPayment(txId []byte, payloadIndex byte, blockHeight uint64, dataStorage *data_storage.DataStorage) (key string, conditionalPaymentsMap *conditional_payments_list.ConditionalPaymentsHashMap, condPayment *conditional_payment.ConditionalPayment, err error) {
 
-	key := string(this.TxId) + "_" + strconv.Itoa(int(this.PayloadIndex))
+	key = string(txId) + "_" + strconv.Itoa(int(payloadIndex))
 
 	val := dataStorage.DBTx.Get("conditionalPayments:all:" + string(key))
 	if val == nil {
-		return errors.New("Pending Future not found by key")
+		err = errors.New("Pending Future not found by key")
+		return
 	}
 
 	txBlockHeight, err := strconv.ParseUint(string(val), 10, 64)
@@ -34,25 +38,40 @@ func (this *TransactionSimpleExtraResolutionConditionalPayment) IncludeTransacti
 	}
 
 	if txBlockHeight < blockHeight+1 {
-		return errors.New("Pending Future Expired")
+		err = errors.New("Pending Future Expired")
+		return
 	}
 
-	conditionalPaymentsMap, err := dataStorage.ConditionalPaymentsCollection.GetMap(txBlockHeight)
-	if err != nil {
-		return err
+	if conditionalPaymentsMap, err = dataStorage.ConditionalPaymentsCollection.GetMap(txBlockHeight); err != nil {
+		return
 	}
 
-	condPayment, err := conditionalPaymentsMap.Get(key)
-	if err != nil {
+	if condPayment, err = conditionalPaymentsMap.Get(key); err != nil {
 		return
 	}
 
 	if condPayment == nil {
-		return errors.New("Pending Future not found")
+		err = errors.New("Pending Future not found")
+		return
 	}
 
 	if condPayment.Processed {
-		return errors.New("Pending Future was already processed")
+		err = errors.New("Pending Future was already processed")
+		return
+	}
+
+	return
+}
+
+func (this *TransactionSimpleExtraResolutionConditionalPayment) IncludeTransactionVin0(blockHeight uint64, plainAcc *plain_account.PlainAccount, dataStorage *data_storage.DataStorage) (err error) {
+
+	key, conditionalPaymentsMap, condPayment, err := getConditionalPayment(this.TxId, this.PayloadIndex, blockHeight, dataStorage)
+	if err != nil {
+		return
+	}
+
+	if condPayment.MultisigThreshold == 0 {
+		return errors.New("Hashlock Pending Future requires the preimage")
 	}
 
 	if int(condPayment.MultisigThreshold) > len(this.MultisigPublicKeys) {
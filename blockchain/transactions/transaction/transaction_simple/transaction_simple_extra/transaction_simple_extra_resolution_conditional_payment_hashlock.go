@@ -0,0 +1,71 @@
+package transaction_simple_extra
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/conditional_payments_list/conditional_payment"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
+	"pandora-pay/config"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers/advanced_buffers"
+)
+
+// TransactionSimpleExtraResolutionConditionalPaymentHashlock reveals the preimage of a hashlock conditional payment. Anyone knowing the preimage can resolve it to the receiver before the deadline
+type TransactionSimpleExtraResolutionConditionalPaymentHashlock struct {
+	TransactionSimpleExtraInterface
+	TxId         []byte
+	PayloadIndex byte
+	Preimage     []byte
+}
+
+func (this *TransactionSimpleExtraResolutionConditionalPaymentHashlock) IncludeTransactionVin0(blockHeight uint64, plainAcc *plain_account.PlainAccount, dataStorage *data_storage.DataStorage) (err error) {
+
+	key, conditionalPaymentsMap, condPayment, err := getConditionalPayment(this.TxId, this.PayloadIndex, blockHeight, dataStorage)
+	if err != nil {
+		return
+	}
+
+	if condPayment.MultisigThreshold != 0 {
+		return errors.New("Pending Future is not a hashlock")
+	}
+
+	if !bytes.Equal(conditional_payment.ComputeHashlock(this.Preimage), condPayment.Hashlock) {
+		return errors.New("Preimage doesn't match the hashlock")
+	}
+
+	if err = dataStorage.ProceedConditionalPayment(true, condPayment); err != nil {
+		return
+	}
+
+	return conditionalPaymentsMap.Update(key, condPayment)
+}
+
+func (this *TransactionSimpleExtraResolutionConditionalPaymentHashlock) Validate(fee uint64) error {
+	if len(this.Preimage) != config.HASHLOCK_PREIMAGE_SIZE {
+		return errors.New("Invalid preimage size")
+	}
+	if fee != 0 {
+		return errors.New("Fee should be zero")
+	}
+	return nil
+}
+
+func (this *TransactionSimpleExtraResolutionConditionalPaymentHashlock) Serialize(w *advanced_buffers.BufferWriter, inclSignature bool) {
+	w.Write(this.TxId)
+	w.WriteByte(this.PayloadIndex)
+	w.Write(this.Preimage)
+}
+
+func (this *TransactionSimpleExtraResolutionConditionalPaymentHashlock) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+	if this.TxId, err = r.ReadBytes(cryptography.HashSize); err != nil {
+		return
+	}
+	if this.PayloadIndex, err = r.ReadByte(); err != nil {
+		return
+	}
+	if this.Preimage, err = r.ReadBytes(config.HASHLOCK_PREIMAGE_SIZE); err != nil {
+		return
+	}
+	return
+}
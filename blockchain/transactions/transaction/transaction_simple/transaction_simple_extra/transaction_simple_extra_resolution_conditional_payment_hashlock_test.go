@@ -0,0 +1,79 @@
+package transaction_simple_extra
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/conditional_payments_list/conditional_payment"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"strconv"
+	"testing"
+)
+
+func TestResolutionConditionalPaymentHashlock(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("/hashlock")
+	assert.Nil(t, err)
+	defer db.Close()
+
+	sender := addresses.GenerateNewPrivateKey()
+	receiver := addresses.GenerateNewPrivateKey()
+
+	preimage := cryptography.RandomHash()
+	txId := cryptography.RandomHash()
+	txIdMultisig := cryptography.RandomHash()
+
+	const deadline = uint64(100)
+
+	assert.Nil(t, db.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		dataStorage := data_storage.NewDataStorage(dbTx)
+
+		publicKeyList := [][]byte{sender.GeneratePublicKey(), receiver.GeneratePublicKey()}
+		echanges := []*crypto.ElGamal{
+			crypto.CommitElGamal(sender.GeneratePublicKeyPoint(), big.NewInt(-10)),
+			crypto.CommitElGamal(receiver.GeneratePublicKeyPoint(), big.NewInt(10)),
+		}
+
+		for _, publicKey := range publicKeyList {
+			_, err = dataStorage.CreateRegistration(publicKey, false, nil)
+			assert.Nil(t, err)
+		}
+
+		hashlock := conditional_payment.ComputeHashlock(preimage)
+		assert.Nil(t, dataStorage.AddConditionalPayment(deadline, txId, 0, config_coins.NATIVE_ASSET_FULL, false, true, publicKeyList, echanges, 0, nil, hashlock))
+		assert.Nil(t, dataStorage.AddConditionalPayment(deadline, txIdMultisig, 0, config_coins.NATIVE_ASSET_FULL, false, true, publicKeyList, echanges, 1, [][]byte{receiver.GeneratePublicKey()}, nil))
+		assert.Nil(t, dataStorage.CommitChanges())
+
+		//a multisig conditional payment can't be resolved by a preimage
+		assert.NotNil(t, (&TransactionSimpleExtraResolutionConditionalPaymentHashlock{nil, txIdMultisig, 0, preimage}).IncludeTransactionVin0(deadline-1, nil, dataStorage))
+
+		assert.NotNil(t, (&TransactionSimpleExtraResolutionConditionalPaymentHashlock{nil, txId, 0, cryptography.RandomHash()}).IncludeTransactionVin0(deadline-1, nil, dataStorage), "wrong preimage")
+		assert.NotNil(t, (&TransactionSimpleExtraResolutionConditionalPaymentHashlock{nil, txId, 1, preimage}).IncludeTransactionVin0(deadline-1, nil, dataStorage), "wrong payload index")
+		assert.NotNil(t, (&TransactionSimpleExtraResolutionConditionalPaymentHashlock{nil, txId, 0, preimage}).IncludeTransactionVin0(deadline, nil, dataStorage), "deadline expired")
+
+		extra := &TransactionSimpleExtraResolutionConditionalPaymentHashlock{nil, txId, 0, preimage}
+		assert.Nil(t, extra.IncludeTransactionVin0(deadline-1, nil, dataStorage))
+
+		conditionalPaymentsMap, err := dataStorage.ConditionalPaymentsCollection.GetMap(deadline)
+		assert.Nil(t, err)
+		condPayment, err := conditionalPaymentsMap.Get(string(txId) + "_" + strconv.Itoa(0))
+		assert.Nil(t, err)
+		assert.True(t, condPayment.Processed)
+
+		accs, err := dataStorage.AccsCollection.GetMap(config_coins.NATIVE_ASSET_FULL)
+		assert.Nil(t, err)
+		acc, err := accs.Get(string(receiver.GeneratePublicKey()))
+		assert.Nil(t, err)
+		assert.NotNil(t, acc, "the receiver should have been paid")
+
+		assert.NotNil(t, extra.IncludeTransactionVin0(deadline-1, nil, dataStorage), "already processed")
+
+		return nil
+	}))
+}
@@ -3,14 +3,22 @@ package transaction_simple_parts
 import (
 	"bytes"
 	"errors"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account_multisig"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
 	"pandora-pay/helpers/advanced_buffers"
 )
 
 type TransactionSimpleInput struct {
-	PublicKey []byte //33
-	Signature []byte //64
+	PublicKey          []byte   //33
+	Signature          []byte   //64
+	MultisigPublicKeys [][]byte //only when PublicKey is a multisig key
+	MultisigSignatures [][]byte //only when PublicKey is a multisig key
+}
+
+func (vin *TransactionSimpleInput) IsMultisig() bool {
+	return plain_account_multisig.IsMultisigKey(vin.PublicKey)
 }
 
 func (vin *TransactionSimpleInput) Validate() error {
@@ -22,16 +30,62 @@ func (vin *TransactionSimpleInput) Validate() error {
 	if len(vin.PublicKey) != cryptography.PublicKeySize {
 		return errors.New("Vin.PublicKey length is invalid")
 	}
+
+	if vin.IsMultisig() {
+		if len(vin.Signature) != 0 {
+			return errors.New("Vin.Signature should be empty for a multisig")
+		}
+		//an input without signatures is valid to be signed offline, but it can not be included
+		if len(vin.MultisigPublicKeys) != len(vin.MultisigSignatures) || len(vin.MultisigPublicKeys) > plain_account_multisig.MULTISIG_MAX_PUBLIC_KEYS {
+			return errors.New("Vin multisig signatures are invalid")
+		}
+		unique := make(map[string]bool)
+		for i := range vin.MultisigPublicKeys {
+			if len(vin.MultisigPublicKeys[i]) != cryptography.PublicKeySize || len(vin.MultisigSignatures[i]) != cryptography.SignatureSize {
+				return errors.New("Vin multisig signature length is invalid")
+			}
+			unique[string(vin.MultisigPublicKeys[i])] = true
+		}
+		if len(unique) != len(vin.MultisigPublicKeys) {
+			return errors.New("Vin multisig public keys contain duplicates")
+		}
+		return nil
+	}
+
 	if len(vin.Signature) != cryptography.SignatureSize {
 		return errors.New("Vin.Signature length is invalid")
 	}
 	return nil
 }
 
+// VerifySignature verifies the signature or all the multisig signatures. The multisig threshold is verified when the tx is included
+func (vin *TransactionSimpleInput) VerifySignature(hashForSignature []byte) bool {
+	if vin.IsMultisig() {
+		if len(vin.MultisigSignatures) == 0 {
+			return false
+		}
+		for i := range vin.MultisigPublicKeys {
+			if !crypto.VerifySignature(hashForSignature, vin.MultisigSignatures[i], vin.MultisigPublicKeys[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return crypto.VerifySignature(hashForSignature, vin.Signature, vin.PublicKey)
+}
+
 func (vin *TransactionSimpleInput) Serialize(w *advanced_buffers.BufferWriter, inclSignature bool) {
 	w.Write(vin.PublicKey)
 	if inclSignature {
-		w.Write(vin.Signature)
+		if vin.IsMultisig() {
+			w.WriteByte(byte(len(vin.MultisigSignatures)))
+			for i := range vin.MultisigSignatures {
+				w.Write(vin.MultisigPublicKeys[i])
+				w.Write(vin.MultisigSignatures[i])
+			}
+		} else {
+			w.Write(vin.Signature)
+		}
 	}
 }
 
@@ -39,6 +93,28 @@ func (vin *TransactionSimpleInput) Deserialize(r *advanced_buffers.BufferReader)
 	if vin.PublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
 		return
 	}
+
+	if vin.IsMultisig() {
+		var n byte
+		if n, err = r.ReadByte(); err != nil {
+			return
+		}
+		if n > plain_account_multisig.MULTISIG_MAX_PUBLIC_KEYS {
+			return errors.New("Too many multisig signatures")
+		}
+		vin.MultisigPublicKeys = make([][]byte, n)
+		vin.MultisigSignatures = make([][]byte, n)
+		for i := range vin.MultisigPublicKeys {
+			if vin.MultisigPublicKeys[i], err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+				return
+			}
+			if vin.MultisigSignatures[i], err = r.ReadBytes(cryptography.SignatureSize); err != nil {
+				return
+			}
+		}
+		return
+	}
+
 	if vin.Signature, err = r.ReadBytes(cryptography.SignatureSize); err != nil {
 		return
 	}
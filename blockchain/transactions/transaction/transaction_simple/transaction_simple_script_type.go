@@ -5,6 +5,8 @@ type ScriptType uint64
 const (
 	SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY ScriptType = iota
 	SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT
+	SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK
+	SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER
 )
 
 func (t ScriptType) String() string {
@@ -13,6 +15,10 @@ func (t ScriptType) String() string {
 		return "SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY"
 	case SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT:
 		return "SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT"
+	case SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK:
+		return "SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK"
+	case SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER:
+		return "SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER"
 	default:
 		return "Unknown ScriptType"
 	}
@@ -10,6 +10,7 @@ import (
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
 	"strconv"
@@ -100,16 +101,22 @@ func (tx *TransactionZether) Validate() (err error) {
 }
 
 func (tx *TransactionZether) VerifySignatureManually(txHash []byte) bool {
+	batch := crypto.NewProofsBatchVerifier()
+	return tx.AddProofsToBatch(batch, txHash) == -1 && batch.Verify()
+}
+
+// AddProofsToBatch returns the index of the first payload whose proof failed or -1 if all the proofs were added
+func (tx *TransactionZether) AddProofsToBatch(batch *crypto.ProofsBatchVerifier, txHash []byte) int {
 
 	assetMap := map[string]int{}
-	for _, payload := range tx.Payloads {
-		if payload.Proof.Verify(payload.Asset, assetMap[string(payload.Asset)], tx.ChainKernelHash, payload.Statement, txHash, payload.BurnValue) == false {
-			return false
+	for payloadIndex, payload := range tx.Payloads {
+		if !batch.Add(payload.Proof, payload.Asset, assetMap[string(payload.Asset)], tx.ChainKernelHash, payload.Statement, txHash, payload.BurnValue) {
+			return payloadIndex
 		}
 		assetMap[string(payload.Asset)] = assetMap[string(payload.Asset)] + 1
 	}
 
-	return true
+	return -1
 }
 
 func (tx *TransactionZether) SerializeAdvanced(w *advanced_buffers.BufferWriter, inclSignature bool) {
@@ -0,0 +1,220 @@
+package transaction_zether_payload_extra
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_registrations"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers/advanced_buffers"
+)
+
+// NewUpdatePublicKey and NewSupplyPublicKey are optional. Empty means no rotation
+type TransactionZetherPayloadExtraAssetUpdate struct {
+	TransactionZetherPayloadExtraInterface
+	AssetId              []byte
+	ChangeDescription    bool
+	Description          string
+	ChangeData           bool
+	Data                 []byte
+	ChangeMaxSupply      bool
+	MaxSupply            uint64
+	NewUpdatePublicKey   []byte
+	NewSupplyPublicKey   []byte
+	AssetUpdatePublicKey []byte
+	AssetSignature       []byte
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) BeforeIncludeTxPayload(txHash []byte, payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, publicKeyList [][]byte, blockHeight uint64, dataStorage *data_storage.DataStorage) (err error) {
+	return
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) AfterIncludeTxPayload(txHash []byte, payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, publicKeyList [][]byte, blockHeight uint64, dataStorage *data_storage.DataStorage) (err error) {
+
+	ast, err := dataStorage.Asts.Get(string(payloadExtra.AssetId))
+	if err != nil {
+		return
+	}
+
+	if ast == nil {
+		return errors.New("Asset was not found")
+	}
+
+	if !bytes.Equal(payloadExtra.AssetUpdatePublicKey, ast.UpdatePublicKey) {
+		return errors.New("Asset UpdatePublicKey is not matching")
+	}
+
+	if payloadExtra.ChangeDescription {
+		if err = ast.SetDescription(payloadExtra.Description); err != nil {
+			return
+		}
+	}
+	if payloadExtra.ChangeData {
+		if err = ast.SetData(payloadExtra.Data); err != nil {
+			return
+		}
+	}
+	if payloadExtra.ChangeMaxSupply {
+		if err = ast.SetMaxSupply(payloadExtra.MaxSupply); err != nil {
+			return
+		}
+	}
+	if len(payloadExtra.NewUpdatePublicKey) > 0 {
+		if err = ast.SetUpdatePublicKey(payloadExtra.NewUpdatePublicKey); err != nil {
+			return
+		}
+	}
+	if len(payloadExtra.NewSupplyPublicKey) > 0 {
+		if err = ast.SetSupplyPublicKey(payloadExtra.NewSupplyPublicKey); err != nil {
+			return
+		}
+	}
+
+	if err = ast.Validate(); err != nil {
+		return
+	}
+
+	return dataStorage.Asts.Update(string(payloadExtra.AssetId), ast)
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) ComputeAllKeys(out map[string]bool) {
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) VerifyExtraSignature(hashForSignature []byte, payloadStatement *crypto.Statement) bool {
+	return crypto.VerifySignature(hashForSignature, payloadExtra.AssetSignature, payloadExtra.AssetUpdatePublicKey)
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) Validate(payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, payloadParity bool) error {
+	if !bytes.Equal(payloadAsset, config_coins.NATIVE_ASSET_FULL) {
+		return errors.New("payloadAsset must be NATIVE_ASSET_FULL")
+	}
+	if bytes.Equal(payloadExtra.AssetId, config_coins.NATIVE_ASSET_FULL) {
+		return errors.New("AssetId can not be NATIVE_ASSET_FULL")
+	}
+	if !payloadExtra.ChangeDescription && !payloadExtra.ChangeData && !payloadExtra.ChangeMaxSupply && len(payloadExtra.NewUpdatePublicKey) == 0 && len(payloadExtra.NewSupplyPublicKey) == 0 {
+		return errors.New("Asset update has no changes")
+	}
+	if !payloadExtra.ChangeDescription && len(payloadExtra.Description) > 0 {
+		return errors.New("Description must be empty")
+	}
+	if !payloadExtra.ChangeData && len(payloadExtra.Data) > 0 {
+		return errors.New("Data must be empty")
+	}
+	if !payloadExtra.ChangeMaxSupply && payloadExtra.MaxSupply != 0 {
+		return errors.New("MaxSupply must be zero")
+	}
+	if len(payloadExtra.NewUpdatePublicKey) != 0 && len(payloadExtra.NewUpdatePublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid New Update Public Key")
+	}
+	if len(payloadExtra.NewSupplyPublicKey) != 0 && len(payloadExtra.NewSupplyPublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid New Supply Public Key")
+	}
+	if len(payloadExtra.AssetUpdatePublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid Public Key")
+	}
+	if len(payloadExtra.AssetSignature) != cryptography.SignatureSize {
+		return errors.New("Invalid Signature")
+	}
+	return nil
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) Serialize(w *advanced_buffers.BufferWriter, inclSignature bool) {
+	w.Write(payloadExtra.AssetId)
+
+	w.WriteBool(payloadExtra.ChangeDescription)
+	if payloadExtra.ChangeDescription {
+		w.WriteString(payloadExtra.Description)
+	}
+
+	w.WriteBool(payloadExtra.ChangeData)
+	if payloadExtra.ChangeData {
+		w.WriteVariableBytes(payloadExtra.Data)
+	}
+
+	w.WriteBool(payloadExtra.ChangeMaxSupply)
+	if payloadExtra.ChangeMaxSupply {
+		w.WriteUvarint(payloadExtra.MaxSupply)
+	}
+
+	w.WriteBool(len(payloadExtra.NewUpdatePublicKey) > 0)
+	if len(payloadExtra.NewUpdatePublicKey) > 0 {
+		w.Write(payloadExtra.NewUpdatePublicKey)
+	}
+
+	w.WriteBool(len(payloadExtra.NewSupplyPublicKey) > 0)
+	if len(payloadExtra.NewSupplyPublicKey) > 0 {
+		w.Write(payloadExtra.NewSupplyPublicKey)
+	}
+
+	w.Write(payloadExtra.AssetUpdatePublicKey)
+	if inclSignature {
+		w.Write(payloadExtra.AssetSignature)
+	}
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+
+	if payloadExtra.AssetId, err = r.ReadBytes(config_coins.ASSET_LENGTH); err != nil {
+		return
+	}
+
+	if payloadExtra.ChangeDescription, err = r.ReadBool(); err != nil {
+		return
+	}
+	if payloadExtra.ChangeDescription {
+		if payloadExtra.Description, err = r.ReadString(1024); err != nil {
+			return
+		}
+	}
+
+	if payloadExtra.ChangeData, err = r.ReadBool(); err != nil {
+		return
+	}
+	if payloadExtra.ChangeData {
+		if payloadExtra.Data, err = r.ReadVariableBytes(5120); err != nil {
+			return
+		}
+	}
+
+	if payloadExtra.ChangeMaxSupply, err = r.ReadBool(); err != nil {
+		return
+	}
+	if payloadExtra.ChangeMaxSupply {
+		if payloadExtra.MaxSupply, err = r.ReadUvarint(); err != nil {
+			return
+		}
+	}
+
+	var hasKey bool
+	if hasKey, err = r.ReadBool(); err != nil {
+		return
+	}
+	if hasKey {
+		if payloadExtra.NewUpdatePublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+			return
+		}
+	}
+
+	if hasKey, err = r.ReadBool(); err != nil {
+		return
+	}
+	if hasKey {
+		if payloadExtra.NewSupplyPublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+			return
+		}
+	}
+
+	if payloadExtra.AssetUpdatePublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+		return
+	}
+	if payloadExtra.AssetSignature, err = r.ReadBytes(cryptography.SignatureSize); err != nil {
+		return
+	}
+	return
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdate) UpdateStatement(payloadStatement *crypto.Statement) error {
+	return nil
+}
@@ -13,8 +13,9 @@ type TransactionZetherPayloadExtraConditionalPayment struct {
 	TransactionZetherPayloadExtraInterface
 	Deadline           uint64
 	DefaultResolution  bool //true for receiver, false for refunding sender
-	MultisigThreshold  byte
+	MultisigThreshold  byte //zero for a hashlock payment
 	MultisigPublicKeys [][]byte
+	Hashlock           []byte //SHA256 of the preimage which resolves the payment to the receiver
 }
 
 func (payloadExtra *TransactionZetherPayloadExtraConditionalPayment) BeforeIncludeTxPayload(txHash []byte, payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, publicKeyList [][]byte, blockHeight uint64, dataStorage *data_storage.DataStorage) (err error) {
@@ -51,12 +52,24 @@ func (payloadExtra *TransactionZetherPayloadExtraConditionalPayment) Validate(pa
 	if payloadStatement.Fee != 0 {
 		return errors.New("Payload Fee must be zero")
 	}
+	if payloadExtra.MultisigThreshold == 0 {
+		if len(payloadExtra.Hashlock) != cryptography.HashSize {
+			return errors.New("Hashlock is invalid")
+		}
+		if len(payloadExtra.MultisigPublicKeys) != 0 {
+			return errors.New("Hashlock payment should not have multisig public keys")
+		}
+		if payloadExtra.DefaultResolution {
+			return errors.New("Hashlock payment should refund the sender after the deadline")
+		}
+		return nil
+	}
+	if len(payloadExtra.Hashlock) != 0 {
+		return errors.New("Multisig payment should not have a hashlock")
+	}
 	if len(payloadExtra.MultisigPublicKeys) > 5 {
 		return errors.New("PublicKeys list is limited to 5 elements")
 	}
-	if payloadExtra.MultisigThreshold == 0 {
-		return errors.New("Threshold should not be zero")
-	}
 	if int(payloadExtra.MultisigThreshold) > len(payloadExtra.MultisigPublicKeys) {
 		return errors.New("Invalid threshold")
 	}
@@ -74,6 +87,10 @@ func (payloadExtra *TransactionZetherPayloadExtraConditionalPayment) Serialize(w
 	w.WriteUvarint(payloadExtra.Deadline)
 	w.WriteBool(payloadExtra.DefaultResolution)
 	w.WriteByte(payloadExtra.MultisigThreshold)
+	if payloadExtra.MultisigThreshold == 0 {
+		w.Write(payloadExtra.Hashlock)
+		return
+	}
 	w.WriteByte(byte(len(payloadExtra.MultisigPublicKeys)))
 	for _, pb := range payloadExtra.MultisigPublicKeys {
 		w.Write(pb)
@@ -92,6 +109,10 @@ func (payloadExtra *TransactionZetherPayloadExtraConditionalPayment) Deserialize
 	if payloadExtra.MultisigThreshold, err = r.ReadByte(); err != nil {
 		return
 	}
+	if payloadExtra.MultisigThreshold == 0 {
+		payloadExtra.Hashlock, err = r.ReadBytes(cryptography.HashSize)
+		return
+	}
 
 	var n byte
 	if n, err = r.ReadByte(); err != nil {
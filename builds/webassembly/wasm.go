@@ -75,18 +75,20 @@ func initialize() {
 			"generateNewAddress": js.FuncOf(generateNewAddress),
 		}),
 		"cryptography": js.ValueOf(map[string]any{
-			"HASH_SIZE":            js.ValueOf(cryptography.HashSize),
-			"PRIVATE_KEY_SIZE":     js.ValueOf(cryptography.PrivateKeySize),
-			"SEED_SIZE":            js.ValueOf(cryptography.SeedSize),
-			"PUBLIC_KEY_SIZE":      js.ValueOf(cryptography.PublicKeySize),
-			"SIGNATURE_SIZE":       js.ValueOf(cryptography.SignatureSize),
-			"RIPEMD_SIZE":          js.ValueOf(cryptography.RipemdSize),
-			"PUBLIC_KEY_HASH_SIZE": js.ValueOf(cryptography.PublicKeyHashSize),
-			"CHECK_SUM_SIZE":       js.ValueOf(cryptography.ChecksumSize),
-			"sha3":                 js.FuncOf(sha3),
-			"ripemd":               js.FuncOf(ripemd),
-			"sign":                 js.FuncOf(sign),
-			"verify":               js.FuncOf(verify),
+			"HASH_SIZE":              js.ValueOf(cryptography.HashSize),
+			"PRIVATE_KEY_SIZE":       js.ValueOf(cryptography.PrivateKeySize),
+			"SEED_SIZE":              js.ValueOf(cryptography.SeedSize),
+			"PUBLIC_KEY_SIZE":        js.ValueOf(cryptography.PublicKeySize),
+			"SIGNATURE_SIZE":         js.ValueOf(cryptography.SignatureSize),
+			"RIPEMD_SIZE":            js.ValueOf(cryptography.RipemdSize),
+			"PUBLIC_KEY_HASH_SIZE":   js.ValueOf(cryptography.PublicKeyHashSize),
+			"CHECK_SUM_SIZE":         js.ValueOf(cryptography.ChecksumSize),
+			"HASHLOCK_PREIMAGE_SIZE": js.ValueOf(config.HASHLOCK_PREIMAGE_SIZE),
+			"sha3":                   js.FuncOf(sha3),
+			"hashlock":               js.FuncOf(hashlock),
+			"ripemd":                 js.FuncOf(ripemd),
+			"sign":                   js.FuncOf(sign),
+			"verify":                 js.FuncOf(verify),
 		}),
 		"network": js.ValueOf(map[string]any{
 			"networkDisconnect":                      js.FuncOf(networkDisconnect),
@@ -119,6 +121,9 @@ func initialize() {
 				"createSimpleTx": js.FuncOf(createSimpleTx),
 			}),
 			"signResolutionConditionalPayment": js.FuncOf(signResolutionConditionalPayment),
+			"signSimpleTxMultisig":             js.FuncOf(signSimpleTxMultisig),
+			"aggregateSimpleTxMultisig":        js.FuncOf(aggregateSimpleTxMultisig),
+			"computeMultisigKey":               js.FuncOf(computeMultisigKey),
 		}),
 		"mempool": js.ValueOf(map[string]any{
 			"mempoolRemoveTx": js.FuncOf(mempoolRemoveTx),
@@ -137,8 +142,10 @@ func initialize() {
 				}),
 				"transactionSimple": js.ValueOf(map[string]any{
 					"ScriptType": js.ValueOf(map[string]any{
-						"SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY":     js.ValueOf(uint64(transaction_simple.SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY)),
-						"SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT": js.ValueOf(uint64(transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT)),
+						"SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY":              js.ValueOf(uint64(transaction_simple.SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY)),
+						"SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT":          js.ValueOf(uint64(transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT)),
+						"SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK": js.ValueOf(uint64(transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK)),
+						"SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER":         js.ValueOf(uint64(transaction_simple.SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER)),
 					}),
 				}),
 				"transactionZether": js.ValueOf(map[string]any{
@@ -151,6 +158,10 @@ func initialize() {
 						"SCRIPT_ASSET_SUPPLY_INCREASE": js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE)),
 						"SCRIPT_PLAIN_ACCOUNT_FUND":    js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND)),
 						"SCRIPT_CONDITIONAL_PAYMENT":   js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT)),
+						"SCRIPT_ASSET_SUPPLY_DECREASE": js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE)),
+						"SCRIPT_ASSET_PAUSE":           js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_PAUSE)),
+						"SCRIPT_ASSET_FREEZE":          js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_FREEZE)),
+						"SCRIPT_ASSET_UPDATE":          js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_UPDATE)),
 					}),
 				}),
 			}),
@@ -177,6 +188,11 @@ func initialize() {
 						"SUBSCRIPTION_ASSET":                js.ValueOf(int(api_code_types.SUBSCRIPTION_ASSET)),
 						"SUBSCRIPTION_REGISTRATION":         js.ValueOf(int(api_code_types.SUBSCRIPTION_REGISTRATION)),
 						"SUBSCRIPTION_TRANSACTION":          js.ValueOf(int(api_code_types.SUBSCRIPTION_TRANSACTION)),
+						"SUBSCRIPTION_CHAIN":                js.ValueOf(int(api_code_types.SUBSCRIPTION_CHAIN)),
+						"SUBSCRIPTION_MEMPOOL":              js.ValueOf(int(api_code_types.SUBSCRIPTION_MEMPOOL)),
+						"SUBSCRIPTION_CONDITIONAL_PAYMENT":  js.ValueOf(int(api_code_types.SUBSCRIPTION_CONDITIONAL_PAYMENT)),
+						"SUBSCRIPTION_PENDING_STAKE":        js.ValueOf(int(api_code_types.SUBSCRIPTION_PENDING_STAKE)),
+						"SUBSCRIPTION_REORG_ALERT":          js.ValueOf(int(api_code_types.SUBSCRIPTION_REORG_ALERT)),
 					}),
 				}),
 			}),
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/base64"
+	"pandora-pay/blockchain/data_storage/conditional_payments_list/conditional_payment"
 	"pandora-pay/builds/webassembly/webassembly_utils"
 	"pandora-pay/cryptography"
 	"pandora-pay/cryptography/crypto"
@@ -22,6 +23,20 @@ func sha3(this js.Value, args []js.Value) interface{} {
 	})
 }
 
+func hashlock(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		preimage, err := base64.StdEncoding.DecodeString(args[0].String())
+		if err != nil {
+			return nil, err
+		}
+
+		out := conditional_payment.ComputeHashlock(preimage)
+
+		return base64.StdEncoding.EncodeToString(out), nil
+	})
+}
+
 func ripemd(this js.Value, args []js.Value) interface{} {
 	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
 
@@ -1,15 +1,20 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"pandora-pay/addresses"
 	"pandora-pay/app"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account_multisig"
+	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
 	"pandora-pay/builds/webassembly/webassembly_utils"
 	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/txs_builder/wizard"
+	"sort"
 	"syscall/js"
 )
 
@@ -33,6 +38,9 @@ func createSimpleTx(this js.Value, args []js.Value) interface{} {
 			Fee        *wizard.WizardTransactionFee  `json:"fee"`
 			FeeVersion bool                          `json:"feeVersion"`
 			Height     uint64                        `json:"height"`
+			//multisig plain account paying the tx. The threshold is required to estimate the fee
+			MultisigAccount   []byte `json:"multisigAccount"`
+			MultisigThreshold byte   `json:"multisigThreshold"`
 		}{}
 
 		//read txScript
@@ -49,6 +57,10 @@ func createSimpleTx(this js.Value, args []js.Value) interface{} {
 			txData.Extra = &wizard.WizardTxSimpleExtraUpdateAssetFeeLiquidity{}
 		case transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT:
 			txData.Extra = &wizard.WizardTxSimpleExtraResolutionConditionalPayment{}
+		case transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK:
+			txData.Extra = &wizard.WizardTxSimpleExtraResolutionConditionalPaymentHashlock{}
+		case transaction_simple.SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER:
+			txData.Extra = &wizard.WizardTxSimpleExtraPlainAccountMultisigRegister{}
 		default:
 			txData.Extra = nil
 			return nil, errors.New("Invalid Tx Simple Script")
@@ -64,6 +76,8 @@ func createSimpleTx(this js.Value, args []js.Value) interface{} {
 			txData.Fee,
 			txData.Nonce,
 			nil,
+			txData.MultisigAccount,
+			txData.MultisigThreshold,
 		}
 
 		if len(txData.Sender) > 0 {
@@ -136,3 +150,82 @@ func signResolutionConditionalPayment(this js.Value, args []js.Value) interface{
 
 	})
 }
+
+func signSimpleTxMultisig(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		if len(args) != 3 || args[0].Type() != js.TypeObject || args[1].Type() != js.TypeString || args[2].Type() != js.TypeString {
+			return nil, errors.New("Argument must be the tx, the address and the password")
+		}
+
+		if err := app.Wallet.Encryption.CheckPassword(args[2].String(), false); err != nil {
+			return nil, err
+		}
+
+		tx := &transaction.Transaction{}
+		if err := tx.Deserialize(advanced_buffers.NewBufferReader(webassembly_utils.GetBytes(args[0]))); err != nil {
+			return nil, err
+		}
+
+		walletAddr, err := app.Wallet.GetWalletAddressByEncodedAddress(args[1].String(), true)
+		if err != nil {
+			return nil, err
+		}
+		if walletAddr.PrivateKey == nil {
+			return nil, errors.New("Can't be used for transactions as the private key is missing")
+		}
+
+		if err = wizard.SignSimpleTxMultisig(tx, walletAddr.PrivateKey.Key); err != nil {
+			return nil, err
+		}
+
+		return webassembly_utils.ConvertBytes(tx.Bloom.Serialized), nil
+	})
+}
+
+func aggregateSimpleTxMultisig(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		if len(args) < 2 {
+			return nil, errors.New("At least two transactions are required")
+		}
+
+		txs := make([]*transaction.Transaction, len(args))
+		for i := range args {
+			txs[i] = &transaction.Transaction{}
+			if err := txs[i].Deserialize(advanced_buffers.NewBufferReader(webassembly_utils.GetBytes(args[i]))); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := wizard.AggregateSimpleTxMultisigSignatures(txs[0], txs[1:]); err != nil {
+			return nil, err
+		}
+
+		return webassembly_utils.ConvertBytes(txs[0].Bloom.Serialized), nil
+	})
+}
+
+func computeMultisigKey(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		data := &struct {
+			Threshold  byte     `json:"threshold"`
+			PublicKeys [][]byte `json:"publicKeys"`
+		}{}
+
+		if err := webassembly_utils.UnmarshalBytes(args[0], data); err != nil {
+			return nil, err
+		}
+
+		sort.Slice(data.PublicKeys, func(i, j int) bool {
+			return bytes.Compare(data.PublicKeys[i], data.PublicKeys[j]) < 0
+		})
+
+		if err := plain_account_multisig.ValidateKeys(data.Threshold, data.PublicKeys); err != nil {
+			return nil, err
+		}
+
+		return webassembly_utils.ConvertBytes(plain_account_multisig.ComputeMultisigKey(data.Threshold, data.PublicKeys)), nil
+	})
+}
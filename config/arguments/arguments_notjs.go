@@ -8,7 +8,7 @@ package arguments
 var commands = `PANDORA PAY.
 
 Usage:
-  pandorapay [--pprof] [--network=network] [--debug] [--gui-type=type] [--forging] [--new-devnet] [--run-testnet-script] [--node-name=name] [--tcp-server-port=port] [--tcp-server-address=address] [--tcp-server-auto-tls-certificate] [--tcp-server-tls-cert-file=path] [--tcp-server-tls-key-file=path] [--instance=prefix] [--instance-id=id] [--set-genesis=genesis] [--create-new-genesis=args] [--store-wallet-type=type] [--store-chain-type=type] [--node-consensus=type] [--tcp-max-clients=limit] [--tcp-max-server-sockets=limit] [--node-provide-extended-info-app=bool] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--hcaptcha-secret=args] [--faucet-testnet-enabled=args] [--delegator-enabled=bool] [--delegator-require-auth=bool] [--delegates-maximum=args] [--auth-users=args] [--light-computations] [--balance-decryptor-disable-init] [--balance-decryptor-table-size=size] [--tcp-connections-ready=threshold] [--exit] [--skip-init-sync] [--tcp-server-url=url] [--tcp-proxy=PROXY]
+  pandorapay [--pprof] [--network=network] [--debug] [--gui-type=type] [--forging] [--new-devnet] [--run-testnet-script] [--node-name=name] [--tcp-server-port=port] [--tcp-server-address=address] [--tcp-server-auto-tls-certificate] [--tcp-server-tls-cert-file=path] [--tcp-server-tls-key-file=path] [--instance=prefix] [--instance-id=id] [--set-genesis=genesis] [--checkpoint=height:hash] [--max-reorg-depth=blocks] [--create-new-genesis=args] [--store-wallet-type=type] [--store-chain-type=type] [--node-consensus=type] [--tcp-max-clients=limit] [--tcp-max-server-sockets=limit] [--node-provide-extended-info-app=bool] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--hcaptcha-secret=args] [--faucet-testnet-enabled=args] [--delegator-enabled=bool] [--delegator-require-auth=bool] [--delegates-maximum=args] [--auth-file=path] [--auth-new-key=args] [--api-rate-limit=rate] [--api-rate-limit-auth=rate] [--api-rate-limit-peer=rate] [--api-rate-costs=args] [--light-computations] [--balance-decryptor-disable-init] [--balance-decryptor-table-size=size] [--balance-decryptor-table-max-size=size] [--tcp-connections-ready=threshold] [--mempool-max-txs=limit] [--mempool-max-size=size] [--mempool-tx-ttl=seconds] [--mempool-tx-ttl-blocks=blocks] [--mempool-save-interval=seconds] [--export-snapshot=path] [--import-snapshot=path] [--snapshot-trusted-hash=hash] [--exit] [--skip-init-sync] [--tcp-server-url=url] [--tcp-proxy=PROXY]
   pandorapay -h | --help
   pandorapay -v | --version
 
@@ -23,9 +23,11 @@ Options:
   --new-devnet                                       Create a new devnet genesis.
   --run-testnet-script                               Run testnet script which will create dummy transactions in the network.
   --set-genesis=genesis                              Manually set the Genesis via a JSON. By using argument "file" it will read it via a file.
+  --checkpoint=height:hash                           Reject the chains which do not contain the block hash (hex) at the height. Multiple checkpoints are separated by ","
+  --max-reorg-depth=blocks                           Maximum number of blocks a fork can roll back. Use 0 to disable it [default: 100].
   --create-new-genesis=args                          Create a new Genesis. Useful for creating a new private testnet. Argument must be "0.stake,1.stake,2.stake"
-  --store-wallet-type=type                           Set Wallet Store Type. Accepted values: "bolt|bunt|bunt-memory|memory". [default: bolt]
-  --store-chain-type=type                            Set Chain Store Type. Accepted values: "bolt|bunt|bunt-memory|memory".  [default: bolt]
+  --store-wallet-type=type                           Set Wallet Store Type. Accepted values: "bolt|bunt|bunt-memory|leveldb|memory". [default: bolt]
+  --store-chain-type=type                            Set Chain Store Type. Accepted values: "bolt|bunt|bunt-memory|leveldb|memory".  [default: bolt]
   --forging                                          Start Forging blocks.
   --node-name=name                                   Change node name.
   --node-consensus=type                              Consensus type. Accepted values: "full|app|none" [default: full].
@@ -51,10 +53,24 @@ Options:
   --delegator-enabled=bool                           Enable Delegator. Will allow other users to Delegate to the node. Use "true" to enable it
   --delegator-require-auth=bool                      Delegator will require authentication.
   --delegates-maximum=args                           Maximum number of Delegates
-  --auth-users=args                                  Credential for Authenticated Users. Arguments must be a JSON "[{'user': 'username', 'pass': 'secret'}]".
+  --auth-file=path                                   JSON file with the API credentials. Only the salted hashes of the secrets are stored.
+  --auth-new-key=args                                Add an API credential to the --auth-file and print its key. Argument must be a JSON "{'user': 'username', 'roles': ['wallet-read']}". Roles: wallet-read, wallet-spend, delegator, admin.
+  --api-rate-limit=rate                              API tokens refilled every second for an ip. A bucket holds 10 seconds of tokens. Use 0 to disable [default: 100].
+  --api-rate-limit-auth=rate                         API tokens refilled every second for an authenticated user. Use 0 to disable [default: 1000].
+  --api-rate-limit-peer=rate                         API tokens refilled every second for a peer the node connected to. Use 0 to disable [default: 1000].
+  --api-rate-costs=args                              Override the tokens cost of the API methods. Argument must be a JSON "{'block-complete': 10, 'faucet/coins': 100}".
   --light-computations                               Reduces the computations for a testnet node.
   --balance-decryptor-disable-init                   Disable first balance decryptor initialization. 
   --balance-decryptor-table-size=size                Balance Decryptor initial table size. [default: 23]
+  --balance-decryptor-table-max-size=size            Grow the Balance Decryptor table in the background up to this size. The table is saved in the _build folder and reused after restarts.
+  --mempool-max-txs=limit                            Maximum number of transactions kept in the mempool [default: 50000].
+  --mempool-max-size=size                            Maximum size of the transactions kept in the mempool in MB [default: 128].
+  --mempool-tx-ttl=seconds                           Transactions are evicted from the mempool after this time. Use 0 to disable it [default: 10800].
+  --mempool-tx-ttl-blocks=blocks                     Transactions are evicted from the mempool after this number of blocks. Use 0 to disable it [default: 100].
+  --mempool-save-interval=seconds                    The pending transactions are saved when they changed at this interval [default: 30].
+  --export-snapshot=path                             Export the chain state and the last blocks into a snapshot file after the chain is loaded.
+  --import-snapshot=path                             Bootstrap an empty chain store from a snapshot file. It requires --snapshot-trusted-hash and a network where the blocks include the StateRoot (devnet for now).
+  --snapshot-trusted-hash=hash                       Trusted block hash (base64) included in the imported snapshot. The state is verified against it and the chain continues from it.
   --exit                                             Exit node.
   --skip-init-sync                                   Skip sync wait at when the node started. Useful when creating a new testnet.
 `
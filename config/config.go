@@ -3,12 +3,15 @@ package config
 import (
 	"errors"
 	"github.com/blang/semver/v4"
+	"math"
 	"math/big"
 	"math/rand"
 	"pandora-pay/config/arguments"
 	"pandora-pay/config/config_forging"
+	"pandora-pay/config/config_mempool"
 	"pandora-pay/config/config_nodes"
 	"runtime"
+	"strconv"
 	"time"
 )
 
@@ -28,6 +31,7 @@ var (
 const (
 	TRANSACTIONS_MAX_DATA_LENGTH = 512
 	TRANSACTIONS_ZETHER_RING_MAX = 256
+	HASHLOCK_PREIMAGE_SIZE       = 32 //same size as the HTLC of other chains
 )
 
 const (
@@ -50,7 +54,11 @@ const (
 	BLOCK_TIME              uint64 = 90 //seconds
 	DIFFICULTY_BLOCK_WINDOW uint64 = 10
 	FORK_MAX_UNCLE_ALLOWED  uint64 = 60
-	FORK_MAX_DOWNLOAD       uint64 = 20
+	FORK_MAX_DOWNLOAD       uint64 = 200 //blocks downloaded in parallel before being added to the chain
+	FORK_MAX_HEADERS        uint64 = 500 //headers returned by a single block-headers request
+	FORK_FIRST_HEADERS      uint64 = 8   //headers requested first when searching the common ancestor. The next requests double it
+	FORK_DOWNLOAD_WORKERS          = 8
+	FORK_DOWNLOAD_RETRIES          = 3
 )
 
 var (
@@ -65,6 +73,7 @@ var (
 	API_MEMPOOL_MAX_TRANSACTIONS = 50
 	API_ACCOUNT_MAX_TXS          = uint64(10)
 	API_ASSETS_INFO_MAX_RESULTS  = 10
+	API_LIST_MAX_RESULTS         = 100
 )
 
 var (
@@ -74,6 +83,25 @@ var (
 	BIG_FLOAT_MAX_256 = new(big.Float).SetInt(BIG_INT_MAX_256) // 0xFFFFFFFF....
 )
 
+var (
+	FORK_MAX_REORG_DEPTH uint64 = 100 //blocks a fork can roll back. Zero disables the check
+)
+
+// heights from which the consensus changes are activated. The changes are not scheduled yet on the mainnet and testnet
+const (
+	MAIN_NET_FORK_HEIGHT_MERKLE_TREE uint64 = math.MaxUint64
+	TEST_NET_FORK_HEIGHT_MERKLE_TREE uint64 = math.MaxUint64
+	DEV_NET_FORK_HEIGHT_MERKLE_TREE  uint64 = 0
+	MAIN_NET_FORK_HEIGHT_STATE_ROOT  uint64 = math.MaxUint64
+	TEST_NET_FORK_HEIGHT_STATE_ROOT  uint64 = math.MaxUint64
+	DEV_NET_FORK_HEIGHT_STATE_ROOT   uint64 = 0
+)
+
+var (
+	FORK_HEIGHT_MERKLE_TREE = MAIN_NET_FORK_HEIGHT_MERKLE_TREE //merkle tree with domain separation and without duplicated nodes
+	FORK_HEIGHT_STATE_ROOT  = MAIN_NET_FORK_HEIGHT_STATE_ROOT  //blocks include the StateRoot
+)
+
 var (
 	NODE_PROVIDE_EXTENDED_INFO_APP bool
 	NODE_CONSENSUS                 NodeConsensusType = NODE_CONSENSUS_TYPE_FULL
@@ -94,12 +122,16 @@ func InitConfig() (err error) {
 		NETWORK_SELECTED_DELEGATOR_NODES = config_nodes.TEST_NET_DELEGATOR_NODES
 		NETWORK_SELECTED_NAME = TEST_NET_NETWORK_NAME
 		NETWORK_SELECTED_BYTE_PREFIX = TEST_NET_NETWORK_BYTE_PREFIX
+		FORK_HEIGHT_MERKLE_TREE = TEST_NET_FORK_HEIGHT_MERKLE_TREE
+		FORK_HEIGHT_STATE_ROOT = TEST_NET_FORK_HEIGHT_STATE_ROOT
 	} else if arguments.Arguments["--network"] == "devnet" {
 		NETWORK_SELECTED = DEV_NET_NETWORK_BYTE
 		NETWORK_SELECTED_SEEDS = DEV_NET_SEED_NODES
 		NETWORK_SELECTED_DELEGATOR_NODES = config_nodes.DEV_NET_DELEGATOR_NODES
 		NETWORK_SELECTED_NAME = DEV_NET_NETWORK_NAME
 		NETWORK_SELECTED_BYTE_PREFIX = DEV_NET_NETWORK_BYTE_PREFIX
+		FORK_HEIGHT_MERKLE_TREE = DEV_NET_FORK_HEIGHT_MERKLE_TREE
+		FORK_HEIGHT_STATE_ROOT = DEV_NET_FORK_HEIGHT_STATE_ROOT
 	} else {
 		return errors.New("selected --network is invalid. Accepted only: mainnet, testnet, devnet")
 	}
@@ -112,6 +144,12 @@ func InitConfig() (err error) {
 		LIGHT_COMPUTATIONS = true
 	}
 
+	if arguments.Arguments["--max-reorg-depth"] != nil {
+		if FORK_MAX_REORG_DEPTH, err = strconv.ParseUint(arguments.Arguments["--max-reorg-depth"].(string), 10, 64); err != nil {
+			return
+		}
+	}
+
 	NODE_PROVIDE_EXTENDED_INFO_APP = false
 	switch arguments.Arguments["--node-consensus"] {
 	case "full":
@@ -139,6 +177,10 @@ func InitConfig() (err error) {
 		return
 	}
 
+	if err = config_mempool.InitConfig(); err != nil {
+		return
+	}
+
 	return
 }
 
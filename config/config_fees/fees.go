@@ -6,6 +6,13 @@ var (
 	FEE_PER_BYTE_EXTRA_SPACE = uint64(100)
 )
 
+var (
+	FEE_ESTIMATE_BLOCKS      = uint64(20)  //default number of recent blocks scanned to estimate the fee
+	FEE_ESTIMATE_MAX_BLOCKS  = uint64(100) //maximum number of recent blocks that can be scanned
+	FEE_ESTIMATE_PERCENTILE  = uint64(50)  //percentile used to recommend the fee
+	FEE_ESTIMATE_PERCENTILES = []uint64{10, 25, 50, 75, 90}
+)
+
 func ComputeTxFee(size, feePerByte, extraSpace, feePerByeExtraSpace uint64) uint64 {
 	return size*feePerByte + extraSpace*feePerByeExtraSpace
 }
@@ -0,0 +1,71 @@
+package config_mempool
+
+import (
+	"errors"
+	"pandora-pay/config/arguments"
+	"strconv"
+	"time"
+)
+
+var (
+	MEMPOOL_MAX_TXS  = 50000
+	MEMPOOL_MAX_SIZE = uint64(128 * 1024 * 1024) //bytes
+
+	// percentage by which the FeePerByte of a replacing transaction must exceed the replaced one
+	MEMPOOL_REPLACE_BY_FEE_MIN_BUMP = uint64(10)
+
+	// transactions older than these are evicted. Zero disables the check
+	MEMPOOL_TX_TTL        = int64(3 * 60 * 60) //seconds
+	MEMPOOL_TX_TTL_BLOCKS = uint64(100)
+
+	// the pending transactions are saved to the store at this interval when they changed, so a crash loses only the recent ones
+	MEMPOOL_SAVE_INTERVAL = 30 * time.Second
+)
+
+func InitConfig() (err error) {
+
+	if arguments.Arguments["--mempool-max-txs"] != nil {
+		if MEMPOOL_MAX_TXS, err = strconv.Atoi(arguments.Arguments["--mempool-max-txs"].(string)); err != nil {
+			return
+		}
+		if MEMPOOL_MAX_TXS <= 0 {
+			return errors.New("--mempool-max-txs must be greater than zero")
+		}
+	}
+
+	if arguments.Arguments["--mempool-max-size"] != nil {
+		var size uint64
+		if size, err = strconv.ParseUint(arguments.Arguments["--mempool-max-size"].(string), 10, 64); err != nil {
+			return
+		}
+		if size == 0 {
+			return errors.New("--mempool-max-size must be greater than zero")
+		}
+		MEMPOOL_MAX_SIZE = size * 1024 * 1024
+	}
+
+	if arguments.Arguments["--mempool-tx-ttl"] != nil {
+		if MEMPOOL_TX_TTL, err = strconv.ParseInt(arguments.Arguments["--mempool-tx-ttl"].(string), 10, 64); err != nil {
+			return
+		}
+	}
+
+	if arguments.Arguments["--mempool-tx-ttl-blocks"] != nil {
+		if MEMPOOL_TX_TTL_BLOCKS, err = strconv.ParseUint(arguments.Arguments["--mempool-tx-ttl-blocks"].(string), 10, 64); err != nil {
+			return
+		}
+	}
+
+	if arguments.Arguments["--mempool-save-interval"] != nil {
+		var seconds uint64
+		if seconds, err = strconv.ParseUint(arguments.Arguments["--mempool-save-interval"].(string), 10, 64); err != nil {
+			return
+		}
+		if seconds == 0 {
+			return errors.New("--mempool-save-interval must be greater than zero")
+		}
+		MEMPOOL_SAVE_INTERVAL = time.Duration(seconds) * time.Second
+	}
+
+	return
+}
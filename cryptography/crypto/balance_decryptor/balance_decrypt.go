@@ -41,19 +41,20 @@ func (p PreComputeTable) Len() int           { return len(p) }
 func (p PreComputeTable) Less(i, j int) bool { return p[i] < p[j] }
 func (p PreComputeTable) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 
-// with some more smartness table can be condensed more to contain 16.3% more entries within the same size
-func createLookupTable(count, table_size int, tableComputedCn chan *LookupTable, ctx context.Context, statusCallback func(string)) {
-
-	t := make([]PreComputeTable, count, count)
+// computes the sorted entries of the points [start, end) * G
+// start and end must be multiples of 256
+func computeLookupTableEntries(start, end int, ctx context.Context, statusCallback func(string)) PreComputeTable {
 
-	if table_size&0xff != 0 {
+	if start&0xff != 0 || end&0xff != 0 {
 		panic("table size must be multiple of 256")
 	}
 
+	t := make(PreComputeTable, end-start, end-start)
+
 	//terminal := isatty.IsTerminal(os.Stdout.Fd())
 
 	var acc bn256.G1 // avoid allocations every loop
-	acc.ScalarMult(crypto.G, new(big.Int).SetUint64(0))
+	acc.ScalarMult(crypto.G, new(big.Int).SetUint64(uint64(start)))
 
 	small_table := make([]*bn256.G1, 256, 256)
 	for k := range small_table {
@@ -62,50 +63,81 @@ func createLookupTable(count, table_size int, tableComputedCn chan *LookupTable,
 
 	var compressed [33]byte
 
-	for i := range t {
-		t[i] = make([]uint64, table_size, table_size)
+	for j := start; j < end; j += 256 {
 
-		for j := 0; j < table_size; j += 256 {
+		for k := range small_table {
+			small_table[k].Set(&acc)
+			acc.Add(small_table[k], crypto.G)
+		}
+		(bn256.G1Array(small_table)).MakeAffine() // precompute everything ASAP
 
-			for k := range small_table {
-				small_table[k].Set(&acc)
-				acc.Add(small_table[k], crypto.G)
-			}
-			(bn256.G1Array(small_table)).MakeAffine() // precompute everything ASAP
+		for k := range small_table {
+			// convert acc to compressed point and extract last 5 bytes
+			//compressed := small_table[k].EncodeCompressed()
+			small_table[k].EncodeCompressedToBuf(compressed[:])
+
+			// replace last bytes by j in coded form
+			compressed[32] = byte(uint64(j+k) & 0xff)
+			compressed[31] = byte((uint64(j+k) >> 8) & 0xff)
+			compressed[30] = byte((uint64(j+k) >> 16) & 0xff)
 
-			for k := range small_table {
-				// convert acc to compressed point and extract last 5 bytes
-				//compressed := small_table[k].EncodeCompressed()
-				small_table[k].EncodeCompressedToBuf(compressed[:])
+			t[j-start+k] = binary.BigEndian.Uint64(compressed[25:])
+		}
 
-				// replace last bytes by j in coded form
-				compressed[32] = byte(uint64(j+k) & 0xff)
-				compressed[31] = byte((uint64(j+k) >> 8) & 0xff)
-				compressed[30] = byte((uint64(j+k) >> 16) & 0xff)
+		if j&8191 == 0 && runtime.GOARCH == "wasm" {
 
-				(t)[i][j+k] = binary.BigEndian.Uint64(compressed[25:])
+			statusCallback(fmt.Sprintf("%.2f%%", float32(j-start)*100/float32(len(t))))
+
+			select {
+			case <-ctx.Done():
+				return nil
+			default:
 			}
+		}
+	}
 
-			if j&8191 == 0 && runtime.GOARCH == "wasm" {
+	//fmt.Printf("sorting start\n")
+	sort.Sort(t)
+	//fmt.Printf("sortingcomplete\n")
 
-				statusCallback(fmt.Sprintf("%.2f%%", float32(j)*100/float32(len((t)[i]))))
+	return t
+}
 
-				select {
-				case <-ctx.Done():
-					tableComputedCn <- nil
-					return
-				default:
-				}
-			}
+// merges two sorted tables into a new sorted table
+func mergeLookupTables(a, b PreComputeTable) PreComputeTable {
+
+	t := make(PreComputeTable, len(a)+len(b), len(a)+len(b))
+
+	i, j := 0, 0
+	for k := range t {
+		if j == len(b) || (i < len(a) && a[i] <= b[j]) {
+			t[k] = a[i]
+			i++
+		} else {
+			t[k] = b[j]
+			j++
 		}
+	}
 
-		//fmt.Printf("sorting start\n")
-		sort.Sort(t[i])
-		//fmt.Printf("sortingcomplete\n")
-		//bar.Finish()
+	return t
+}
+
+// with some more smartness table can be condensed more to contain 16.3% more entries within the same size
+// the table is grown from the previous table by computing only the missing entries
+func createLookupTable(previous PreComputeTable, table_size int, tableComputedCn chan *LookupTable, ctx context.Context, statusCallback func(string)) {
+
+	t := computeLookupTableEntries(len(previous), table_size, ctx, statusCallback)
+	if t == nil {
+		tableComputedCn <- nil
+		return
 	}
+
+	if len(previous) > 0 {
+		t = mergeLookupTables(previous, t)
+	}
+
 	//fmt.Printf("lookuptable complete\n")
-	t1 := LookupTable(t)
+	t1 := LookupTable{t}
 
 	tableComputedCn <- &t1
 }
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"os"
 	"pandora-pay/cryptography/bn256"
 	"pandora-pay/cryptography/crypto"
 	"runtime"
+	"strconv"
 	"sync"
 )
 
@@ -18,6 +20,9 @@ type BalanceDecryptorType struct {
 	tableSize       int
 	tableLookup     *LookupTable
 	readyCn         chan struct{}
+	tablePath       string
+	tableFile       *tableFile //the file mapped by tableLookup
+	lock            *sync.RWMutex
 }
 
 func (this *BalanceDecryptorType) TryDecryptBalance(p *bn256.G1, matchBalance uint64) bool {
@@ -34,14 +39,59 @@ func (this *BalanceDecryptorType) DecryptBalance(p *bn256.G1, tryPreviousValue b
 		}
 	}
 
-	tableLookup := this.SetTableSize(0, context.Background(), statusCallback)
-	if tableLookup == nil {
+	if this.SetTableSize(0, context.Background(), statusCallback) == nil {
 		return 0, errors.New("It was stopped")
 	}
 
+	//the table can be replaced by GrowTableSize. The lock is not held during the lookup, the mapped file is kept until the lookup is done
+	this.lock.RLock()
+	tableLookup, file := this.tableLookup, this.tableFile
+	if file != nil {
+		file.acquire()
+	}
+	this.lock.RUnlock()
+
+	if file != nil {
+		defer file.release()
+	}
+
 	return tableLookup.Lookup(p, ctx, statusCallback)
 }
 
+// SetTablePath must be called before the table is initialized. The table will be loaded from this file and saved into it
+func (this *BalanceDecryptorType) SetTablePath(path string) {
+	this.tablePath = path
+}
+
+func (this *BalanceDecryptorType) GetTableSize() int {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return this.tableSize
+}
+
+func (this *BalanceDecryptorType) loadTableFile(statusCallback func(string)) *tableFile {
+	if this.tablePath == "" {
+		return nil
+	}
+	file, err := readTableFile(this.tablePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			statusCallback("Table file is invalid. " + err.Error())
+		}
+		return nil
+	}
+	return file
+}
+
+func (this *BalanceDecryptorType) saveTableFile(tableLookup *LookupTable, statusCallback func(string)) {
+	if this.tablePath == "" {
+		return
+	}
+	if err := writeTableFile(this.tablePath, (*tableLookup)[0]); err != nil {
+		statusCallback("Table could not be saved. " + err.Error())
+	}
+}
+
 func (this *BalanceDecryptorType) SetTableSize(newTableSize int, ctx context.Context, statusCallback func(string)) *LookupTable {
 
 	this.once.Do(func() {
@@ -57,24 +107,114 @@ func (this *BalanceDecryptorType) SetTableSize(newTableSize int, ctx context.Con
 			panic("Table Size is incorrect")
 		}
 
+		defer close(this.readyCn)
+		defer close(this.tableComputedCn)
+
+		//a stored table at least as big is used directly. A smaller one is only grown
+		var previous PreComputeTable
+		file := this.loadTableFile(statusCallback)
+		if file != nil {
+			if len(file.table) >= newTableSize {
+				this.tableSize = len(file.table)
+				this.tableLookup = &LookupTable{file.table}
+				this.tableFile = file
+				return
+			}
+			previous = file.table
+			defer file.release()
+		}
+
 		this.tableSize = newTableSize
 
 		go func() {
-			createLookupTable(1, newTableSize, this.tableComputedCn, ctx, statusCallback)
+			createLookupTable(previous, newTableSize, this.tableComputedCn, ctx, statusCallback)
 		}()
 
 		tableLookup := <-this.tableComputedCn
 		this.tableLookup = tableLookup
-		close(this.readyCn)
-		close(this.tableComputedCn)
+
+		if tableLookup != nil {
+			this.saveTableFile(tableLookup, statusCallback)
+		}
 
 	})
 
 	<-this.readyCn
 
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
 	return this.tableLookup
 }
 
+// GrowTableSize doubles the table in the background until it reaches maxTableSize.
+// Only the missing entries are computed and every step is saved, so a restart continues from the last saved table
+func (this *BalanceDecryptorType) GrowTableSize(maxTableSize int, ctx context.Context, statusCallback func(string)) error {
+
+	if maxTableSize > 1<<24 || maxTableSize&0xff != 0 {
+		return errors.New("Table Size is incorrect")
+	}
+
+	if this.SetTableSize(0, ctx, statusCallback) == nil {
+		return errors.New("It was stopped")
+	}
+
+	for {
+
+		select {
+		case <-ctx.Done():
+			return errors.New("It was stopped")
+		default:
+		}
+
+		this.lock.RLock()
+		tableLookup := this.tableLookup
+		this.lock.RUnlock()
+
+		size := len((*tableLookup)[0])
+		if size >= maxTableSize {
+			return nil
+		}
+
+		newTableSize := size * 2
+		if newTableSize > maxTableSize {
+			newTableSize = maxTableSize
+		}
+
+		tableComputedCn := make(chan *LookupTable)
+		go func() {
+			createLookupTable((*tableLookup)[0], newTableSize, tableComputedCn, ctx, statusCallback)
+		}()
+
+		newTableLookup := <-tableComputedCn
+		if newTableLookup == nil {
+			return errors.New("It was stopped")
+		}
+
+		this.saveTableFile(newTableLookup, statusCallback)
+
+		//the saved file is mapped to release the computed table from memory
+		file := this.loadTableFile(statusCallback)
+		if file != nil && len(file.table) == newTableSize {
+			newTableLookup = &LookupTable{file.table}
+		} else if file != nil {
+			file.release()
+			file = nil
+		}
+
+		this.lock.Lock()
+		oldFile := this.tableFile
+		this.tableLookup, this.tableFile, this.tableSize = newTableLookup, file, newTableSize
+		this.lock.Unlock()
+
+		if oldFile != nil {
+			oldFile.release() //unmapped after the lookups in progress are done
+		}
+
+		statusCallback("Table grown to " + strconv.Itoa(newTableSize))
+	}
+}
+
 var BalanceDecryptor *BalanceDecryptorType
 
 func init() {
@@ -85,6 +225,9 @@ func init() {
 		0,
 		nil,
 		make(chan struct{}),
+		"",
+		nil,
+		&sync.RWMutex{},
 	}
 
 }
@@ -0,0 +1,163 @@
+package balance_decryptor
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"unsafe"
+)
+
+// the table file contains a header followed by the sorted table entries stored as little endian uint64
+// header: magic (4 bytes) | version (4 bytes) | table size (8 bytes) | sha256 of the entries (32 bytes) | padding
+const (
+	TABLE_FILE_MAGIC              = "PBDT"
+	TABLE_FILE_VERSION     uint32 = 1
+	TABLE_FILE_HEADER_SIZE        = 64
+)
+
+type tableFile struct {
+	data  []byte //the mapped file
+	table PreComputeTable
+	refs  int32 //atomic. The owner and every lookup in progress hold a reference. The file is unmapped when the last one is released
+}
+
+var nativeLittleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+func readTableFile(path string) (*tableFile, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if stat.Size() < TABLE_FILE_HEADER_SIZE {
+		return nil, errors.New("Table file is too small")
+	}
+
+	header := make([]byte, TABLE_FILE_HEADER_SIZE)
+	if _, err = f.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+
+	if string(header[:4]) != TABLE_FILE_MAGIC {
+		return nil, errors.New("Table file magic is invalid")
+	}
+	if binary.LittleEndian.Uint32(header[4:8]) != TABLE_FILE_VERSION {
+		return nil, errors.New("Table file version is not supported")
+	}
+
+	tableSize := binary.LittleEndian.Uint64(header[8:16])
+	if tableSize == 0 || tableSize > 1<<24 || tableSize&0xff != 0 {
+		return nil, errors.New("Table file size is invalid")
+	}
+	if uint64(stat.Size()) != TABLE_FILE_HEADER_SIZE+tableSize*8 {
+		return nil, errors.New("Table file length is not matching")
+	}
+
+	data, err := mmapFile(f, int(stat.Size()))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := data[TABLE_FILE_HEADER_SIZE:]
+	checksum := sha256.Sum256(entries)
+	if !bytes.Equal(checksum[:], header[16:48]) {
+		munmapFile(data)
+		return nil, errors.New("Table file checksum is invalid")
+	}
+
+	file := &tableFile{data, nil, 1}
+
+	if nativeLittleEndian {
+		file.table = unsafe.Slice((*uint64)(unsafe.Pointer(&entries[0])), tableSize)
+	} else {
+		file.table = make(PreComputeTable, tableSize)
+		for i := range file.table {
+			file.table[i] = binary.LittleEndian.Uint64(entries[i*8:])
+		}
+		munmapFile(data)
+		file.data = nil
+	}
+
+	return file, nil
+}
+
+// the table is written in a temporary file which is renamed, so a table file is never partially written
+func writeTableFile(path string, table PreComputeTable) (err error) {
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	entries := make([]byte, len(table)*8)
+	for i, value := range table {
+		binary.LittleEndian.PutUint64(entries[i*8:], value)
+	}
+	checksum := sha256.Sum256(entries)
+
+	header := make([]byte, TABLE_FILE_HEADER_SIZE)
+	copy(header, TABLE_FILE_MAGIC)
+	binary.LittleEndian.PutUint32(header[4:8], TABLE_FILE_VERSION)
+	binary.LittleEndian.PutUint64(header[8:16], uint64(len(table)))
+	copy(header[16:48], checksum[:])
+
+	w := bufio.NewWriter(f)
+	if _, err = w.Write(header); err != nil {
+		return
+	}
+	if _, err = w.Write(entries); err != nil {
+		return
+	}
+	if err = w.Flush(); err != nil {
+		return
+	}
+	if err = f.Sync(); err != nil {
+		return
+	}
+	if err = f.Close(); err != nil {
+		return
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func (file *tableFile) acquire() {
+	atomic.AddInt32(&file.refs, 1)
+}
+
+func (file *tableFile) release() error {
+	if atomic.AddInt32(&file.refs, -1) == 0 {
+		return file.close()
+	}
+	return nil
+}
+
+func (file *tableFile) close() error {
+	if file.data == nil {
+		return nil
+	}
+	data := file.data
+	file.data = nil
+	file.table = nil
+	return munmapFile(data)
+}
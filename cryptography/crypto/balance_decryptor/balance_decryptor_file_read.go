@@ -0,0 +1,22 @@
+//go:build wasm || windows
+// +build wasm windows
+
+package balance_decryptor
+
+import (
+	"io"
+	"os"
+)
+
+// memory mapping is not available, the file is read in memory
+func mmapFile(f *os.File, size int) ([]byte, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func munmapFile(data []byte) error {
+	return nil
+}
@@ -0,0 +1,61 @@
+package balance_decryptor
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"os"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"path/filepath"
+	"testing"
+)
+
+func createTestLookupTable(previous PreComputeTable, tableSize int) *LookupTable {
+	cn := make(chan *LookupTable, 1)
+	createLookupTable(previous, tableSize, cn, context.Background(), func(string) {})
+	return <-cn
+}
+
+func TestTableFile(t *testing.T) {
+
+	full := createTestLookupTable(nil, 1<<12)
+	half := createTestLookupTable(nil, 1<<11)
+	grown := createTestLookupTable((*half)[0], 1<<12)
+
+	assert.Equal(t, (*full)[0], (*grown)[0])
+
+	path := filepath.Join(t.TempDir(), "balance_decryptor.table")
+	assert.NoError(t, writeTableFile(path, (*grown)[0]))
+
+	file, err := readTableFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, (*full)[0], file.table)
+
+	balance := uint64(123456789)
+	p := new(bn256.G1).ScalarMult(crypto.G, new(big.Int).SetUint64(balance))
+
+	tableLookup := LookupTable{file.table}
+	value, err := tableLookup.Lookup(p, context.Background(), func(string) {})
+	assert.NoError(t, err)
+	assert.Equal(t, balance, value)
+
+	//the owner releases the file while a lookup is still using it
+	file.acquire()
+	assert.NoError(t, file.release())
+	assert.NotNil(t, file.table)
+	value, err = tableLookup.Lookup(p, context.Background(), func(string) {})
+	assert.NoError(t, err)
+	assert.Equal(t, balance, value)
+	assert.NoError(t, file.release())
+	assert.Nil(t, file.table)
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	data[len(data)-1] ^= 1
+	assert.NoError(t, os.WriteFile(path, data, 0644))
+
+	_, err = readTableFile(path)
+	assert.Error(t, err)
+
+}
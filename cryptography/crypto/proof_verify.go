@@ -57,8 +57,14 @@ type InnerProductSupport struct {
 var gparams = NewGeneratorParams(128)
 
 // verify proof
-// first generate supporting structures
 func (proof *Proof) Verify(assetId []byte, assetIndex int, chainHash []byte, s *Statement, txid []byte, extra_value uint64) bool {
+	batch := NewProofsBatchVerifier()
+	return batch.Add(proof, assetId, assetIndex, chainHash, s, txid, extra_value) && batch.Verify()
+}
+
+// first generate supporting structures
+// the final checks are postponed in the batch
+func (proof *Proof) verify(batch *ProofsBatchVerifier, assetId []byte, assetIndex int, chainHash []byte, s *Statement, txid []byte, extra_value uint64) bool {
 
 	var anonsupport AnonSupport
 	var protsupport ProtocolSupport
@@ -102,44 +108,7 @@ func (proof *Proof) Verify(assetId []byte, assetIndex int, chainHash []byte, s *
 		return false
 	}
 
-	anonsupport.temp = new(bn256.G1)
 	var zeroes [64]byte
-	anonsupport.temp.Unmarshal(zeroes[:])
-
-	for k := 0; k < 2*m; k++ {
-		anonsupport.temp = new(bn256.G1).Add(anonsupport.temp, new(bn256.G1).ScalarMult(gparams.Gs.vector[k], anonsupport.f[k][1]))
-
-		t := new(big.Int).Mod(new(big.Int).Mul(anonsupport.f[k][1], anonsupport.f[k][0]), bn256.Order)
-
-		anonsupport.temp = new(bn256.G1).Add(anonsupport.temp, new(bn256.G1).ScalarMult(gparams.Hs.vector[k], t))
-	}
-
-	t0 := new(bn256.G1).ScalarMult(gparams.Hs.vector[0+2*m], new(big.Int).Mod(new(big.Int).Mul(anonsupport.f[0][1], anonsupport.f[m][1]), bn256.Order))
-	t1 := new(bn256.G1).ScalarMult(gparams.Hs.vector[1+2*m], new(big.Int).Mod(new(big.Int).Mul(anonsupport.f[0][0], anonsupport.f[m][0]), bn256.Order))
-
-	anonsupport.temp = new(bn256.G1).Add(anonsupport.temp, t0)
-	anonsupport.temp = new(bn256.G1).Add(anonsupport.temp, t1)
-
-	// check whether we successfuly recover B^w * A
-	stored := new(bn256.G1).Add(new(bn256.G1).ScalarMult(proof.B, anonsupport.w), proof.A)
-	computed := new(bn256.G1).Add(anonsupport.temp, new(bn256.G1).ScalarMult(gparams.H, proof.z_A))
-
-	//	for i := range proof.f.vector {
-	//		klog.V(2).Infof("proof.f %d %s\n", i, proof.f.vector[i].Text(16))
-	//	}
-	//	klog.V(2).Infof("anonsupport.w %s\n", anonsupport.w.Text(16))
-	//	klog.V(2).Infof("proof.z_A %s\n", proof.z_A.Text(16))
-	//	klog.V(2).Infof("proof.B %s\n", proof.B.String())
-	//	klog.V(2).Infof("proof.A %s\n", proof.A.String())
-	//	klog.V(2).Infof("gparams.H %s\n", gparams.H.String())
-
-	//	klog.V(2).Infof("stored %s\n", stored.String())
-	//	klog.V(2).Infof("computed %s\n", computed.String())
-
-	if stored.String() != computed.String() { // if failed bail out
-		//		klog.Warning("Recover key failed B^w * A")
-		return false
-	}
 
 	anonsupport.r = assemblepolynomials(anonsupport.f)
 
@@ -387,41 +356,16 @@ func (proof *Proof) Verify(assetId []byte, assetIndex int, chainHash []byte, s *
 
 	o := reducedhash(ConvertBigIntToByte(proof.c))
 
-	u_x := new(bn256.G1).ScalarMult(gparams.H, o)
-
-	var hPrimes []*bn256.G1
-	hPrimeSum := new(bn256.G1)
-
-	hPrimeSum.Unmarshal(zeroes[:])
-	for i := 0; i < 128; i++ {
-		hPrimes = append(hPrimes, new(bn256.G1).ScalarMult(gparams.Hs.vector[i], new(big.Int).ModInverse(protsupport.ys[i], bn256.Order)))
-
-		//		klog.V(2).Infof("hPrimes %d %s\n", i, hPrimes[i].String())
-
-		tmp := new(big.Int).Mod(new(big.Int).Mul(protsupport.ys[i], protsupport.z), bn256.Order)
-		tmp = new(big.Int).Mod(new(big.Int).Add(tmp, protsupport.twoTimesZSquared[i]), bn256.Order)
-
-		hPrimeSum = new(bn256.G1).Add(hPrimeSum, new(bn256.G1).ScalarMult(hPrimes[i], tmp))
-
+	// check whether we successfuly recover B^w * A
+	if !batch.addRecoverCheck(proof, anonsupport.f, anonsupport.w, m) {
+		return false
 	}
 
-	P := new(bn256.G1).Add(proof.BA, new(bn256.G1).ScalarMult(proof.BS, x))
-	P = new(bn256.G1).Add(P, new(bn256.G1).ScalarMult(gparams.GSUM, new(big.Int).Mod(new(big.Int).Neg(protsupport.z), bn256.Order)))
-	P = new(bn256.G1).Add(P, hPrimeSum)
-
-	P = new(bn256.G1).Add(P, new(bn256.G1).ScalarMult(gparams.H, new(big.Int).Mod(new(big.Int).Neg(proof.mu), bn256.Order)))
-	P = new(bn256.G1).Add(P, new(bn256.G1).ScalarMult(u_x, new(big.Int).Mod(new(big.Int).Set(proof.that), bn256.Order)))
-
-	//	klog.V(2).Infof("P  %s\n", P.String())
-
-	if !proof.ip.Verify(hPrimes, u_x, P, o, gparams) {
+	if !batch.addInnerProductCheck(proof, protsupport.ys, protsupport.z, protsupport.twoTimesZSquared[:], x, o) {
 		//		klog.Warning("inner proof failed")
 		return false
 	}
 
-	// klog.V(2).Infof("proof %s\n", proof.String())
-	//	klog.V(2).Infof("Proof successful verified\n")
-
 	return true
 
 }
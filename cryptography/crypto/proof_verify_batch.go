@@ -0,0 +1,203 @@
+package crypto
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"pandora-pay/cryptography/bn256"
+)
+
+// ProofsBatchVerifier verifies many proofs together.
+// The Sigma challenge of every proof is still recomputed and hashed one by one, but the final equality checks
+// (the recovery of B^w * A and the inner product) are multiplied by random weights and summed into a single
+// multi-exponentiation. The generators shared by all proofs are multiplied only once per batch.
+// If Verify fails, the proofs must be verified one by one to find the invalid one.
+type ProofsBatchVerifier struct {
+	gs      []*big.Int //scalars of gparams.Gs
+	hs      []*big.Int //scalars of gparams.Hs
+	h       *big.Int
+	gsum    *big.Int
+	points  []*bn256.G1 //points specific to each proof
+	scalars []*big.Int
+	count   int
+}
+
+func mulMod(values ...*big.Int) *big.Int {
+	out := new(big.Int).SetUint64(1)
+	for _, value := range values {
+		out = out.Mod(out.Mul(out, value), bn256.Order)
+	}
+	return out
+}
+
+func (batch *ProofsBatchVerifier) addScalar(to *big.Int, values ...*big.Int) {
+	to.Mod(to.Add(to, mulMod(values...)), bn256.Order)
+}
+
+func (batch *ProofsBatchVerifier) addPoint(point *bn256.G1, values ...*big.Int) {
+	batch.points = append(batch.points, point)
+	batch.scalars = append(batch.scalars, mulMod(values...))
+}
+
+// B*w + A - (sum Gs[k]*f[k][1] + Hs[k]*f[k][1]*f[k][0]) - Hs[2m]*f[0][1]*f[m][1] - Hs[2m+1]*f[0][0]*f[m][0] - H*z_A == 0
+func (batch *ProofsBatchVerifier) addRecoverCheck(proof *Proof, f [][2]*big.Int, w *big.Int, m int) bool {
+
+	if 2*m+2 > len(batch.hs) {
+		return false
+	}
+
+	weight := RandomScalar()
+	negWeight := new(big.Int).Sub(bn256.Order, weight)
+
+	batch.addPoint(proof.B, weight, w)
+	batch.addPoint(proof.A, weight)
+
+	for k := 0; k < 2*m; k++ {
+		batch.addScalar(batch.gs[k], negWeight, f[k][1])
+		batch.addScalar(batch.hs[k], negWeight, f[k][1], f[k][0])
+	}
+	batch.addScalar(batch.hs[2*m], negWeight, f[0][1], f[m][1])
+	batch.addScalar(batch.hs[2*m+1], negWeight, f[0][0], f[m][0])
+	batch.addScalar(batch.h, negWeight, proof.z_A)
+
+	return true
+}
+
+// P + sum(L_i*o_i^2 + R_i*o_i^-2) - sum(Gs[i]*a*exp_i + hPrimes[i]*b*exp_(n-1-i)) - u*a*b == 0
+// where P = BA + BS*x - GSUM*z + sum(hPrimes[i]*(ys_i*z + 2^i*zs)) - H*mu + u*that, hPrimes[i] = Hs[i]/ys_i and u = H*salt
+func (batch *ProofsBatchVerifier) addInnerProductCheck(proof *Proof, ys []*big.Int, z *big.Int, twoTimesZSquared []*big.Int, x, salt *big.Int) bool {
+
+	ip := proof.ip
+
+	log_n := len(ip.ls)
+	if log_n != len(ip.rs) { // length must be same
+		return false
+	}
+	n := int(math.Pow(2, float64(log_n)))
+	if n != len(batch.gs) || n != len(ys) {
+		return false
+	}
+
+	weight := RandomScalar()
+	negWeight := new(big.Int).Sub(bn256.Order, weight)
+
+	batch.addPoint(proof.BA, weight)
+	batch.addPoint(proof.BS, weight, x)
+
+	o := salt
+	challenges := make([]*big.Int, log_n)
+	exp := new(big.Int).SetUint64(1)
+	for i := 0; i < log_n; i++ {
+
+		var input []byte
+		input = append(input, ConvertBigIntToByte(o)...)
+		input = append(input, ip.ls[i].Marshal()...)
+		input = append(input, ip.rs[i].Marshal()...)
+		o = reducedhash(input)
+		challenges[i] = o
+
+		o_inv := new(big.Int).ModInverse(o, bn256.Order)
+		if o_inv == nil {
+			return false
+		}
+
+		batch.addPoint(ip.ls[i], weight, o, o)
+		batch.addPoint(ip.rs[i], weight, o_inv, o_inv)
+
+		exp = mulMod(exp, o)
+	}
+
+	exponents := make([]*big.Int, n)
+	if exponents[0] = new(big.Int).ModInverse(exp, bn256.Order); exponents[0] == nil {
+		return false
+	}
+
+	bits := make([]bool, n)
+	for i := 0; i < n/2; i++ {
+		for j := 0; (1<<j)+i < n; j++ {
+			i1 := (1 << j) + i
+			if !bits[i1] {
+				exponents[i1] = mulMod(exponents[i], challenges[log_n-1-j], challenges[log_n-1-j])
+				bits[i1] = true
+			}
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		ysInv := new(big.Int).ModInverse(ys[i], bn256.Order)
+		if ysInv == nil {
+			return false
+		}
+
+		tmp := new(big.Int).Mod(new(big.Int).Mul(ys[i], z), bn256.Order)
+		tmp = tmp.Add(tmp, twoTimesZSquared[i])
+		tmp = tmp.Sub(tmp, mulMod(ip.b, exponents[n-1-i]))
+		tmp = tmp.Mod(tmp, bn256.Order)
+
+		batch.addScalar(batch.hs[i], weight, ysInv, tmp)
+		batch.addScalar(batch.gs[i], negWeight, ip.a, exponents[i])
+	}
+
+	batch.addScalar(batch.gsum, negWeight, z)
+
+	hScalar := new(big.Int).Sub(proof.that, mulMod(ip.a, ip.b))
+	hScalar = hScalar.Mod(hScalar.Mul(hScalar, salt), bn256.Order)
+	hScalar = hScalar.Sub(hScalar, proof.mu)
+	batch.addScalar(batch.h, weight, hScalar.Mod(hScalar, bn256.Order))
+
+	return true
+}
+
+// Add verifies the Sigma protocol of the proof and postpones its final checks until Verify is called
+func (batch *ProofsBatchVerifier) Add(proof *Proof, assetId []byte, assetIndex int, chainHash []byte, s *Statement, txid []byte, extra_value uint64) bool {
+	if !proof.verify(batch, assetId, assetIndex, chainHash, s, txid, extra_value) {
+		return false
+	}
+	batch.count += 1
+	return true
+}
+
+func (batch *ProofsBatchVerifier) Count() int {
+	return batch.count
+}
+
+// Verify returns true if the postponed checks of all the added proofs are valid
+func (batch *ProofsBatchVerifier) Verify() bool {
+
+	var zeroes [64]byte
+	total := new(bn256.G1)
+	total.Unmarshal(zeroes[:])
+
+	for i := range batch.gs {
+		total.Add(new(bn256.G1).Set(total), new(bn256.G1).ScalarMult(gparams.Gs.vector[i], batch.gs[i]))
+		total.Add(new(bn256.G1).Set(total), new(bn256.G1).ScalarMult(gparams.Hs.vector[i], batch.hs[i]))
+	}
+	total.Add(new(bn256.G1).Set(total), new(bn256.G1).ScalarMult(gparams.H, batch.h))
+	total.Add(new(bn256.G1).Set(total), new(bn256.G1).ScalarMult(gparams.GSUM, batch.gsum))
+
+	for i, point := range batch.points {
+		total.Add(new(bn256.G1).Set(total), new(bn256.G1).ScalarMult(point, batch.scalars[i]))
+	}
+
+	return bytes.Equal(total.Marshal(), zeroes[:])
+}
+
+func NewProofsBatchVerifier() *ProofsBatchVerifier {
+
+	batch := &ProofsBatchVerifier{
+		make([]*big.Int, len(gparams.Gs.vector)),
+		make([]*big.Int, len(gparams.Hs.vector)),
+		new(big.Int),
+		new(big.Int),
+		nil,
+		nil,
+		0,
+	}
+
+	for i := range batch.gs {
+		batch.gs[i] = new(big.Int)
+		batch.hs[i] = new(big.Int)
+	}
+
+	return batch
+}
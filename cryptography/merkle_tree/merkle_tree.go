@@ -1,13 +1,125 @@
 package merkle_tree
 
 import (
+	"bytes"
+	"errors"
 	"math"
 	"pandora-pay/cryptography"
 )
 
 /**
 Fast Merkle Tree Construction
-It can't generate efficient proofs
+The leaves and the nodes are hashed with different prefixes, so a node can't be presented as a leaf.
+A node without a right sibling is carried up to the next level unchanged, so two different lists of hashes can't have the same root
+*/
+
+const (
+	leafPrefix = byte(0)
+	nodePrefix = byte(1)
+)
+
+func hashMerkleLeaf(hash []byte) []byte {
+	return cryptography.SHA3(append([]byte{leafPrefix}, hash...))
+}
+
+func hashMerkleNode(left []byte, right []byte) []byte {
+	// Concatenate the prefix, the left and right nodes.
+	hash := make([]byte, 0, 1+len(left)+len(right))
+	hash = append(hash, nodePrefix)
+	hash = append(hash, left...)
+	hash = append(hash, right...)
+	return cryptography.SHA3(hash)
+}
+
+// returns the levels of the tree starting from the leaves. The last level contains only the root
+func buildMerkleTree(hashes [][]byte) [][][]byte {
+
+	level := make([][]byte, len(hashes))
+	for i := range hashes {
+		level[i] = hashMerkleLeaf(hashes[i])
+	}
+
+	levels := [][][]byte{level}
+	for len(level) > 1 {
+
+		next := make([][]byte, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 < len(level) {
+				next[i/2] = hashMerkleNode(level[i], level[i+1])
+			} else {
+				next[i/2] = level[i]
+			}
+		}
+
+		levels = append(levels, next)
+		level = next
+	}
+
+	return levels
+}
+
+func MerkleRoot(hashes [][]byte) []byte {
+	if len(hashes) == 0 {
+		return nil
+	}
+	levels := buildMerkleTree(hashes)
+	return levels[len(levels)-1][0]
+}
+
+// MerkleProof returns the siblings required to compute the root from the hash found at index, starting from the leaves.
+// The levels where the node doesn't have a sibling are skipped
+func MerkleProof(hashes [][]byte, index int) ([][]byte, error) {
+
+	if index < 0 || index >= len(hashes) {
+		return nil, errors.New("Merkle index is out of range")
+	}
+
+	levels := buildMerkleTree(hashes)
+
+	proof := [][]byte{}
+	for _, level := range levels[:len(levels)-1] {
+		if sibling := index ^ 1; sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		index /= 2
+	}
+
+	return proof, nil
+}
+
+// VerifyMerkleProof checks that the hash found at index is included in the tree of count hashes with the given root
+func VerifyMerkleProof(root, hash []byte, index, count uint64, proof [][]byte) bool {
+
+	if index >= count {
+		return false
+	}
+
+	hash = hashMerkleLeaf(hash)
+
+	for size := count; size > 1; size = (size + 1) / 2 {
+
+		if sibling := index ^ 1; sibling < size {
+
+			if len(proof) == 0 || len(proof[0]) != cryptography.HashSize {
+				return false
+			}
+
+			if index%2 == 0 {
+				hash = hashMerkleNode(hash, proof[0])
+			} else {
+				hash = hashMerkleNode(proof[0], hash)
+			}
+			proof = proof[1:]
+		}
+
+		index /= 2
+	}
+
+	return len(proof) == 0 && bytes.Equal(hash, root)
+}
+
+/**
+Merkle Tree used before config.FORK_HEIGHT_MERKLE_TREE. Missing right nodes are replaced by the left node
 */
 
 func roundNextPowerOfTwo(number int) int {
@@ -20,13 +132,15 @@ func roundNextPowerOfTwo(number int) int {
 	return 1 << exp
 }
 
-func hashMerkleNode(left []byte, right []byte) []byte {
+func hashMerkleNodeLegacy(left []byte, right []byte) []byte {
 	// Concatenate the left and right nodes.
-	hash := append(left, right...)
+	hash := make([]byte, 0, len(left)+len(right))
+	hash = append(hash, left...)
+	hash = append(hash, right...)
 	return cryptography.SHA3(hash)
 }
 
-func buildMerkleTree(hashes [][]byte) [][]byte {
+func buildMerkleTreeLegacy(hashes [][]byte) [][]byte {
 
 	if len(hashes) == 0 {
 		return [][]byte{}
@@ -49,11 +163,11 @@ func buildMerkleTree(hashes [][]byte) [][]byte {
 			nodes[offset] = nil
 
 		case nodes[i+1] == nil:
-			newHash := hashMerkleNode(nodes[i], nodes[i])
+			newHash := hashMerkleNodeLegacy(nodes[i], nodes[i])
 			nodes[offset] = newHash
 
 		default:
-			newHash := hashMerkleNode(nodes[i], nodes[i+1])
+			newHash := hashMerkleNodeLegacy(nodes[i], nodes[i+1])
 			nodes[offset] = newHash
 		}
 		offset++
@@ -61,7 +175,7 @@ func buildMerkleTree(hashes [][]byte) [][]byte {
 	return nodes
 }
 
-func MerkleRoot(hashes [][]byte) []byte {
-	merkles := buildMerkleTree(hashes)
+func MerkleRootLegacy(hashes [][]byte) []byte {
+	merkles := buildMerkleTreeLegacy(hashes)
 	return merkles[len(merkles)-1] //return last element
 }
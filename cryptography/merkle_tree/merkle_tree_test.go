@@ -14,7 +14,42 @@ func TestMerkleRoot(t *testing.T) {
 
 	root := MerkleRoot(hashes)
 
-	hash := hashMerkleNode(hashes[0], hashes[1])
+	hash := hashMerkleNode(hashMerkleLeaf(hashes[0]), hashMerkleLeaf(hashes[1]))
 	assert.Equal(t, root, hash, "Merkle Tree Hashes are invalid")
 
+	//the last odd hash is carried up instead of being duplicated
+	assert.NotEqual(t, MerkleRoot(append(hashes, hashes[1])), MerkleRoot(append(hashes, hashes[1], hashes[1])))
+
+	//a node can't be used as a leaf
+	assert.NotEqual(t, root, MerkleRoot([][]byte{hash}))
+
+}
+
+func TestMerkleProof(t *testing.T) {
+
+	for count := 1; count <= 9; count++ {
+
+		hashes := make([][]byte, count)
+		for i := range hashes {
+			hashes[i] = cryptography.RandomHash()
+		}
+		root := MerkleRoot(hashes)
+
+		for i := range hashes {
+			proof, err := MerkleProof(hashes, i)
+			assert.Nil(t, err)
+			assert.True(t, VerifyMerkleProof(root, hashes[i], uint64(i), uint64(count), proof), "Merkle Proof is invalid")
+			assert.False(t, VerifyMerkleProof(root, cryptography.RandomHash(), uint64(i), uint64(count), proof), "Merkle Proof should be invalid")
+			if i == count-1 && count%2 == 1 && count > 1 { //the last odd hash is carried up
+				assert.False(t, VerifyMerkleProof(root, hashes[i], uint64(i), uint64(count+1), proof), "Merkle Proof count should be invalid")
+			}
+			if i^1 < count {
+				assert.False(t, VerifyMerkleProof(root, hashes[i], uint64(i^1), uint64(count), proof), "Merkle Proof index should be invalid")
+			}
+		}
+
+		_, err := MerkleProof(hashes, count)
+		assert.NotNil(t, err)
+	}
+
 }
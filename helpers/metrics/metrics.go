@@ -0,0 +1,286 @@
+package metrics
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"pandora-pay/helpers/generics"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+/**
+Metrics exported in the Prometheus text exposition format
+A metric registered again with the same name replaces the previous one
+*/
+
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type metric interface {
+	write(w io.Writer)
+}
+
+type registryType struct {
+	metrics map[string]metric
+	lock    *sync.RWMutex
+}
+
+var registry = &registryType{
+	make(map[string]metric),
+	&sync.RWMutex{},
+}
+
+func register(name string, m metric) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	registry.metrics[name] = m
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	if math.IsInf(value, -1) {
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func formatLabels(labels ...string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	list := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labels[i+1])
+		list = append(list, labels[i]+`="`+value+`"`)
+	}
+	return "{" + strings.Join(list, ",") + "}"
+}
+
+func writeHeader(w io.Writer, name, help, metricType string) {
+	io.WriteString(w, "# HELP "+name+" "+help+"\n")
+	io.WriteString(w, "# TYPE "+name+" "+metricType+"\n")
+}
+
+func writeValue(w io.Writer, name, labels string, value float64) {
+	io.WriteString(w, name+labels+" "+formatFloat(value)+"\n")
+}
+
+type Counter struct {
+	name  string
+	help  string
+	value uint64 //use atomic
+}
+
+func (c *Counter) Add(delta uint64) {
+	atomic.AddUint64(&c.value, delta)
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+func (c *Counter) write(w io.Writer) {
+	writeHeader(w, c.name, c.help, "counter")
+	writeValue(w, c.name, "", float64(atomic.LoadUint64(&c.value)))
+}
+
+type Gauge struct {
+	name string
+	help string
+	bits uint64 //use atomic
+}
+
+func (g *Gauge) Set(value float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(value))
+}
+
+func (g *Gauge) Add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&g.bits)
+		if atomic.CompareAndSwapUint64(&g.bits, old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+func (g *Gauge) write(w io.Writer) {
+	writeHeader(w, g.name, g.help, "gauge")
+	writeValue(w, g.name, "", math.Float64frombits(atomic.LoadUint64(&g.bits)))
+}
+
+type gaugeFunc struct {
+	name string
+	help string
+	fn   func() float64
+}
+
+func (g *gaugeFunc) write(w io.Writer) {
+	writeHeader(w, g.name, g.help, "gauge")
+	writeValue(w, g.name, "", g.fn())
+}
+
+type Histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+	lock    *sync.Mutex
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		buckets,
+		make([]uint64, len(buckets)),
+		0,
+		0,
+		&sync.Mutex{},
+	}
+}
+
+func (h *Histogram) Observe(value float64) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for i, bucket := range h.buckets {
+		if value <= bucket {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+func (h *Histogram) writeValues(w io.Writer, name string, labels ...string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for i, bucket := range h.buckets {
+		writeValue(w, name+"_bucket", formatLabels(append(labels, "le", formatFloat(bucket))...), float64(h.counts[i]))
+	}
+	writeValue(w, name+"_bucket", formatLabels(append(labels, "le", "+Inf")...), float64(h.count))
+	writeValue(w, name+"_sum", formatLabels(labels...), h.sum)
+	writeValue(w, name+"_count", formatLabels(labels...), float64(h.count))
+}
+
+type histogramSingle struct {
+	name string
+	help string
+	*Histogram
+}
+
+func (h *histogramSingle) write(w io.Writer) {
+	writeHeader(w, h.name, h.help, "histogram")
+	h.writeValues(w, h.name)
+}
+
+//HistogramVec is a set of histograms partitioned by the value of a label
+type HistogramVec struct {
+	name       string
+	help       string
+	label      string
+	buckets    []float64
+	histograms *generics.Map[string, *Histogram]
+}
+
+func (h *HistogramVec) WithLabelValue(value string) *Histogram {
+	if histogram, found := h.histograms.Load(value); found {
+		return histogram
+	}
+	histogram, _ := h.histograms.LoadOrStore(value, newHistogram(h.buckets))
+	return histogram
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	writeHeader(w, h.name, h.help, "histogram")
+
+	values := make([]string, 0)
+	h.histograms.Range(func(key string, histogram *Histogram) bool {
+		values = append(values, key)
+		return true
+	})
+	sort.Strings(values)
+
+	for _, value := range values {
+		histogram, _ := h.histograms.Load(value)
+		histogram.writeValues(w, h.name, h.label, value)
+	}
+}
+
+//histogramFunc computes the histogram of the values returned by fn every time the metrics are read
+type histogramFunc struct {
+	name    string
+	help    string
+	buckets []float64
+	fn      func() []float64
+}
+
+func (h *histogramFunc) write(w io.Writer) {
+	histogram := newHistogram(h.buckets)
+	for _, value := range h.fn() {
+		histogram.Observe(value)
+	}
+	writeHeader(w, h.name, h.help, "histogram")
+	histogram.writeValues(w, h.name)
+}
+
+func NewCounter(name, help string) *Counter {
+	counter := &Counter{name, help, 0}
+	register(name, counter)
+	return counter
+}
+
+func NewGauge(name, help string) *Gauge {
+	gauge := &Gauge{name, help, 0}
+	register(name, gauge)
+	return gauge
+}
+
+func NewGaugeFunc(name, help string, fn func() float64) {
+	register(name, &gaugeFunc{name, help, fn})
+}
+
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	histogram := newHistogram(buckets)
+	register(name, &histogramSingle{name, help, histogram})
+	return histogram
+}
+
+func NewHistogramVec(name, help, label string, buckets []float64) *HistogramVec {
+	histogramVec := &HistogramVec{name, help, label, buckets, &generics.Map[string, *Histogram]{}}
+	register(name, histogramVec)
+	return histogramVec
+}
+
+func NewHistogramFunc(name, help string, buckets []float64, fn func() []float64) {
+	register(name, &histogramFunc{name, help, buckets, fn})
+}
+
+// WriteMetrics writes all the registered metrics sorted by name
+func WriteMetrics(w io.Writer) {
+
+	registry.lock.RLock()
+	names := make([]string, 0, len(registry.metrics))
+	for name := range registry.metrics {
+		names = append(names, name)
+	}
+	list := make([]metric, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		list[i] = registry.metrics[name]
+	}
+	registry.lock.RUnlock()
+
+	for _, m := range list {
+		m.write(w)
+	}
+}
+
+func Handler(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	WriteMetrics(w)
+}
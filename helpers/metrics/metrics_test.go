@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWriteMetrics(t *testing.T) {
+
+	counter := NewCounter("test_counter_total", "Test counter")
+	counter.Add(2)
+	counter.Inc()
+
+	gauge := NewGauge("test_gauge", "Test gauge")
+	gauge.Set(5)
+	gauge.Add(-1.5)
+
+	histogram := NewHistogramVec("test_duration_seconds", "Test histogram", "method", []float64{0.1, 1})
+	histogram.WithLabelValue("block").Observe(0.05)
+	histogram.WithLabelValue("block").Observe(0.5)
+	histogram.WithLabelValue("block").Observe(2)
+
+	NewHistogramFunc("test_values", "Test histogram func", []float64{10}, func() []float64 {
+		return []float64{1, 20}
+	})
+
+	b := &bytes.Buffer{}
+	WriteMetrics(b)
+	out := b.String()
+
+	assert.Contains(t, out, "# TYPE test_counter_total counter\ntest_counter_total 3\n")
+	assert.Contains(t, out, "# TYPE test_gauge gauge\ntest_gauge 3.5\n")
+	assert.Contains(t, out, `test_duration_seconds_bucket{method="block",le="0.1"} 1`+"\n")
+	assert.Contains(t, out, `test_duration_seconds_bucket{method="block",le="1"} 2`+"\n")
+	assert.Contains(t, out, `test_duration_seconds_bucket{method="block",le="+Inf"} 3`+"\n")
+	assert.Contains(t, out, `test_duration_seconds_sum{method="block"} 2.55`+"\n")
+	assert.Contains(t, out, `test_values_bucket{le="10"} 1`+"\n")
+	assert.Contains(t, out, "test_values_count 2\n")
+}
@@ -12,10 +12,12 @@ import (
 	"pandora-pay/gui"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/txs_validator"
 	"runtime"
+	"sync"
 	"time"
 )
 
@@ -36,6 +38,7 @@ type Mempool struct {
 	removeTransactionsCn      chan *MempoolWorkerRemoveTxs
 	insertTransactionsCn      chan *MempoolWorkerInsertTxs
 	Txs                       *MempoolTxs
+	feeEstimateCache          *feeEstimateCache
 	OnBroadcastNewTransaction func([]*transaction.Transaction, bool, bool, advanced_connection_types.UUID, context.Context) []error
 }
 
@@ -45,7 +48,14 @@ func (mempool *Mempool) ContinueProcessing(continueProcessingType ContinueProces
 
 func (mempool *Mempool) RemoveInsertedTxsFromBlockchain(txs []string) bool {
 	answerCn := make(chan bool)
-	mempool.removeTransactionsCn <- &MempoolWorkerRemoveTxs{txs, answerCn}
+	mempool.removeTransactionsCn <- &MempoolWorkerRemoveTxs{txs, "", answerCn}
+	return <-answerCn
+}
+
+// RemoveTx drops a pending transaction from the mempool, notifying the subscribers with the reason
+func (mempool *Mempool) RemoveTx(hash string, evictedReason string) bool {
+	answerCn := make(chan bool)
+	mempool.removeTransactionsCn <- &MempoolWorkerRemoveTxs{[]string{hash}, evictedReason, answerCn}
 	return <-answerCn
 }
 
@@ -102,6 +112,10 @@ func (mempool *Mempool) processTxsToMempool(txs []*transaction.Transaction, heig
 			continue
 		}
 
+		if errs[i] = mempool.checkAssetsNotPaused(tx); errs[i] != nil {
+			continue
+		}
+
 		checkFee := true
 
 		minerFee, err := tx.GetAllFee()
@@ -122,7 +136,7 @@ func (mempool *Mempool) processTxsToMempool(txs []*transaction.Transaction, heig
 		case transaction_type.TX_SIMPLE:
 			requiredFeePerByte = config_fees.FEE_PER_BYTE
 			txBase := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
-			if txBase.TxScript == transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT {
+			if txBase.TxScript == transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT || txBase.TxScript == transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK {
 				checkFee = false
 			}
 		case transaction_type.TX_ZETHER:
@@ -154,6 +168,11 @@ func (mempool *Mempool) processTxsToMempool(txs []*transaction.Transaction, heig
 func (mempool *Mempool) AddTxsToMempool(txs []*transaction.Transaction, height uint64, justCreated, awaitAnswer, awaitBroadcasting bool, exceptSocketUUID advanced_connection_types.UUID, ctx context.Context) []error {
 
 	finalTxs, errs := mempool.processTxsToMempool(txs, height, ctx)
+	for _, finalTx := range finalTxs {
+		if finalTx != nil {
+			finalTx.Mine = justCreated
+		}
+	}
 
 	//making sure that the transaction is not inserted twice
 	if runtime.GOARCH != "wasm" {
@@ -226,6 +245,8 @@ func (mempool *Mempool) UpdateWork(hash []byte, height uint64) {
 	}
 
 	mempool.newWorkCn <- newWork
+
+	mempool.refreshFeeEstimate()
 }
 
 func (mempool *Mempool) ContinueWork() {
@@ -246,14 +267,32 @@ func CreateMempool() (*Mempool, error) {
 		make(chan *MempoolWorkerRemoveTxs),
 		make(chan *MempoolWorkerInsertTxs),
 		createMempoolTxs(),
+		&feeEstimateCache{make(map[uint64]*feeEstimateBlock), &sync.Mutex{}, make(chan struct{}, 1)},
 		nil,
 	}
 
+	metrics.NewGaugeFunc("pandora_mempool_txs", "Number of txs in the mempool", func() float64 {
+		return float64(mempool.Txs.Count())
+	})
+	metrics.NewGaugeFunc("pandora_mempool_bytes", "Size of the txs in the mempool", func() float64 {
+		return float64(mempool.Txs.Size())
+	})
+	metrics.NewHistogramFunc("pandora_mempool_fee_per_byte", "FeePerByte distribution of the txs in the mempool", []float64{10, 20, 50, 100, 200, 500, 1000, 5000}, func() []float64 {
+		txs := mempool.Txs.GetTxsList()
+		fees := make([]float64, len(txs))
+		for i, tx := range txs {
+			fees[i] = float64(tx.FeePerByte)
+		}
+		return fees
+	})
+
 	worker := new(mempoolWorker)
 	recovery.SafeGo(func() {
 		worker.processing(mempool.newWorkCn, mempool.SuspendProcessingCn, mempool.ContinueProcessingCn, mempool.addTransactionCn, mempool.insertTransactionsCn, mempool.removeTransactionsCn, mempool.Txs)
 	})
 
+	recovery.SafeGo(mempool.processFeeEstimateRefresh)
+
 	mempool.initCLI()
 
 	return mempool, nil
@@ -2,12 +2,18 @@ package mempool
 
 import (
 	"bytes"
+	"errors"
+	"pandora-pay/blockchain/data_storage/assets"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
 	"pandora-pay/blockchain/transactions/transaction/transaction_type"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/config/config_mempool"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
 	"sort"
+	"time"
 )
 
 type ContinueProcessingType byte
@@ -60,6 +66,45 @@ func (mempool *Mempool) ExistsTxZetherVersion(publicKey []byte, version transact
 	return false
 }
 
+// rejects zether transactions moving assets that are currently paused
+func (mempool *Mempool) checkAssetsNotPaused(tx *transaction.Transaction) error {
+
+	if tx.Version != transaction_type.TX_ZETHER {
+		return nil
+	}
+
+	base := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		asts := assets.NewAssets(reader)
+		for _, payload := range base.Payloads {
+			ast, err := asts.Get(string(payload.Asset))
+			if err != nil {
+				return err
+			}
+			if ast != nil && ast.Paused {
+				return errors.New("Asset is paused")
+			}
+		}
+		return nil
+	})
+}
+
+func getExpiredTxs(txsList []*mempoolTx, chainHeight uint64) []string {
+
+	now := time.Now().Unix()
+
+	expired := []string{}
+	for _, tx := range txsList {
+		if config_mempool.MEMPOOL_TX_TTL > 0 && tx.Added+config_mempool.MEMPOOL_TX_TTL < now {
+			expired = append(expired, tx.Tx.Bloom.HashStr)
+		} else if config_mempool.MEMPOOL_TX_TTL_BLOCKS > 0 && tx.ChainHeight+config_mempool.MEMPOOL_TX_TTL_BLOCKS < chainHeight {
+			expired = append(expired, tx.Tx.Bloom.HashStr)
+		}
+	}
+	return expired
+}
+
 func (mempool *Mempool) CountInputTxs(publicKey []byte) uint64 {
 
 	txs := mempool.Txs.GetTxsList()
@@ -130,6 +175,8 @@ func (mempool *Mempool) GetNextTransactionsToInclude(chainHash []byte) (out []*t
 	return []*transaction.Transaction{}, nil
 }
 
+// the TX_SIMPLE txs of the same account keep the nonce order inside the positions of the account,
+// otherwise a tx which replaced another one by a higher fee would be processed after its chained txs
 func sortTxs(txList []*mempoolTx) {
 	sort.Slice(txList, func(i, j int) bool {
 
@@ -139,4 +186,30 @@ func sortTxs(txList []*mempoolTx) {
 
 		return txList[i].FeePerByte < txList[j].FeePerByte
 	})
+
+	positions := make(map[string][]int)
+	for i, tx := range txList {
+		if tx.Tx.Version == transaction_type.TX_SIMPLE {
+			if base := tx.Tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple); base.HasVin() {
+				positions[string(base.Vin.PublicKey)] = append(positions[string(base.Vin.PublicKey)], i)
+			}
+		}
+	}
+
+	for _, list := range positions {
+		if len(list) < 2 {
+			continue
+		}
+
+		accountTxs := make([]*mempoolTx, len(list))
+		for i, index := range list {
+			accountTxs[i] = txList[index]
+		}
+		sort.SliceStable(accountTxs, func(i, j int) bool {
+			return accountTxs[i].Tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple).Nonce < accountTxs[j].Tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple).Nonce
+		})
+		for i, index := range list {
+			txList[index] = accountTxs[i]
+		}
+	}
 }
@@ -0,0 +1,237 @@
+package mempool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/config"
+	"pandora-pay/config/config_fees"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+type FeeEstimate struct {
+	Version     transaction_type.TransactionVersion `json:"version" msgpack:"version"`
+	Blocks      uint64                              `json:"blocks" msgpack:"blocks"`           //number of blocks scanned
+	BlocksTxs   uint64                              `json:"blocksTxs" msgpack:"blocksTxs"`     //number of txs found in the scanned blocks
+	MempoolTxs  uint64                              `json:"mempoolTxs" msgpack:"mempoolTxs"`   //number of txs waiting in the mempool
+	MempoolSize uint64                              `json:"mempoolSize" msgpack:"mempoolSize"` //bytes waiting in the mempool
+	Minimum     uint64                              `json:"minimum" msgpack:"minimum"`         //minimum FeePerByte accepted by the mempool
+	Backlog     uint64                              `json:"backlog" msgpack:"backlog"`         //FeePerByte required to outbid the mempool backlog within the target blocks
+	Percentiles []uint64                            `json:"percentiles" msgpack:"percentiles"`
+	FeePerByte  []uint64                            `json:"feePerByte" msgpack:"feePerByte"` //FeePerByte for each percentile
+	Recommended uint64                              `json:"recommended" msgpack:"recommended"`
+}
+
+func getFeePercentile(sorted []uint64, percentile uint64) uint64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[percentile*uint64(len(sorted)-1)/100]
+}
+
+func getMinimumFeePerByte(version transaction_type.TransactionVersion) (uint64, error) {
+	switch version {
+	case transaction_type.TX_SIMPLE:
+		return config_fees.FEE_PER_BYTE, nil
+	case transaction_type.TX_ZETHER:
+		return config_fees.FEE_PER_BYTE_ZETHER, nil
+	default:
+		return 0, errors.New("Invalid Tx.Version")
+	}
+}
+
+// the FeePerByte of the txs included in a block, grouped by the tx version
+type feeEstimateBlock struct {
+	hash []byte
+	fees map[transaction_type.TransactionVersion][]uint64
+}
+
+type feeEstimateCache struct {
+	blocks    map[uint64]*feeEstimateBlock //by height
+	lock      *sync.Mutex
+	refreshCn chan struct{}
+}
+
+func loadBlockFeesPerByte(reader store_db_interface.StoreDBTransactionInterface, height uint64, hash []byte) (*feeEstimateBlock, error) {
+
+	data := reader.Get("blockTxs" + strconv.FormatUint(height, 10))
+	if data == nil {
+		return nil, errors.New("Block txs not found")
+	}
+
+	var txHashes [][]byte
+	if err := msgpack.Unmarshal(data, &txHashes); err != nil {
+		return nil, err
+	}
+
+	block := &feeEstimateBlock{hash, make(map[transaction_type.TransactionVersion][]uint64)}
+
+	for _, txHash := range txHashes {
+
+		if data = reader.Get("tx:" + string(txHash)); data == nil {
+			return nil, errors.New("Tx not found")
+		}
+
+		tx := &transaction.Transaction{}
+		if err := tx.Deserialize(advanced_buffers.NewBufferReader(data)); err != nil {
+			return nil, err
+		}
+
+		fee, err := tx.GetAllFee()
+		if err != nil {
+			return nil, err
+		}
+		if fee == 0 { //staking rewards and conditional payment resolutions
+			continue
+		}
+
+		block.fees[tx.Version] = append(block.fees[tx.Version], fee/uint64(len(data)))
+	}
+
+	return block, nil
+}
+
+// the blocks are read from the store only once. A cached block is reused as long as its hash is still in the chain
+func (mempool *Mempool) loadBlocksFeesPerByte(version transaction_type.TransactionVersion, blocks uint64) (count uint64, fees []uint64, err error) {
+
+	cache := mempool.feeEstimateCache
+
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		chainHeight, _ := binary.Uvarint(reader.Get("chainHeight"))
+
+		for height := chainHeight; height > 0 && count < blocks; height-- {
+
+			hash := reader.Get("blockHash_ByHeight" + strconv.FormatUint(height-1, 10))
+			if hash == nil {
+				break
+			}
+			count++
+
+			block := cache.blocks[height-1]
+			if block == nil || !bytes.Equal(block.hash, hash) {
+				if block, err = loadBlockFeesPerByte(reader, height-1, hash); err != nil {
+					return
+				}
+				cache.blocks[height-1] = block
+			}
+
+			fees = append(fees, block.fees[version]...)
+		}
+
+		for height := range cache.blocks {
+			if height >= chainHeight || height+config_fees.FEE_ESTIMATE_MAX_BLOCKS < chainHeight {
+				delete(cache.blocks, height)
+			}
+		}
+
+		return
+	})
+
+	return
+}
+
+// the cache is refreshed in background when a new block is added. The pending refreshes are merged
+func (mempool *Mempool) refreshFeeEstimate() {
+	select {
+	case mempool.feeEstimateCache.refreshCn <- struct{}{}:
+	default:
+	}
+}
+
+func (mempool *Mempool) processFeeEstimateRefresh() {
+	for {
+		<-mempool.feeEstimateCache.refreshCn
+		if _, _, err := mempool.loadBlocksFeesPerByte(transaction_type.TX_SIMPLE, config_fees.FEE_ESTIMATE_BLOCKS); err != nil {
+			gui.GUI.Error("Error refreshing the fee estimate", err)
+		}
+	}
+}
+
+// EstimateFee computes the FeePerByte percentiles of the txs included in the recent blocks and of the txs waiting in the mempool.
+// The backlog is the FeePerByte required to be among the txs that fit in the next target blocks
+func (mempool *Mempool) EstimateFee(version transaction_type.TransactionVersion, blocks, target uint64, percentiles []uint64) (*FeeEstimate, error) {
+
+	minimum, err := getMinimumFeePerByte(version)
+	if err != nil {
+		return nil, err
+	}
+
+	if blocks == 0 {
+		blocks = config_fees.FEE_ESTIMATE_BLOCKS
+	}
+	if blocks > config_fees.FEE_ESTIMATE_MAX_BLOCKS {
+		return nil, errors.New("Too many blocks to scan")
+	}
+	if target == 0 {
+		target = 1
+	}
+	if len(percentiles) == 0 {
+		percentiles = config_fees.FEE_ESTIMATE_PERCENTILES
+	}
+	for _, percentile := range percentiles {
+		if percentile > 100 {
+			return nil, errors.New("Invalid percentile")
+		}
+	}
+
+	estimate := &FeeEstimate{
+		Version:     version,
+		Minimum:     minimum,
+		Percentiles: percentiles,
+		FeePerByte:  make([]uint64, len(percentiles)),
+	}
+
+	var fees []uint64
+	if estimate.Blocks, fees, err = mempool.loadBlocksFeesPerByte(version, blocks); err != nil {
+		return nil, err
+	}
+	estimate.BlocksTxs = uint64(len(fees))
+
+	txs := mempool.Txs.GetTxsList()
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].FeePerByte > txs[j].FeePerByte
+	})
+
+	capacity := target * config.BLOCK_MAX_SIZE
+	for _, tx := range txs {
+		if estimate.MempoolSize+tx.Tx.Bloom.Size > capacity && estimate.Backlog == 0 {
+			estimate.Backlog = tx.FeePerByte + 1
+		}
+		estimate.MempoolSize += tx.Tx.Bloom.Size
+		if tx.Tx.Version == version {
+			estimate.MempoolTxs++
+			fees = append(fees, tx.FeePerByte)
+		}
+	}
+
+	sort.Slice(fees, func(i, j int) bool {
+		return fees[i] < fees[j]
+	})
+
+	for i, percentile := range percentiles {
+		estimate.FeePerByte[i] = getFeePercentile(fees, percentile)
+	}
+
+	estimate.Recommended = getFeePercentile(fees, config_fees.FEE_ESTIMATE_PERCENTILE)
+	if estimate.Recommended < estimate.Backlog {
+		estimate.Recommended = estimate.Backlog
+	}
+	if estimate.Recommended < estimate.Minimum {
+		estimate.Recommended = estimate.Minimum
+	}
+
+	return estimate, nil
+}
@@ -0,0 +1,201 @@
+package mempool
+
+import (
+	"context"
+	"encoding/base64"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config"
+	"pandora-pay/config/config_mempool"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"runtime"
+	"time"
+)
+
+type mempoolStoredTx struct {
+	tx    *transaction.Transaction
+	added int64
+	mine  bool
+}
+
+// SaveToStore persists the pending transactions, so they can be restored after a restart
+func (mempool *Mempool) SaveToStore() error {
+
+	if runtime.GOARCH == "wasm" {
+		return nil
+	}
+
+	txs := mempool.Txs.GetTxsList()
+
+	w := advanced_buffers.NewBufferWriter()
+	w.WriteUvarint(uint64(len(txs)))
+	for _, tx := range txs {
+		w.WriteVariableBytes(tx.Tx.Bloom.Serialized)
+		w.WriteUvarint(uint64(tx.Added))
+		w.WriteBool(tx.Mine)
+	}
+
+	if err := store.StoreMempool.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		writer.Put("txs", w.Bytes())
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	gui.GUI.Log("Mempool saved", len(txs))
+	return nil
+}
+
+// the pending transactions are saved when they changed, so a crash or a kill loses only the most recent ones
+func (mempool *Mempool) processSaving() {
+
+	saved := uint64(0)
+	for {
+
+		if changes := mempool.Txs.Changes(); changes != saved {
+			if err := mempool.SaveToStore(); err != nil {
+				gui.GUI.Error("Error saving mempool", err)
+			} else {
+				saved = changes
+			}
+		}
+
+		time.Sleep(config_mempool.MEMPOOL_SAVE_INTERVAL)
+	}
+}
+
+// the stored txs which can not be decoded are skipped. If the data is corrupted, only the txs read before are returned
+func parseStoredTxs(data []byte) (list []*mempoolStoredTx, err error) {
+
+	r := advanced_buffers.NewBufferReader(data)
+
+	var count uint64
+	if count, err = r.ReadUvarint(); err != nil {
+		return
+	}
+
+	list = make([]*mempoolStoredTx, 0, generics.Min(count, 1024))
+	for i := uint64(0); i < count; i++ {
+
+		var serialized []byte
+		if serialized, err = r.ReadVariableBytes(config.BLOCK_MAX_SIZE); err != nil {
+			return
+		}
+
+		stored := &mempoolStoredTx{tx: &transaction.Transaction{}}
+		errTx := stored.tx.Deserialize(advanced_buffers.NewBufferReader(serialized))
+
+		var added uint64
+		if added, err = r.ReadUvarint(); err != nil {
+			return
+		}
+		stored.added = int64(added)
+
+		if stored.mine, err = r.ReadBool(); err != nil {
+			return
+		}
+
+		if errTx != nil {
+			gui.GUI.Warning("Mempool stored tx skipped", i, errTx)
+			continue
+		}
+
+		list = append(list, stored)
+	}
+
+	return
+}
+
+// the stored txs are always deleted, so a corrupted store can not block the next start
+func (mempool *Mempool) readFromStore() (list []*mempoolStoredTx, err error) {
+
+	err = store.StoreMempool.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		data := writer.Get("txs")
+		if data == nil {
+			return
+		}
+		writer.Delete("txs")
+
+		var errParse error
+		if list, errParse = parseStoredTxs(data); errParse != nil {
+			gui.GUI.Warning("Mempool stored txs are corrupted", errParse)
+		}
+
+		return
+	})
+
+	return
+}
+
+// LoadFromStore restores the transactions saved by SaveToStore.
+// All of them are revalidated against the current tip and the invalid ones are discarded
+func (mempool *Mempool) LoadFromStore(height uint64) error {
+
+	if runtime.GOARCH == "wasm" {
+		return nil
+	}
+
+	list, err := mempool.readFromStore()
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		recovery.SafeGo(mempool.processSaving)
+		return nil
+	}
+
+	gui.GUI.Log("Mempool restoring", len(list))
+
+	recovery.SafeGo(func() {
+
+		discard := func(index int, tx *transaction.Transaction, err error) {
+			if tx.Bloom != nil {
+				gui.GUI.Warning("Mempool discarded tx", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), err)
+			} else {
+				gui.GUI.Warning("Mempool discarded tx", index, err)
+			}
+		}
+
+		count := 0
+		for index, it := range list {
+
+			if err := it.tx.BloomAll(); err != nil {
+				discard(index, it.tx, err)
+				continue
+			}
+
+			finalTxs, errs := mempool.processTxsToMempool([]*transaction.Transaction{it.tx}, height, context.Background())
+			if errs[0] != nil {
+				discard(index, it.tx, errs[0])
+				continue
+			}
+			if finalTxs[0] == nil { //already in mempool
+				continue
+			}
+
+			finalTxs[0].Added = it.added
+			finalTxs[0].Mine = it.mine
+
+			//the worker revalidates it against the current tip
+			answerCn := make(chan error)
+			mempool.addTransactionCn <- &MempoolWorkerAddTx{finalTxs[0], answerCn}
+			if err := <-answerCn; err != nil {
+				discard(index, it.tx, err)
+				continue
+			}
+
+			count++
+		}
+
+		gui.GUI.Log("Mempool restored", count)
+
+		mempool.processSaving()
+	})
+
+	return nil
+}
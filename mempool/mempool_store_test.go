@@ -0,0 +1,159 @@
+package mempool
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config/config_mempool"
+	"pandora-pay/cryptography"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"pandora-pay/txs_builder/wizard"
+	"pandora-pay/txs_validator"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testChainHeight = uint64(10)
+
+var initTestStoresOnce sync.Once
+
+// the chain state and the mempool store are kept in memory and shared by all the tests, because the mempool goroutines keep running.
+// The accounts have enough unclaimed funds to pay any fee
+func createTestStores(t *testing.T, count int) []*addresses.PrivateKey {
+
+	initTestStoresOnce.Do(func() {
+
+		var err error
+		gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+		assert.Nil(t, err)
+		assert.Nil(t, txs_validator.NewTxsValidator())
+
+		chainDB, err := store_db_memory.CreateStoreDBMemory("/blockchain")
+		assert.Nil(t, err)
+		mempoolDB, err := store_db_memory.CreateStoreDBMemory("/mempool")
+		assert.Nil(t, err)
+
+		store.StoreBlockchain = &store.Store{Name: "blockchain", Opened: true, DB: chainDB}
+		store.StoreMempool = &store.Store{Name: "mempool", Opened: true, DB: mempoolDB}
+
+		config_mempool.MEMPOOL_SAVE_INTERVAL = 10 * time.Millisecond
+	})
+
+	keys := make([]*addresses.PrivateKey, count)
+	for i := range keys {
+		keys[i] = addresses.GenerateNewPrivateKey()
+		setTestPlainAccountNonce(t, keys[i], 0)
+	}
+
+	return keys
+}
+
+// the mempool worker must be suspended while the chain state is changed
+func setTestPlainAccountNonce(t *testing.T, key *addresses.PrivateKey, nonce uint64) {
+	assert.Nil(t, store.StoreBlockchain.DB.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		dataStorage := data_storage.NewDataStorage(dbTx)
+
+		plainAcc, err := dataStorage.GetOrCreatePlainAccount(key.GeneratePublicKey(), false)
+		if err != nil {
+			return
+		}
+		plainAcc.Unclaimed = 1000000000000000
+		plainAcc.Nonce = nonce
+
+		if err = dataStorage.PlainAccs.Update(string(key.GeneratePublicKey()), plainAcc); err != nil {
+			return
+		}
+		return dataStorage.CommitChanges()
+	}))
+}
+
+func createTestMempool(t *testing.T) *Mempool {
+
+	mempool, err := CreateMempool()
+	assert.Nil(t, err)
+
+	mempool.UpdateWork(cryptography.RandomHash(), testChainHeight)
+
+	//the suspended worker doesn't hold the chain store anymore
+	t.Cleanup(func() {
+		mempool.SuspendProcessingCn <- struct{}{}
+	})
+
+	return mempool
+}
+
+func createTestTx(t *testing.T, key *addresses.PrivateKey, nonce, feePerByte uint64) *transaction.Transaction {
+	tx, err := wizard.CreateSimpleTx(&wizard.WizardTxSimpleTransfer{
+		Extra: &wizard.WizardTxSimpleExtraUpdateAssetFeeLiquidity{},
+		Data:  &wizard.WizardTransactionData{},
+		Fee:   &wizard.WizardTransactionFee{PerByte: feePerByte},
+		Nonce: nonce,
+		Key:   key.Key,
+	}, true, func(string) {})
+	assert.Nil(t, err)
+	return tx
+}
+
+func addTestTx(mempool *Mempool, tx *transaction.Transaction) error {
+	return mempool.AddTxToMempool(tx, testChainHeight, false, true, false, advanced_connection_types.UUID_SKIP_ALL, context.Background())
+}
+
+func readStoredTxs(t *testing.T) (list []*mempoolStoredTx) {
+	assert.Nil(t, store.StoreMempool.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		if data := reader.Get("txs"); data != nil {
+			list, err = parseStoredTxs(data)
+		}
+		return
+	}))
+	return
+}
+
+func TestMempoolSaveLoad(t *testing.T) {
+
+	keys := createTestStores(t, 2)
+
+	assert.Nil(t, store.StoreMempool.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		writer.Delete("txs")
+		return nil
+	}))
+
+	mempool := createTestMempool(t)
+	assert.Nil(t, mempool.LoadFromStore(testChainHeight))
+
+	valid := createTestTx(t, keys[0], 0, 10)
+	invalid := createTestTx(t, keys[1], 0, 10)
+	assert.Nil(t, addTestTx(mempool, valid))
+	assert.Nil(t, addTestTx(mempool, invalid))
+
+	//the txs are saved without closing the node
+	assert.Eventually(t, func() bool {
+		return len(readStoredTxs(t)) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	//the second tx was included in a block while the node was down
+	mempool.SuspendProcessingCn <- struct{}{}
+	setTestPlainAccountNonce(t, keys[1], 1)
+	mempool.ContinueProcessingCn <- CONTINUE_PROCESSING_ERROR
+
+	restarted := createTestMempool(t)
+	assert.Nil(t, restarted.LoadFromStore(testChainHeight))
+
+	//the restored txs are saved again once they were revalidated
+	assert.Eventually(t, func() bool {
+		list := readStoredTxs(t)
+		return len(list) == 1 && string(list[0].tx.SerializeManualToBytes()) == string(valid.Bloom.Serialized)
+	}, time.Second, 10*time.Millisecond)
+
+	assert.True(t, restarted.Txs.Exists(valid.Bloom.HashStr))
+	assert.False(t, restarted.Txs.Exists(invalid.Bloom.HashStr))
+	assert.Equal(t, 1, restarted.Txs.Count())
+}
@@ -1,11 +1,16 @@
 package mempool
 
 import (
+	"bytes"
 	"errors"
 	"golang.org/x/exp/slices"
 	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
 	"pandora-pay/config"
+	"pandora-pay/config/config_mempool"
 	"pandora-pay/store"
+	"pandora-pay/store/min_max_heap"
 	"pandora-pay/store/store_db/store_db_interface"
 	"sync/atomic"
 )
@@ -26,8 +31,9 @@ type MempoolWorkerAddTx struct {
 }
 
 type MempoolWorkerRemoveTxs struct {
-	Txs    []string
-	Result chan<- bool
+	Txs           []string
+	EvictedReason string //empty when the txs were included in the blockchain
+	Result        chan<- bool
 }
 
 type MempoolWorkerInsertTxs struct {
@@ -56,39 +62,46 @@ func (worker *mempoolWorker) processing(
 	includedTotalSize := uint64(0)
 	includedTxs := []*mempoolTx{}
 
-	resetNow := func(newWork *mempoolWork) {
+	//set when txs were replaced or evicted. The restart is delayed until the queued txs are added
+	restartPending := false
 
-		if newWork.chainHash != nil {
-			dataStorage = nil
-			work = newWork
-			includedTotalSize = uint64(0)
-			includedTxs = []*mempoolTx{}
-			listIndex = 0
-			if len(txsList) > 1 {
-				sortTxs(txsList)
-			}
+	//the txs accepted while a restart is pending. They are included in dataStorage, but not in includedTxs
+	acceptedTxs := []*mempoolTx{}
+
+	//the txs which can be evicted ordered by FeePerByte, the lowest first. The txs created by us are never evicted
+	evictableHeap := min_max_heap.NewMinMemoryHeap("mempool")
+
+	insertTxNow := func(tx *mempoolTx) {
+		txsMap[tx.Tx.Bloom.HashStr] = tx
+		if !tx.Mine {
+			evictableHeap.Insert(float64(tx.FeePerByte), []byte(tx.Tx.Bloom.HashStr))
 		}
+		txs.insertTx(tx)
+		txs.inserted(tx)
 	}
 
-	removeTxNow := func(tx *mempoolTx, txWasInserted bool, includedInBlockchainNotification bool) {
+	removeTxNow := func(tx *mempoolTx, txWasInserted bool, includedInBlockchainNotification bool, evictedReason string) {
 
+		if txsMap[tx.Tx.Bloom.HashStr] == tx && !tx.Mine {
+			evictableHeap.DeleteByKey([]byte(tx.Tx.Bloom.HashStr))
+		}
 		delete(txsMap, tx.Tx.Bloom.HashStr)
 
 		if txWasInserted {
 			txs.deleteTx(tx.Tx.Bloom.HashStr)
-			txs.deleted(tx, txWasInserted, includedInBlockchainNotification)
+			txs.deleted(tx, txWasInserted, includedInBlockchainNotification, evictedReason)
 
 		}
 	}
 
-	removeTxs := func(data *MempoolWorkerRemoveTxs) {
+	removeTxsNow := func(hashes []string, includedInBlockchainNotification bool, evictedReason string) bool {
 
 		removedTxsMap := make(map[string]bool)
-		for _, hash := range data.Txs {
+		for _, hash := range hashes {
 			if hash != "" {
 				if tx := txsMap[hash]; tx != nil {
 					removedTxsMap[hash] = true
-					removeTxNow(tx, true, true)
+					removeTxNow(tx, true, includedInBlockchainNotification, evictedReason)
 				}
 			}
 		}
@@ -117,16 +130,183 @@ func (worker *mempoolWorker) processing(
 			txsList = newList
 		}
 
-		data.Result <- len(removedTxsMap) > 0
+		return len(removedTxsMap) > 0
+	}
+
+	//the included txs are processed again from the beginning
+	restartNow := func() {
+		restartPending = false
+		dataStorage = nil
+		acceptedTxs = []*mempoolTx{}
+		includedTotalSize = uint64(0)
+		includedTxs = []*mempoolTx{}
+		listIndex = 0
+		if work != nil {
+			atomic.StoreUint64(&work.result.totalSize, 0)
+			work.result.txs.Store(includedTxs)
+		}
+		if len(txsList) > 1 {
+			sortTxs(txsList)
+		}
+	}
+
+	resetNow := func(newWork *mempoolWork) {
+
+		if newWork.chainHash != nil {
+			restartPending = false
+			dataStorage = nil
+			acceptedTxs = []*mempoolTx{}
+			work = newWork
+			includedTotalSize = uint64(0)
+			includedTxs = []*mempoolTx{}
+			listIndex = 0
+
+			removeTxsNow(getExpiredTxs(txsList, work.chainHeight), false, "expired")
+
+			if len(txsList) > 1 {
+				sortTxs(txsList)
+			}
+		}
+	}
+
+	removeTxs := func(data *MempoolWorkerRemoveTxs) {
+		if data.EvictedReason == "" {
+			data.Result <- removeTxsNow(data.Txs, true, "")
+			return
+		}
+		result := removeTxsNow(data.Txs, false, data.EvictedReason)
+		if result {
+			restartNow()
+		}
+		data.Result <- result
+	}
+
+	//returns the txs that must be removed to accept the new tx
+	//a TX_SIMPLE with the same Vin and Nonce is replaced only if the fee is bumped
+	//when the mempool is full, the txs with the lowest FeePerByte are evicted
+	makeRoom := func(newTx *mempoolTx) (replaced, evicted []string, err error) {
+
+		removedMap := make(map[string]bool)
+
+		if newTx.Tx.Version == transaction_type.TX_SIMPLE {
+			newBase := newTx.Tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+			if newBase.HasVin() {
+				for hash, tx := range txsMap {
+					if tx.Tx.Version != transaction_type.TX_SIMPLE {
+						continue
+					}
+					base := tx.Tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+					if base.HasVin() && base.Nonce == newBase.Nonce && bytes.Equal(base.Vin.PublicKey, newBase.Vin.PublicKey) {
+						if newTx.FeePerByte <= tx.FeePerByte || newTx.FeePerByte*100 < tx.FeePerByte*(100+config_mempool.MEMPOOL_REPLACE_BY_FEE_MIN_BUMP) {
+							return nil, nil, errors.New("Replacement transaction fee is too low")
+						}
+						removedMap[hash] = true
+						replaced = append(replaced, hash)
+					}
+				}
+			}
+		}
+
+		count := len(txsMap) + 1
+		size := txs.Size() + newTx.Tx.Bloom.Size
+		for _, hash := range replaced {
+			count -= 1
+			size -= txsMap[hash].Tx.Bloom.Size
+		}
+
+		//the lowest txs are taken out of the heap and inserted back, because the txs are removed only after the new tx is validated
+		popped := []*mempoolTx{}
+		defer func() {
+			for _, tx := range popped {
+				evictableHeap.Insert(float64(tx.FeePerByte), []byte(tx.Tx.Bloom.HashStr))
+			}
+		}()
+
+		for count > config_mempool.MEMPOOL_MAX_TXS || size > config_mempool.MEMPOOL_MAX_SIZE {
+
+			var lowest *mempoolTx
+			for lowest == nil && evictableHeap.GetSize() > 0 {
+				top, err := evictableHeap.RemoveTop()
+				if err != nil {
+					return nil, nil, err
+				}
+				tx := txsMap[string(top.Key)]
+				popped = append(popped, tx)
+				if !removedMap[tx.Tx.Bloom.HashStr] {
+					lowest = tx
+				}
+			}
+
+			if lowest == nil || lowest.FeePerByte >= newTx.FeePerByte {
+				return nil, nil, errors.New("Mempool is full")
+			}
+
+			removedMap[lowest.Tx.Bloom.HashStr] = true
+			evicted = append(evicted, lowest.Tx.Bloom.HashStr)
+			count -= 1
+			size -= lowest.Tx.Bloom.Size
+		}
+
+		return
+	}
+
+	//the new tx is verified against the pending state before any tx is replaced or evicted for it, so the chained txs of an account are accepted.
+	//When txs are removed for it, the pending state is rebuilt without them. The pending state keeps the new tx until the restart
+	validateNewTx := func(newTx *mempoolTx, removed []string, dbTx store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		defer func() {
+			if errReturned := recover(); errReturned != nil {
+				err = errReturned.(error)
+			}
+		}()
+
+		if dbTx.Exists("txHash:" + newTx.Tx.Bloom.HashStr) {
+			return errors.New("Tx is already included in blockchain")
+		}
+
+		newDataStorage := dataStorage
+		if len(removed) > 0 {
+
+			removedMap := make(map[string]bool)
+			for _, hash := range removed {
+				removedMap[hash] = true
+			}
+
+			newDataStorage = data_storage.NewDataStorage(dbTx)
+			for _, list := range [][]*mempoolTx{includedTxs, acceptedTxs} {
+				for _, tx := range list {
+					if removedMap[tx.Tx.Bloom.HashStr] || txsMap[tx.Tx.Bloom.HashStr] != tx {
+						continue
+					}
+					if err = tx.Tx.IncludeTransaction(work.chainHeight, newDataStorage); err != nil {
+						newDataStorage.Rollback()
+						continue
+					}
+					if err = newDataStorage.CommitChanges(); err != nil {
+						return
+					}
+				}
+			}
+		}
+
+		if err = newTx.Tx.IncludeTransaction(work.chainHeight, newDataStorage); err != nil {
+			newDataStorage.Rollback()
+			return
+		}
+		if err = newDataStorage.CommitChanges(); err != nil {
+			return
+		}
+
+		dataStorage = newDataStorage
+		acceptedTxs = append(acceptedTxs, newTx)
+		return
 	}
 
 	insertTxs := func(data *MempoolWorkerInsertTxs) {
 		result := false
 		for _, tx := range data.Txs {
 			if tx != nil && txsMap[tx.Tx.Bloom.HashStr] == nil {
-				txsMap[tx.Tx.Bloom.HashStr] = tx
-				txs.insertTx(tx)
-				txs.inserted(tx)
+				insertTxNow(tx)
 				txsList = append(txsList, tx)
 				result = true
 			}
@@ -157,6 +337,7 @@ func (worker *mempoolWorker) processing(
 				work = nil //it needs a new work
 			case CONTINUE_PROCESSING_NO_ERROR_RESET:
 				dataStorage = nil
+				acceptedTxs = []*mempoolTx{}
 				listIndex = 0
 			}
 
@@ -185,7 +366,13 @@ func (worker *mempoolWorker) processing(
 				tx = nil
 				newAddTx = nil
 
-				if listIndex == len(txsList) {
+				//the restart is done only once all the queued txs were added
+				if restartPending && len(addTransactionCn) == 0 {
+					restartNow()
+					continue
+				}
+
+				if listIndex == len(txsList) || restartPending {
 					select {
 					case newWork := <-newWorkCn:
 						resetNow(newWork)
@@ -203,6 +390,39 @@ func (worker *mempoolWorker) processing(
 							}
 							continue
 						}
+
+						replaced, evicted, err := makeRoom(newAddTx.Tx)
+						if err != nil {
+							if newAddTx.Result != nil {
+								newAddTx.Result <- err
+							}
+							continue
+						}
+
+						//while a restart is pending, the new tx is inserted and processed after the restart
+						if len(replaced) > 0 || len(evicted) > 0 || restartPending {
+
+							if err = validateNewTx(newAddTx.Tx, append(replaced, evicted...), dbTx); err != nil {
+								if newAddTx.Result != nil {
+									newAddTx.Result <- err
+								}
+								continue
+							}
+
+							removeTxsNow(replaced, false, "replaced")
+							removeTxsNow(evicted, false, "mempool is full")
+
+							txsList = append(txsList, newAddTx.Tx)
+							insertTxNow(newAddTx.Tx)
+
+							restartPending = true
+
+							if newAddTx.Result != nil {
+								newAddTx.Result <- nil
+							}
+							continue
+						}
+
 						tx = newAddTx.Tx
 					}
 				} else {
@@ -268,9 +488,7 @@ func (worker *mempoolWorker) processing(
 							if newAddTx != nil {
 								listIndex += 1
 								txsList = append(txsList, newAddTx.Tx)
-								txsMap[tx.Tx.Bloom.HashStr] = newAddTx.Tx
-								txs.insertTx(tx)
-								txs.inserted(tx)
+								insertTxNow(tx)
 							}
 
 						}
@@ -285,7 +503,11 @@ func (worker *mempoolWorker) processing(
 							txsList = slices.Delete(txsList, listIndex-1, listIndex)
 							listIndex--
 						}
-						removeTxNow(tx, newAddTx == nil, exists)
+						evictedReason := ""
+						if !exists {
+							evictedReason = finalErr.Error()
+						}
+						removeTxNow(tx, newAddTx == nil, exists, evictedReason)
 					}
 
 				}
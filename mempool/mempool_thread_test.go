@@ -0,0 +1,110 @@
+package mempool
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config/config_mempool"
+	"testing"
+	"time"
+)
+
+// the txs are processed by the worker in the background
+func assertTestMempoolTxs(t *testing.T, mempool *Mempool, included []*transaction.Transaction, removed []*transaction.Transaction) {
+	assert.Eventually(t, func() bool {
+
+		for _, tx := range included {
+			if !mempool.Txs.Exists(tx.Bloom.HashStr) {
+				return false
+			}
+		}
+		for _, tx := range removed {
+			if mempool.Txs.Exists(tx.Bloom.HashStr) {
+				return false
+			}
+		}
+
+		txs, _ := mempool.GetNextTransactionsToInclude(nil)
+		return len(txs) == len(included)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestMempoolReplaceByFeeChained(t *testing.T) {
+
+	keys := createTestStores(t, 1)
+	mempool := createTestMempool(t)
+
+	tx0 := createTestTx(t, keys[0], 0, 10)
+	tx1 := createTestTx(t, keys[0], 1, 10)
+	tx2 := createTestTx(t, keys[0], 2, 10)
+	assert.Nil(t, addTestTx(mempool, tx0))
+	assert.Nil(t, addTestTx(mempool, tx1))
+	assert.Nil(t, addTestTx(mempool, tx2))
+	assertTestMempoolTxs(t, mempool, []*transaction.Transaction{tx0, tx1, tx2}, nil)
+
+	assert.NotNil(t, addTestTx(mempool, createTestTx(t, keys[0], 1, 10)), "the fee was not bumped")
+
+	//a tx which is not the first of the account is replaced and the chained tx is kept
+	tx1Replaced := createTestTx(t, keys[0], 1, 20)
+	assert.Nil(t, addTestTx(mempool, tx1Replaced))
+	assertTestMempoolTxs(t, mempool, []*transaction.Transaction{tx0, tx1Replaced, tx2}, []*transaction.Transaction{tx1})
+
+	//the first tx is replaced by a fee higher than the fees of its chained txs
+	tx0Replaced := createTestTx(t, keys[0], 0, 50)
+	assert.Nil(t, addTestTx(mempool, tx0Replaced))
+	assertTestMempoolTxs(t, mempool, []*transaction.Transaction{tx0Replaced, tx1Replaced, tx2}, []*transaction.Transaction{tx0, tx1})
+
+	assert.NotNil(t, addTestTx(mempool, createTestTx(t, keys[0], 5, 10)), "the nonce is not chained")
+}
+
+func TestMempoolEvictLowestFee(t *testing.T) {
+
+	maxTxs := config_mempool.MEMPOOL_MAX_TXS
+	config_mempool.MEMPOOL_MAX_TXS = 3
+	defer func() {
+		config_mempool.MEMPOOL_MAX_TXS = maxTxs
+	}()
+
+	keys := createTestStores(t, 5)
+	mempool := createTestMempool(t)
+
+	txs := []*transaction.Transaction{createTestTx(t, keys[0], 0, 20), createTestTx(t, keys[1], 0, 10), createTestTx(t, keys[2], 0, 30)}
+	for _, tx := range txs {
+		assert.Nil(t, addTestTx(mempool, tx))
+	}
+
+	assert.NotNil(t, addTestTx(mempool, createTestTx(t, keys[3], 0, 10)), "the fee must be higher than the lowest fee")
+
+	//the tx with the lowest fee is evicted
+	tx := createTestTx(t, keys[3], 0, 15)
+	assert.Nil(t, addTestTx(mempool, tx))
+	assertTestMempoolTxs(t, mempool, []*transaction.Transaction{txs[0], txs[2], tx}, []*transaction.Transaction{txs[1]})
+
+	tx2 := createTestTx(t, keys[4], 0, 25)
+	assert.Nil(t, addTestTx(mempool, tx2))
+	assertTestMempoolTxs(t, mempool, []*transaction.Transaction{txs[0], txs[2], tx2}, []*transaction.Transaction{txs[1], tx})
+	assert.Equal(t, 3, mempool.Txs.Count())
+}
+
+func TestMempoolSizeCap(t *testing.T) {
+
+	keys := createTestStores(t, 3)
+	mempool := createTestMempool(t)
+
+	txs := []*transaction.Transaction{createTestTx(t, keys[0], 0, 10), createTestTx(t, keys[1], 0, 20)}
+
+	maxSize := config_mempool.MEMPOOL_MAX_SIZE
+	config_mempool.MEMPOOL_MAX_SIZE = txs[0].Bloom.Size + txs[1].Bloom.Size
+	defer func() {
+		config_mempool.MEMPOOL_MAX_SIZE = maxSize
+	}()
+
+	for _, tx := range txs {
+		assert.Nil(t, addTestTx(mempool, tx))
+	}
+	assertTestMempoolTxs(t, mempool, txs, nil)
+
+	tx := createTestTx(t, keys[2], 0, 30)
+	assert.Nil(t, addTestTx(mempool, tx))
+	assertTestMempoolTxs(t, mempool, []*transaction.Transaction{txs[1], tx}, []*transaction.Transaction{txs[0]})
+	assert.LessOrEqual(t, mempool.Txs.Size(), config_mempool.MEMPOOL_MAX_SIZE)
+}
@@ -23,6 +23,8 @@ type MempoolAccountTxs struct {
 }
 
 type MempoolTxs struct {
+	size                      uint64 //bytes
+	changes                   uint64 //incremented every time a tx is inserted or deleted
 	count                     int32
 	txsMap                    *generics.Map[string, *mempoolTx]
 	accountsMapTxs            *generics.Map[string, *MempoolAccountTxs]
@@ -33,6 +35,8 @@ func (self *MempoolTxs) insertTx(tx *mempoolTx) bool {
 	_, loaded := self.txsMap.LoadOrStore(tx.Tx.Bloom.HashStr, tx)
 	if !loaded {
 		atomic.AddInt32(&self.count, 1)
+		atomic.AddUint64(&self.size, tx.Tx.Bloom.Size)
+		atomic.AddUint64(&self.changes, 1)
 	}
 	return !loaded
 }
@@ -64,6 +68,7 @@ func (self *MempoolTxs) inserted(tx *mempoolTx) {
 			true,
 			tx.Tx,
 			false,
+			"",
 			keys,
 		})
 
@@ -71,14 +76,16 @@ func (self *MempoolTxs) inserted(tx *mempoolTx) {
 }
 
 func (self *MempoolTxs) deleteTx(hashStr string) bool {
-	_, deleted := self.txsMap.LoadAndDelete(hashStr)
+	tx, deleted := self.txsMap.LoadAndDelete(hashStr)
 	if deleted {
 		atomic.AddInt32(&self.count, -1)
+		atomic.AddUint64(&self.size, ^(tx.Tx.Bloom.Size - 1))
+		atomic.AddUint64(&self.changes, 1)
 	}
 	return deleted
 }
 
-func (self *MempoolTxs) deleted(tx *mempoolTx, broadcastNotifications, includedInBlockchainNotification bool) {
+func (self *MempoolTxs) deleted(tx *mempoolTx, broadcastNotifications, includedInBlockchainNotification bool, evictedReason string) {
 	if config.NODE_PROVIDE_EXTENDED_INFO_APP {
 
 		keys := tx.Tx.GetAllKeys()
@@ -100,6 +107,7 @@ func (self *MempoolTxs) deleted(tx *mempoolTx, broadcastNotifications, includedI
 				false,
 				tx.Tx,
 				includedInBlockchainNotification,
+				evictedReason,
 				keys,
 			})
 		}
@@ -142,6 +150,18 @@ func (self *MempoolTxs) GetTxsOnlyList() []*transaction.Transaction {
 	return out
 }
 
+func (self *MempoolTxs) Count() int {
+	return int(atomic.LoadInt32(&self.count))
+}
+
+func (self *MempoolTxs) Size() uint64 {
+	return atomic.LoadUint64(&self.size)
+}
+
+func (self *MempoolTxs) Changes() uint64 {
+	return atomic.LoadUint64(&self.changes)
+}
+
 func (self *MempoolTxs) Exists(txId string) bool {
 	_, loaded := self.txsMap.Load(txId)
 	return loaded
@@ -182,6 +202,8 @@ func (self *MempoolTxs) GetAccountTxs(publicKey []byte) []*mempoolTx {
 func createMempoolTxs() (txs *MempoolTxs) {
 
 	txs = &MempoolTxs{
+		0,
+		0,
 		0,
 		&generics.Map[string, *mempoolTx]{},
 		&generics.Map[string, *MempoolAccountTxs]{},
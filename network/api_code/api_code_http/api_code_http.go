@@ -6,28 +6,23 @@ import (
 	"net/http"
 	"net/url"
 	"pandora-pay/helpers/urldecoder"
-	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/network_config/network_config_auth"
 )
 
-func HandleAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values url.Values) (interface{}, error) {
-	return func(values url.Values) (interface{}, error) {
-
-		authenticated := api_code_types.CheckAuthenticated(values)
-		values.Del("user")
-		values.Del("pass")
-
+func HandleAuthenticated[T any, B any](role network_config_auth.Role, callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values url.Values, auth *network_config_auth.ConfigAuth) (interface{}, error) {
+	return func(values url.Values, auth *network_config_auth.ConfigAuth) (interface{}, error) {
 		args := new(T)
 		if err := urldecoder.Decoder.Decode(args, values); err != nil {
 			return nil, err
 		}
 
 		reply := new(B)
-		return reply, callback(nil, args, reply, authenticated)
+		return reply, callback(nil, args, reply, auth.HasRole(role))
 	}
 }
 
-func Handle[T any, B any](callback func(r *http.Request, args *T, reply *B) error) func(values url.Values) (interface{}, error) {
-	return func(values url.Values) (interface{}, error) {
+func Handle[T any, B any](callback func(r *http.Request, args *T, reply *B) error) func(values url.Values, auth *network_config_auth.ConfigAuth) (interface{}, error) {
+	return func(values url.Values, auth *network_config_auth.ConfigAuth) (interface{}, error) {
 		args := new(T)
 		if err := urldecoder.Decoder.Decode(args, values); err != nil {
 			return nil, err
@@ -38,21 +33,20 @@ func Handle[T any, B any](callback func(r *http.Request, args *T, reply *B) erro
 	}
 }
 
-func HandlePOSTAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values io.ReadCloser) (interface{}, error) {
-	return func(values io.ReadCloser) (interface{}, error) {
-
-		authenticated := new(api_code_types.APIAuthenticated[T])
-		if err := json.NewDecoder(values).Decode(authenticated); err != nil {
+func HandlePOSTAuthenticated[T any, B any](role network_config_auth.Role, callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values io.ReadCloser, auth *network_config_auth.ConfigAuth) (interface{}, error) {
+	return func(values io.ReadCloser, auth *network_config_auth.ConfigAuth) (interface{}, error) {
+		args := new(T)
+		if err := json.NewDecoder(values).Decode(args); err != nil {
 			return nil, err
 		}
 
 		reply := new(B)
-		return reply, callback(nil, authenticated.Data, reply, authenticated.CheckAuthenticated())
+		return reply, callback(nil, args, reply, auth.HasRole(role))
 	}
 }
 
-func HandlePOST[T any, B any](callback func(r *http.Request, args *T, reply *B) error) func(values io.ReadCloser) (interface{}, error) {
-	return func(values io.ReadCloser) (interface{}, error) {
+func HandlePOST[T any, B any](callback func(r *http.Request, args *T, reply *B) error) func(values io.ReadCloser, auth *network_config_auth.ConfigAuth) (interface{}, error) {
+	return func(values io.ReadCloser, auth *network_config_auth.ConfigAuth) (interface{}, error) {
 		args := new(T)
 
 		if err := json.NewDecoder(values).Decode(args); err != nil {
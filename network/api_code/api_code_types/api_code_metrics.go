@@ -0,0 +1,5 @@
+package api_code_types
+
+import "pandora-pay/helpers/metrics"
+
+var APIRequestDuration = metrics.NewHistogramVec("pandora_api_request_duration_seconds", "Latency of the API requests per method", "method", metrics.DefaultBuckets)
@@ -13,6 +13,11 @@ const (
 	SUBSCRIPTION_ASSET
 	SUBSCRIPTION_REGISTRATION
 	SUBSCRIPTION_TRANSACTION
+	SUBSCRIPTION_CHAIN
+	SUBSCRIPTION_MEMPOOL
+	SUBSCRIPTION_CONDITIONAL_PAYMENT
+	SUBSCRIPTION_PENDING_STAKE
+	SUBSCRIPTION_REORG_ALERT
 )
 
 type APISubscriptionNotification struct {
@@ -22,6 +27,14 @@ type APISubscriptionNotification struct {
 	Extra            []byte           `json:"extra,omitempty" msgpack:"extra,omitempty"`
 }
 
+// APISubscriptionNotificationJSON is the notification delivered to the HTTP subscribers. Data and Extra are decoded into JSON
+type APISubscriptionNotificationJSON struct {
+	SubscriptionType SubscriptionType `json:"type"`
+	Key              helpers.Base64   `json:"key"`
+	Data             any              `json:"data,omitempty"`
+	Extra            any              `json:"extra,omitempty"`
+}
+
 type APISubscriptionRequest struct {
 	Key        helpers.Base64   `json:"key,omitempty" msgpack:"key,omitempty"`
 	Type       SubscriptionType `json:"type,omitempty"  msgpack:"type,omitempty"`
@@ -5,12 +5,13 @@ import (
 	"pandora-pay/helpers/msgpack"
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/network_config/network_config_auth"
 	"pandora-pay/network/websocks/connection"
 )
 
 var SubscriptionNotifications *multicast.MulticastChannel[*api_code_types.APISubscriptionNotification]
 
-func HandleAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+func HandleAuthenticated[T any, B any](role network_config_auth.Role, callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
 	return func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
 		args := new(T)
 		if err := msgpack.Unmarshal(values, args); err != nil {
@@ -18,7 +19,7 @@ func HandleAuthenticated[T any, B any](callback func(r *http.Request, args *T, r
 		}
 
 		reply := new(B)
-		return reply, callback(nil, args, reply, conn.Authenticated.IsSet())
+		return reply, callback(nil, args, reply, conn.Auth.Load().HasRole(role))
 	}
 }
 
@@ -12,7 +12,8 @@ type APILogin struct {
 }
 
 type APILoginReply struct {
-	Status bool `json:"status" msgpack:"status"`
+	Status bool                       `json:"status" msgpack:"status"`
+	Roles  []network_config_auth.Role `json:"roles,omitempty" msgpack:"roles,omitempty"`
 }
 
 func Login(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
@@ -22,13 +23,14 @@ func Login(conn *connection.AdvancedConnection, values []byte) (interface{}, err
 	}
 	reply := &APILoginReply{}
 
-	user := network_config_auth.CONFIG_AUTH_USERS_MAP[args.Username]
-	if user == nil || user.Password != args.Password {
+	auth := network_config_auth.Authenticate(args.Username, args.Password)
+	if auth == nil {
 		return reply, nil
 	}
 
-	conn.Authenticated.Set()
+	conn.Auth.Store(auth)
 	reply.Status = true
+	reply.Roles = auth.Roles
 
 	return reply, nil
 }
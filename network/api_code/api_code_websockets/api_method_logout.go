@@ -12,11 +12,11 @@ func Logout(conn *connection.AdvancedConnection, values []byte) (interface{}, er
 
 	reply := &APILogoutReply{}
 
-	if conn.Authenticated.IsNotSet() {
+	if conn.Auth.Load() == nil {
 		return reply, nil
 	}
 
-	conn.Authenticated.UnSet()
+	conn.Auth.Store(nil)
 	reply.Status = true
 
 	return reply, nil
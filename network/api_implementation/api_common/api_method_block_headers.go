@@ -0,0 +1,56 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/config"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type APIBlockHeadersRequest struct {
+	Start uint64 `json:"start" msgpack:"start"`
+	Count uint64 `json:"count" msgpack:"count"`
+}
+
+type APIBlockHeadersReply struct {
+	Headers [][]byte `json:"headers" msgpack:"headers"` //serialized blocks without the transactions
+}
+
+// GetBlockHeaders returns the consecutive block headers starting with Start. At most config.FORK_MAX_HEADERS are returned
+func (api *APICommon) GetBlockHeaders(r *http.Request, args *APIBlockHeadersRequest, reply *APIBlockHeadersReply) error {
+
+	count := args.Count
+	if count == 0 || count > config.FORK_MAX_HEADERS {
+		count = config.FORK_MAX_HEADERS
+	}
+
+	end := args.Start + count
+	if height := api.ApiStore.chain.GetChainData().Height; end > height {
+		end = height
+	}
+	if args.Start >= end {
+		return errors.New("Start is invalid")
+	}
+
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		reply.Headers = make([][]byte, 0, end-args.Start)
+		for height := args.Start; height < end; height++ {
+
+			var hash []byte
+			if hash, err = api.ApiStore.chain.LoadBlockHash(reader, height); err != nil {
+				return
+			}
+
+			data := reader.Get("block_ByHash" + string(hash))
+			if data == nil {
+				return errors.New("Block was not found")
+			}
+			reply.Headers = append(reply.Headers, helpers.CloneBytes(data))
+		}
+
+		return
+	})
+}
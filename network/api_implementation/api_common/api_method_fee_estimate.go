@@ -0,0 +1,96 @@
+package api_common
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/asset_fee_liquidity"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers"
+	"pandora-pay/mempool"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type APIFeeEstimateRequest struct {
+	Version     transaction_type.TransactionVersion `json:"version,omitempty" msgpack:"version,omitempty"`
+	Blocks      uint64                              `json:"blocks,omitempty" msgpack:"blocks,omitempty"`
+	Target      uint64                              `json:"target,omitempty" msgpack:"target,omitempty"`
+	Percentiles []uint64                            `json:"percentiles,omitempty" msgpack:"percentiles,omitempty"`
+	Asset       helpers.Base64                      `json:"asset,omitempty" msgpack:"asset,omitempty"`
+}
+
+type APIFeeEstimateAsset struct {
+	Asset        []byte   `json:"asset" msgpack:"asset"`
+	Rate         uint64   `json:"rate" msgpack:"rate"`
+	LeadingZeros byte     `json:"leadingZeros" msgpack:"leadingZeros"`
+	Minimum      uint64   `json:"minimum" msgpack:"minimum"`
+	Backlog      uint64   `json:"backlog" msgpack:"backlog"`
+	FeePerByte   []uint64 `json:"feePerByte" msgpack:"feePerByte"`
+	Recommended  uint64   `json:"recommended" msgpack:"recommended"`
+}
+
+type APIFeeEstimateReply struct {
+	*mempool.FeeEstimate
+	Asset *APIFeeEstimateAsset `json:"asset,omitempty" msgpack:"asset,omitempty"`
+}
+
+//converts a native FeePerByte into the asset using the rate of the fee liquidity, rounding up
+func convertFeeToAsset(fee uint64, liquidity *asset_fee_liquidity.AssetFeeLiquidity) (uint64, error) {
+	if err := helpers.SafeUint64Mul(&fee, helpers.Pow10(liquidity.LeadingZeros)); err != nil {
+		return 0, err
+	}
+	return (fee + liquidity.Rate - 1) / liquidity.Rate, nil
+}
+
+func (api *APICommon) FeeEstimate(r *http.Request, args *APIFeeEstimateRequest, reply *APIFeeEstimateReply) (err error) {
+
+	if reply.FeeEstimate, err = api.mempool.EstimateFee(args.Version, args.Blocks, args.Target, args.Percentiles); err != nil {
+		return
+	}
+
+	if len(args.Asset) == 0 || bytes.Equal(args.Asset, config_coins.NATIVE_ASSET_FULL) {
+		return
+	}
+
+	if args.Version != transaction_type.TX_ZETHER {
+		return errors.New("Only Zether transactions can pay fees in assets")
+	}
+
+	var liquidity *asset_fee_liquidity.AssetFeeLiquidity
+	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		liquidity, err = data_storage.NewDataStorage(reader).GetAssetFeeLiquidityTop(args.Asset)
+		return
+	}); err != nil {
+		return
+	}
+	if liquidity == nil || liquidity.Rate == 0 {
+		return errors.New("There is no Asset Fee Liquidity for this asset")
+	}
+
+	reply.Asset = &APIFeeEstimateAsset{
+		Asset:        args.Asset,
+		Rate:         liquidity.Rate,
+		LeadingZeros: liquidity.LeadingZeros,
+		FeePerByte:   make([]uint64, len(reply.FeePerByte)),
+	}
+
+	if reply.Asset.Minimum, err = convertFeeToAsset(reply.Minimum, liquidity); err != nil {
+		return
+	}
+	if reply.Asset.Backlog, err = convertFeeToAsset(reply.Backlog, liquidity); err != nil {
+		return
+	}
+	for i, fee := range reply.FeePerByte {
+		if reply.Asset.FeePerByte[i], err = convertFeeToAsset(fee, liquidity); err != nil {
+			return
+		}
+	}
+	if reply.Asset.Recommended, err = convertFeeToAsset(reply.Recommended, liquidity); err != nil {
+		return
+	}
+
+	return
+}
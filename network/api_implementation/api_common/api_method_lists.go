@@ -0,0 +1,81 @@
+package api_common
+
+import (
+	"fmt"
+	"net/http"
+	"pandora-pay/blockchain/data_storage/assets"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/blockchain/data_storage/conditional_payments_list"
+	"pandora-pay/blockchain/data_storage/conditional_payments_list/conditional_payment"
+	"pandora-pay/blockchain/data_storage/registrations"
+	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/config"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/hash_map"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+// APIListRequest pages the elements in key order. The Next of a reply is the Cursor of the following page
+type APIListRequest struct {
+	Cursor helpers.Base64 `json:"cursor,omitempty" msgpack:"cursor,omitempty"`
+	Limit  int            `json:"limit,omitempty" msgpack:"limit,omitempty"`
+}
+
+type APIListReply[T hash_map.HashMapElementSerializableInterface] struct {
+	Keys     []helpers.Base64 `json:"keys" msgpack:"keys"`
+	Elements []T              `json:"elements" msgpack:"elements"`
+	Next     helpers.Base64   `json:"next,omitempty" msgpack:"next,omitempty"` //empty when there are no more elements
+}
+
+type APIConditionalPaymentsListRequest struct {
+	APIListRequest
+	BlockHeight uint64 `json:"blockHeight" msgpack:"blockHeight"`
+}
+
+func listHashMap[T hash_map.HashMapElementSerializableInterface](args *APIListRequest, reply *APIListReply[T], create func(reader store_db_interface.StoreDBTransactionInterface) *hash_map.HashMap[T]) error {
+
+	limit := args.Limit
+	if limit == 0 {
+		limit = config.API_LIST_MAX_RESULTS
+	}
+	if limit < 0 || limit > config.API_LIST_MAX_RESULTS {
+		return fmt.Errorf("Invalid limit: maximum %d", config.API_LIST_MAX_RESULTS)
+	}
+
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+
+		keys, elements, next, err := create(reader).Iterate(string(args.Cursor), limit)
+		if err != nil {
+			return err
+		}
+
+		reply.Keys = make([]helpers.Base64, len(keys))
+		for i, key := range keys {
+			reply.Keys[i] = []byte(key)
+		}
+		reply.Elements = elements
+		if next != "" {
+			reply.Next = []byte(next)
+		}
+		return nil
+	})
+}
+
+func (api *APICommon) GetAssetsList(r *http.Request, args *APIListRequest, reply *APIListReply[*asset.Asset]) error {
+	return listHashMap(args, reply, func(reader store_db_interface.StoreDBTransactionInterface) *hash_map.HashMap[*asset.Asset] {
+		return assets.NewAssets(reader).HashMap
+	})
+}
+
+func (api *APICommon) GetRegistrationsList(r *http.Request, args *APIListRequest, reply *APIListReply[*registration.Registration]) error {
+	return listHashMap(args, reply, func(reader store_db_interface.StoreDBTransactionInterface) *hash_map.HashMap[*registration.Registration] {
+		return registrations.NewRegistrations(reader).HashMap
+	})
+}
+
+func (api *APICommon) GetConditionalPaymentsList(r *http.Request, args *APIConditionalPaymentsListRequest, reply *APIListReply[*conditional_payment.ConditionalPayment]) error {
+	return listHashMap(&args.APIListRequest, reply, func(reader store_db_interface.StoreDBTransactionInterface) *hash_map.HashMap[*conditional_payment.ConditionalPayment] {
+		return conditional_payments_list.NewConditionalPaymentsHashMap(reader, args.BlockHeight).HashMap
+	})
+}
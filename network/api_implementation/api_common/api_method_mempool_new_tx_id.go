@@ -6,6 +6,7 @@ import (
 	"errors"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/network/network_config"
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/txs_validator"
 )
@@ -53,17 +54,20 @@ func (api *APICommon) mempoolNewTxIdProcess(conn *connection.AdvancedConnection,
 
 	tx := &transaction.Transaction{}
 	if err = tx.Deserialize(advanced_buffers.NewBufferReader(result.Tx)); err != nil {
+		conn.Penalize(network_config.NETWORK_PENALTY_INVALID_TX, "Invalid tx")
 		closeConnection = true
 		return
 	}
 
 	if err = txs_validator.TxsValidator.ValidateTx(tx); err != nil {
+		conn.Penalize(network_config.NETWORK_PENALTY_INVALID_TX, "Invalid tx")
 		closeConnection = true
 		return
 	}
 
 	if !bytes.Equal(tx.Bloom.Hash, hash) {
 		err = errors.New("Wrong transaction")
+		conn.Penalize(network_config.NETWORK_PENALTY_INVALID_TX, "Wrong transaction")
 		closeConnection = true
 		return
 	}
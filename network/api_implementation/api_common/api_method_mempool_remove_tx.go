@@ -0,0 +1,28 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/cryptography"
+)
+
+type APIMempoolRemoveTxRequest struct {
+	Hash []byte `json:"hash" msgpack:"hash"`
+}
+
+type APIMempoolRemoveTxReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (api *APICommon) MempoolRemoveTx(r *http.Request, args *APIMempoolRemoveTxRequest, reply *APIMempoolRemoveTxReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if len(args.Hash) != cryptography.HashSize {
+		return errors.New("TxId must be 32 byte")
+	}
+
+	reply.Result = api.mempool.RemoveTx(string(args.Hash), "removed manually")
+	return nil
+}
@@ -0,0 +1,82 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/network/banned_nodes"
+	"pandora-pay/network/connected_nodes"
+	"pandora-pay/network/network_config"
+	"time"
+)
+
+type APINetworkBansReply struct {
+	Bans   []*banned_nodes.BannedNode `json:"bans" msgpack:"bans"`
+	Scores []*banned_nodes.PeerScore  `json:"scores" msgpack:"scores"`
+}
+
+type APINetworkBanAddRequest struct {
+	URL      string `json:"url" msgpack:"url"`
+	Duration uint64 `json:"duration,omitempty" msgpack:"duration,omitempty"` //seconds
+	Message  string `json:"message,omitempty" msgpack:"message,omitempty"`
+}
+
+type APINetworkBanAddReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+type APINetworkBanRemoveRequest struct {
+	URL string `json:"url" msgpack:"url"`
+}
+
+type APINetworkBanRemoveReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (api *APICommon) GetNetworkBans(r *http.Request, args *struct{}, reply *APINetworkBansReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Bans = banned_nodes.BannedNodes.GetBannedNodes()
+	reply.Scores = banned_nodes.BannedNodes.GetPeerScores()
+	return nil
+}
+
+func (api *APICommon) NetworkBanAdd(r *http.Request, args *APINetworkBanAddRequest, reply *APINetworkBanAddReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if args.URL == "" {
+		return errors.New("URL is missing")
+	}
+
+	duration := network_config.NETWORK_BAN_DURATION
+	if args.Duration > 0 {
+		duration = time.Duration(args.Duration) * time.Second
+	}
+	if args.Message == "" {
+		args.Message = "banned manually"
+	}
+
+	banned_nodes.BannedNodes.Ban(nil, args.URL, args.Message, duration)
+
+	//disconnecting the peers already connected
+	for _, conn := range connected_nodes.ConnectedNodes.AllList.Get() {
+		if banned_nodes.BannedNodes.IsBanned(conn.RemoteAddr) {
+			conn.Close()
+		}
+	}
+
+	reply.Result = true
+	return nil
+}
+
+func (api *APICommon) NetworkBanRemove(r *http.Request, args *APINetworkBanRemoveRequest, reply *APINetworkBanRemoveReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Result = banned_nodes.BannedNodes.Unban(args.URL)
+	return nil
+}
@@ -0,0 +1,77 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"pandora-pay/network/known_nodes"
+	"pandora-pay/network/known_nodes/known_node"
+)
+
+type APINetworkKnownNodesReply struct {
+	Nodes []*known_node.KnownNodeInfo `json:"nodes" msgpack:"nodes"`
+}
+
+type APINetworkKnownNodeAddRequest struct {
+	URL string `json:"url" msgpack:"url"`
+}
+
+type APINetworkKnownNodeAddReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+type APINetworkKnownNodeRemoveRequest struct {
+	URL string `json:"url" msgpack:"url"`
+}
+
+type APINetworkKnownNodeRemoveReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (api *APICommon) GetNetworkKnownNodes(r *http.Request, args *struct{}, reply *APINetworkKnownNodesReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Nodes = known_nodes.KnownNodes.GetInfoList()
+	return nil
+}
+
+func (api *APICommon) NetworkKnownNodeAdd(r *http.Request, args *APINetworkKnownNodeAddRequest, reply *APINetworkKnownNodeAddReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	u, err := url.Parse(args.URL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "ws" && u.Scheme != "wss" || u.Host == "" {
+		return errors.New("URL must be a websocket address")
+	}
+
+	if _, err = known_nodes.KnownNodes.AddKnownNode(args.URL, false, known_node.KNOWN_NODE_SOURCE_MANUAL); err != nil {
+		return err
+	}
+
+	reply.Result = true
+	return nil
+}
+
+func (api *APICommon) NetworkKnownNodeRemove(r *http.Request, args *APINetworkKnownNodeRemoveRequest, reply *APINetworkKnownNodeRemoveReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	knownNode := known_nodes.KnownNodes.GetKnownNode(args.URL)
+	if knownNode == nil {
+		return nil
+	}
+	if knownNode.IsSeed {
+		return errors.New("Seed nodes can't be removed")
+	}
+
+	known_nodes.KnownNodes.RemoveKnownNode(knownNode)
+	reply.Result = true
+	return nil
+}
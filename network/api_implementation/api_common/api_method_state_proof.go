@@ -0,0 +1,108 @@
+package api_common
+
+import (
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/accounts"
+	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
+	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+	"pandora-pay/store"
+	"pandora-pay/store/hash_map"
+	"pandora-pay/store/sparse_merkle_tree"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type APIStateProofType string
+
+const (
+	API_STATE_PROOF_ACCOUNT       APIStateProofType = "account"
+	API_STATE_PROOF_PLAIN_ACCOUNT APIStateProofType = "plainAccount"
+	API_STATE_PROOF_REGISTRATION  APIStateProofType = "registration"
+	API_STATE_PROOF_ASSET         APIStateProofType = "asset"
+)
+
+type APIStateProofRequest struct {
+	api_types.APIAccountBaseRequest
+	Type  APIStateProofType `json:"type" msgpack:"type"`
+	Asset helpers.Base64    `json:"asset,omitempty" msgpack:"asset,omitempty"`
+}
+
+type APIStateProofReply struct {
+	Height       uint64                      `json:"height" msgpack:"height"`                             //height of the last block
+	StateRoot    []byte                      `json:"stateRoot" msgpack:"stateRoot"`                       //root of the state after the last block. The next block includes it
+	Key          []byte                      `json:"key" msgpack:"key"`                                   //key of the element in the state tree
+	Serialized   []byte                      `json:"serialized,omitempty" msgpack:"serialized,omitempty"` //missing when the element doesn't exist
+	Proof        *sparse_merkle_tree.Proof   `json:"proof" msgpack:"proof"`
+	Account      *account.Account            `json:"account,omitempty" msgpack:"account,omitempty"`
+	PlainAccount *plain_account.PlainAccount `json:"plainAccount,omitempty" msgpack:"plainAccount,omitempty"`
+	Registration *registration.Registration  `json:"registration,omitempty" msgpack:"registration,omitempty"`
+	Asset        *asset.Asset                `json:"asset,omitempty" msgpack:"asset,omitempty"`
+}
+
+func loadStateProof[T hash_map.HashMapElementSerializableInterface](hashMap *hash_map.HashMap[T], key string, reply *APIStateProofReply) (element T, err error) {
+	reply.Key = hashMap.StateKey(key)
+	if reply.Serialized, reply.Proof, err = hashMap.StateProof(key); err != nil || reply.Serialized == nil {
+		return
+	}
+	return hashMap.Get(key)
+}
+
+func (api *APICommon) GetStateProof(r *http.Request, args *APIStateProofRequest, reply *APIStateProofReply) error {
+
+	var key []byte
+	var err error
+	if args.Type == API_STATE_PROOF_ASSET {
+		if len(args.Asset) != config_coins.ASSET_LENGTH {
+			return errors.New("Invalid asset")
+		}
+		key = args.Asset
+	} else if key, err = args.GetPublicKey(true); err != nil {
+		return err
+	}
+
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		dataStorage := data_storage.NewDataStorage(reader)
+
+		switch args.Type {
+		case API_STATE_PROOF_ACCOUNT:
+			asset := args.Asset
+			if len(asset) == 0 {
+				asset = config_coins.NATIVE_ASSET_FULL
+			}
+			var accs *accounts.Accounts
+			if accs, err = dataStorage.AccsCollection.GetMap(asset); err != nil {
+				return
+			}
+			reply.Account, err = loadStateProof(accs.HashMap, string(key), reply)
+		case API_STATE_PROOF_PLAIN_ACCOUNT:
+			reply.PlainAccount, err = loadStateProof(dataStorage.PlainAccs.HashMap, string(key), reply)
+		case API_STATE_PROOF_REGISTRATION:
+			reply.Registration, err = loadStateProof(dataStorage.Regs.HashMap, string(key), reply)
+		case API_STATE_PROOF_ASSET:
+			reply.Asset, err = loadStateProof(dataStorage.Asts.HashMap, string(key), reply)
+		default:
+			return errors.New("Invalid state proof type")
+		}
+		if err != nil {
+			return
+		}
+
+		chainHeight, _ := binary.Uvarint(reader.Get("chainHeight"))
+		if chainHeight == 0 {
+			return errors.New("Chain has no blocks")
+		}
+
+		reply.Height = chainHeight - 1
+		reply.StateRoot = dataStorage.StateTree.Root()
+
+		return
+	})
+}
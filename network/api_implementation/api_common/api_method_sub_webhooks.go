@@ -0,0 +1,74 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/websocks"
+)
+
+type APISubWebhooksReply struct {
+	Webhooks []*websocks.Webhook `json:"webhooks" msgpack:"webhooks"`
+}
+
+type APISubWebhookAddRequest struct {
+	URL        string                          `json:"url" msgpack:"url"`
+	Secret     string                          `json:"secret" msgpack:"secret"`
+	Type       api_code_types.SubscriptionType `json:"type,omitempty" msgpack:"type,omitempty"`
+	Key        helpers.Base64                  `json:"key" msgpack:"key"`
+	ReturnType api_code_types.APIReturnType    `json:"returnType,omitempty" msgpack:"returnType,omitempty"`
+}
+
+type APISubWebhookAddReply struct {
+	ID string `json:"id" msgpack:"id"`
+}
+
+type APISubWebhookRemoveRequest struct {
+	ID string `json:"id" msgpack:"id"`
+}
+
+type APISubWebhookRemoveReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (api *APICommon) GetSubWebhooks(r *http.Request, args *struct{}, reply *APISubWebhooksReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+	if websocks.Webhooks == nil {
+		return errors.New("Webhooks are not available")
+	}
+
+	reply.Webhooks = websocks.Webhooks.GetWebhooks()
+	return nil
+}
+
+func (api *APICommon) SubWebhookAdd(r *http.Request, args *APISubWebhookAddRequest, reply *APISubWebhookAddReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+	if websocks.Webhooks == nil {
+		return errors.New("Webhooks are not available")
+	}
+
+	webhook, err := websocks.Webhooks.AddWebhook(args.URL, args.Secret, args.Type, args.Key, args.ReturnType)
+	if err != nil {
+		return err
+	}
+
+	reply.ID = webhook.ID
+	return nil
+}
+
+func (api *APICommon) SubWebhookRemove(r *http.Request, args *APISubWebhookRemoveRequest, reply *APISubWebhookRemoveReply, authenticated bool) (err error) {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+	if websocks.Webhooks == nil {
+		return errors.New("Webhooks are not available")
+	}
+
+	reply.Result, err = websocks.Webhooks.RemoveWebhook(args.ID)
+	return
+}
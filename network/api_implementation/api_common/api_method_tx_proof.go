@@ -0,0 +1,89 @@
+package api_common
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"pandora-pay/config"
+	"pandora-pay/cryptography/merkle_tree"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+)
+
+type APITxProofRequest struct {
+	Hash helpers.Base64 `json:"hash" msgpack:"hash"`
+}
+
+type APITxProofReply struct {
+	Hash          []byte   `json:"hash" msgpack:"hash"`
+	BlockHeight   uint64   `json:"blockHeight" msgpack:"blockHeight"`
+	BlockHash     []byte   `json:"blockHash" msgpack:"blockHash"`
+	MerkleHash    []byte   `json:"merkleHash" msgpack:"merkleHash"`
+	Index         uint64   `json:"index" msgpack:"index"` //position of the tx in the block
+	Count         uint64   `json:"count" msgpack:"count"` //number of txs in the block, required to verify the proof
+	Proof         [][]byte `json:"proof" msgpack:"proof"` //siblings from the tx hash up to the MerkleHash
+	Confirmations uint64   `json:"confirmations" msgpack:"confirmations"`
+}
+
+func (api *APICommon) GetTxProof(r *http.Request, args *APITxProofRequest, reply *APITxProofReply) error {
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		hashStr := string(args.Hash)
+
+		data := reader.Get("txBlock:" + hashStr)
+		if data == nil {
+			return errors.New("Tx was not found in a block")
+		}
+		reply.BlockHeight, _ = binary.Uvarint(data)
+
+		if reply.BlockHash, err = api.ApiStore.chain.LoadBlockHash(reader, reply.BlockHeight); err != nil {
+			return
+		}
+
+		if reply.BlockHeight < config.FORK_HEIGHT_MERKLE_TREE {
+			return errors.New("Tx proofs are not supported for the blocks before the merkle tree fork")
+		}
+
+		blk, err := api.ApiStore.loadBlock(reader, reply.BlockHash)
+		if err != nil {
+			return
+		}
+
+		var txHashes [][]byte
+		if err = msgpack.Unmarshal(reader.Get("blockTxs"+strconv.FormatUint(reply.BlockHeight, 10)), &txHashes); err != nil {
+			return
+		}
+
+		index := -1
+		for i, txHash := range txHashes {
+			if bytes.Equal(txHash, args.Hash) {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return errors.New("Tx was not found in the block")
+		}
+
+		if reply.Proof, err = merkle_tree.MerkleProof(txHashes, index); err != nil {
+			return
+		}
+		if !merkle_tree.VerifyMerkleProof(blk.MerkleHash, args.Hash, uint64(index), uint64(len(txHashes)), reply.Proof) {
+			return errors.New("Tx proof is not matching the block MerkleHash")
+		}
+
+		chainHeight, _ := binary.Uvarint(reader.Get("chainHeight"))
+
+		reply.Hash = args.Hash
+		reply.MerkleHash = blk.MerkleHash
+		reply.Index = uint64(index)
+		reply.Count = uint64(len(txHashes))
+		reply.Confirmations = chainHeight - reply.BlockHeight
+
+		return
+	})
+}
@@ -16,8 +16,9 @@ type APISubscriptionNotificationAccountTxExtraBlockchain struct {
 }
 
 type APISubscriptionNotificationAccountTxExtraMempool struct {
-	Inserted bool `json:"inserted,omitempty" msgpack:"inserted,omitempty"`
-	Included bool `json:"included,omitempty" msgpack:"included,omitempty"`
+	Inserted bool   `json:"inserted,omitempty" msgpack:"inserted,omitempty"`
+	Included bool   `json:"included,omitempty" msgpack:"included,omitempty"`
+	Evicted  string `json:"evicted,omitempty" msgpack:"evicted,omitempty"` //reason
 }
 
 type APISubscriptionNotificationAccountExtra struct {
@@ -43,11 +44,42 @@ type APISubscriptionNotificationAccountTxExtra struct {
 }
 
 type APISubscriptionNotificationTxExtraMempool struct {
-	Inserted bool `json:"inserted,omitempty" msgpack:"inserted,omitempty"`
-	Included bool `json:"included,omitempty" msgpack:"included,omitempty"`
+	Inserted bool   `json:"inserted,omitempty" msgpack:"inserted,omitempty"`
+	Included bool   `json:"included,omitempty" msgpack:"included,omitempty"`
+	Evicted  string `json:"evicted,omitempty" msgpack:"evicted,omitempty"` //reason
 }
 
 type APISubscriptionNotificationTxExtra struct {
 	Blockchain *APISubscriptionNotificationTxExtraBlockchain `json:"blockchain,omitempty" msgpack:"blockchain,omitempty"`
 	Mempool    *APISubscriptionNotificationTxExtraMempool    `json:"mempool,omitempty" msgpack:"mempool,omitempty"`
 }
+
+type APISubscriptionNotificationChainExtra struct {
+	Height                uint64   `json:"height" msgpack:"height"`
+	Hash                  []byte   `json:"hash" msgpack:"hash"`
+	PrevHash              []byte   `json:"prevHash" msgpack:"prevHash"`
+	KernelHash            []byte   `json:"kernelHash" msgpack:"kernelHash"`
+	Timestamp             uint64   `json:"timestamp" msgpack:"timestamp"`
+	TransactionsCount     uint64   `json:"transactionsCount" msgpack:"transactionsCount"`
+	RemovedBlocksHeights  []uint64 `json:"removedBlocksHeights,omitempty" msgpack:"removedBlocksHeights,omitempty"` //a reorg happened if not empty
+	InsertedBlocksHeights []uint64 `json:"insertedBlocksHeights" msgpack:"insertedBlocksHeights"`
+	InsertedBlocksHashes  [][]byte `json:"insertedBlocksHashes" msgpack:"insertedBlocksHashes"`
+}
+
+type APISubscriptionNotificationReorgAlertExtra struct {
+	ChainHeight uint64 `json:"chainHeight" msgpack:"chainHeight"`
+	ForkHeight  uint64 `json:"forkHeight" msgpack:"forkHeight"`
+	Depth       uint64 `json:"depth" msgpack:"depth"`
+	Reason      string `json:"reason" msgpack:"reason"`
+}
+
+type APISubscriptionNotificationConditionalPaymentExtra struct {
+	PayloadIndex byte   `json:"payloadIndex" msgpack:"payloadIndex"`
+	BlockHeight  uint64 `json:"blockHeight" msgpack:"blockHeight"`
+	Deleted      bool   `json:"deleted,omitempty" msgpack:"deleted,omitempty"`
+}
+
+type APISubscriptionNotificationPendingStakeExtra struct {
+	BlockHeight uint64 `json:"blockHeight" msgpack:"blockHeight"`
+	Processed   bool   `json:"processed,omitempty" msgpack:"processed,omitempty"`
+}
@@ -3,6 +3,9 @@ package api_websockets
 import (
 	"pandora-pay/blockchain"
 	"pandora-pay/blockchain/blockchain_sync"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/blockchain/data_storage/conditional_payments_list/conditional_payment"
+	"pandora-pay/blockchain/data_storage/registrations/registration"
 	"pandora-pay/blockchain/info"
 	"pandora-pay/config"
 	"pandora-pay/mempool"
@@ -12,6 +15,7 @@ import (
 	"pandora-pay/network/api_implementation/api_common/api_faucet"
 	"pandora-pay/network/api_implementation/api_websockets/consensus"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/settings"
 )
@@ -41,41 +45,58 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 	}
 
 	api.GetMap = map[string]func(conn *connection.AdvancedConnection, values []byte) (interface{}, error){
-		"ping":                    api_code_websockets.Handle[struct{}, api_common.APIPingReply](api.apiCommon.GetPing),
-		"":                        api_code_websockets.Handle[struct{}, api_common.APIInfoReply](api.apiCommon.GetInfo),
-		"chain":                   api_code_websockets.Handle[struct{}, api_common.APIBlockchain](api.apiCommon.GetBlockchain),
-		"blockchain":              api_code_websockets.Handle[struct{}, api_common.APIBlockchain](api.apiCommon.GetBlockchain),
-		"blockchain/staking-info": api_code_websockets.Handle[api_common.APIStakingInfoRequest, api_common.APIStakingInfoReply](api.apiCommon.GetStakingInfo),
-		"blockchain/genesis-info": api_code_websockets.Handle[api_common.APIGenesisInfoRequest, api_common.APIGenesisInfoReply](api.apiCommon.GetGenesisInfo),
-		"blockchain/supply":       api_code_websockets.Handle[struct{}, api_common.APISupply](api.apiCommon.GetSupply),
-		"blockchain/supply-only":  api_code_websockets.Handle[struct{}, uint64](api.apiCommon.GetSupplyOnly),
-		"sync":                    api_code_websockets.Handle[struct{}, blockchain_sync.BlockchainSyncData](api.apiCommon.GetBlockchainSync),
-		"block-hash":              api_code_websockets.Handle[api_common.APIBlockHashRequest, api_common.APIBlockHashReply](api.apiCommon.GetBlockHash),
-		"block":                   api_code_websockets.Handle[api_common.APIBlockRequest, api_common.APIBlockReply](api.apiCommon.GetBlock),
-		"block/exists":            api_code_websockets.Handle[api_common.APIBlockExistsRequest, api_common.APIBlockExistsReply](api.apiCommon.GetBlockExists),
-		"block-complete":          api_code_websockets.Handle[api_common.APIBlockCompleteRequest, api_common.APIBlockCompleteReply](api.apiCommon.GetBlockComplete),
-		"tx-hash":                 api_code_websockets.Handle[api_common.APITxHashRequest, api_common.APITxHashReply](api.apiCommon.GetTxHash),
-		"tx":                      api_code_websockets.Handle[api_common.APITxRequest, api_common.APITxReply](api.apiCommon.GetTx),
-		"tx/exists":               api_code_websockets.Handle[api_common.APITxExistsRequest, api_common.APITxExistsReply](api.apiCommon.GetTxExists),
-		"tx-raw":                  api_code_websockets.Handle[api_common.APITxRawRequest, api_common.APITxRawReply](api.apiCommon.GetTxRaw),
-		"account":                 api_code_websockets.Handle[api_common.APIAccountRequest, api_common.APIAccountReply](api.apiCommon.GetAccount),
-		"accounts/count":          api_code_websockets.Handle[api_common.APIAccountsCountRequest, api_common.APIAccountsCountReply](api.apiCommon.GetAccountsCount),
-		"accounts/keys-by-index":  api_code_websockets.Handle[api_common.APIAccountsKeysByIndexRequest, api_common.APIAccountsKeysByIndexReply](api.apiCommon.GetAccountsKeysByIndex),
-		"accounts/by-keys":        api_code_websockets.Handle[api_common.APIAccountsByKeysRequest, api_common.APIAccountsByKeysReply](api.apiCommon.GetAccountsByKeys),
-		"asset":                   api_code_websockets.Handle[api_common.APIAssetRequest, api_common.APIAssetReply](api.apiCommon.GetAsset),
-		"asset/exists":            api_code_websockets.Handle[api_common.APIAssetRequest, api_common.APIAssetReply](api.apiCommon.GetAsset),
-		"asset/fee-liquidity":     api_code_websockets.Handle[api_common.APIAssetFeeLiquidityFeeRequest, api_common.APIAssetFeeLiquidityFeeReply](api.apiCommon.GetAssetFeeLiquidity),
-		"mempool":                 api_code_websockets.Handle[api_common.APIMempoolRequest, api_common.APIMempoolReply](api.apiCommon.GetMempool),
-		"mempool/tx-exists":       api_code_websockets.Handle[api_common.APIMempoolExistsRequest, api_common.APIMempoolExistsReply](api.apiCommon.GetMempoolExists),
-		"mempool/new-tx":          api_code_websockets.Handle[api_common.APIMempoolNewTxRequest, api_common.APIMempoolNewTxReply](api.apiCommon.MempoolNewTx),
-		"network/nodes":           api_code_websockets.Handle[struct{}, api_common.APINetworkNodesReply](api.apiCommon.GetNetworkNodes),
-		"wallet/get-addresses":    api_code_websockets.HandleAuthenticated[struct{}, api_common.APIWalletGetAccountsReply](api.apiCommon.GetWalletAddresses),
-		"wallet/generate-address": api_code_websockets.HandleAuthenticated[api_common.APIWalletGenerateAddressRequest, api_common.APIWalletGenerateAddressReply](api.apiCommon.GetWalletGenerateAddress),
-		"wallet/create-address":   api_code_websockets.HandleAuthenticated[api_common.APIWalletCreateAddressRequest, api_common.APIWalletCreateAddressReply](api.apiCommon.GetWalletCreateAddress),
-		"wallet/delete-address":   api_code_websockets.HandleAuthenticated[api_common.APIWalletDeleteAddressRequest, api_common.APIWalletDeleteAddressReply](api.apiCommon.GetWalletDeleteAddress),
-		"wallet/get-balances":     api_code_websockets.HandleAuthenticated[api_common.APIWalletGetBalanceRequest, api_common.APIWalletGetBalancesReply](api.apiCommon.GetWalletBalances),
-		"wallet/decrypt-tx":       api_code_websockets.HandleAuthenticated[api_common.APIWalletDecryptTxRequest, api_common.APIWalletDecryptTxReply](api.apiCommon.GetWalletDecryptTx),
-		"wallet/private-transfer": api_code_websockets.HandleAuthenticated[api_common.APIWalletPrivateTransferRequest, api_common.APIWalletPrivateTransferReply](api.apiCommon.WalletPrivateTransfer),
+		"ping":                       api_code_websockets.Handle[struct{}, api_common.APIPingReply](api.apiCommon.GetPing),
+		"":                           api_code_websockets.Handle[struct{}, api_common.APIInfoReply](api.apiCommon.GetInfo),
+		"chain":                      api_code_websockets.Handle[struct{}, api_common.APIBlockchain](api.apiCommon.GetBlockchain),
+		"blockchain":                 api_code_websockets.Handle[struct{}, api_common.APIBlockchain](api.apiCommon.GetBlockchain),
+		"blockchain/staking-info":    api_code_websockets.Handle[api_common.APIStakingInfoRequest, api_common.APIStakingInfoReply](api.apiCommon.GetStakingInfo),
+		"blockchain/genesis-info":    api_code_websockets.Handle[api_common.APIGenesisInfoRequest, api_common.APIGenesisInfoReply](api.apiCommon.GetGenesisInfo),
+		"blockchain/supply":          api_code_websockets.Handle[struct{}, api_common.APISupply](api.apiCommon.GetSupply),
+		"blockchain/supply-only":     api_code_websockets.Handle[struct{}, uint64](api.apiCommon.GetSupplyOnly),
+		"sync":                       api_code_websockets.Handle[struct{}, blockchain_sync.BlockchainSyncData](api.apiCommon.GetBlockchainSync),
+		"block-hash":                 api_code_websockets.Handle[api_common.APIBlockHashRequest, api_common.APIBlockHashReply](api.apiCommon.GetBlockHash),
+		"block-headers":              api_code_websockets.Handle[api_common.APIBlockHeadersRequest, api_common.APIBlockHeadersReply](api.apiCommon.GetBlockHeaders),
+		"block":                      api_code_websockets.Handle[api_common.APIBlockRequest, api_common.APIBlockReply](api.apiCommon.GetBlock),
+		"block/exists":               api_code_websockets.Handle[api_common.APIBlockExistsRequest, api_common.APIBlockExistsReply](api.apiCommon.GetBlockExists),
+		"block-complete":             api_code_websockets.Handle[api_common.APIBlockCompleteRequest, api_common.APIBlockCompleteReply](api.apiCommon.GetBlockComplete),
+		"tx-hash":                    api_code_websockets.Handle[api_common.APITxHashRequest, api_common.APITxHashReply](api.apiCommon.GetTxHash),
+		"tx":                         api_code_websockets.Handle[api_common.APITxRequest, api_common.APITxReply](api.apiCommon.GetTx),
+		"tx-proof":                   api_code_websockets.Handle[api_common.APITxProofRequest, api_common.APITxProofReply](api.apiCommon.GetTxProof),
+		"tx/exists":                  api_code_websockets.Handle[api_common.APITxExistsRequest, api_common.APITxExistsReply](api.apiCommon.GetTxExists),
+		"tx-raw":                     api_code_websockets.Handle[api_common.APITxRawRequest, api_common.APITxRawReply](api.apiCommon.GetTxRaw),
+		"account":                    api_code_websockets.Handle[api_common.APIAccountRequest, api_common.APIAccountReply](api.apiCommon.GetAccount),
+		"accounts/count":             api_code_websockets.Handle[api_common.APIAccountsCountRequest, api_common.APIAccountsCountReply](api.apiCommon.GetAccountsCount),
+		"accounts/keys-by-index":     api_code_websockets.Handle[api_common.APIAccountsKeysByIndexRequest, api_common.APIAccountsKeysByIndexReply](api.apiCommon.GetAccountsKeysByIndex),
+		"accounts/by-keys":           api_code_websockets.Handle[api_common.APIAccountsByKeysRequest, api_common.APIAccountsByKeysReply](api.apiCommon.GetAccountsByKeys),
+		"asset":                      api_code_websockets.Handle[api_common.APIAssetRequest, api_common.APIAssetReply](api.apiCommon.GetAsset),
+		"asset/exists":               api_code_websockets.Handle[api_common.APIAssetRequest, api_common.APIAssetReply](api.apiCommon.GetAsset),
+		"asset/fee-liquidity":        api_code_websockets.Handle[api_common.APIAssetFeeLiquidityFeeRequest, api_common.APIAssetFeeLiquidityFeeReply](api.apiCommon.GetAssetFeeLiquidity),
+		"assets/list":                api_code_websockets.Handle[api_common.APIListRequest, api_common.APIListReply[*asset.Asset]](api.apiCommon.GetAssetsList),
+		"registrations/list":         api_code_websockets.Handle[api_common.APIListRequest, api_common.APIListReply[*registration.Registration]](api.apiCommon.GetRegistrationsList),
+		"conditional-payments/list":  api_code_websockets.Handle[api_common.APIConditionalPaymentsListRequest, api_common.APIListReply[*conditional_payment.ConditionalPayment]](api.apiCommon.GetConditionalPaymentsList),
+		"state-proof":                api_code_websockets.Handle[api_common.APIStateProofRequest, api_common.APIStateProofReply](api.apiCommon.GetStateProof),
+		"mempool":                    api_code_websockets.Handle[api_common.APIMempoolRequest, api_common.APIMempoolReply](api.apiCommon.GetMempool),
+		"mempool/tx-exists":          api_code_websockets.Handle[api_common.APIMempoolExistsRequest, api_common.APIMempoolExistsReply](api.apiCommon.GetMempoolExists),
+		"mempool/new-tx":             api_code_websockets.Handle[api_common.APIMempoolNewTxRequest, api_common.APIMempoolNewTxReply](api.apiCommon.MempoolNewTx),
+		"mempool/remove-tx":          api_code_websockets.HandleAuthenticated[api_common.APIMempoolRemoveTxRequest, api_common.APIMempoolRemoveTxReply](network_config_auth.ROLE_ADMIN, api.apiCommon.MempoolRemoveTx),
+		"fee-estimate":               api_code_websockets.Handle[api_common.APIFeeEstimateRequest, api_common.APIFeeEstimateReply](api.apiCommon.FeeEstimate),
+		"network/nodes":              api_code_websockets.Handle[struct{}, api_common.APINetworkNodesReply](api.apiCommon.GetNetworkNodes),
+		"network/bans":               api_code_websockets.HandleAuthenticated[struct{}, api_common.APINetworkBansReply](network_config_auth.ROLE_ADMIN, api.apiCommon.GetNetworkBans),
+		"network/bans/add":           api_code_websockets.HandleAuthenticated[api_common.APINetworkBanAddRequest, api_common.APINetworkBanAddReply](network_config_auth.ROLE_ADMIN, api.apiCommon.NetworkBanAdd),
+		"network/bans/remove":        api_code_websockets.HandleAuthenticated[api_common.APINetworkBanRemoveRequest, api_common.APINetworkBanRemoveReply](network_config_auth.ROLE_ADMIN, api.apiCommon.NetworkBanRemove),
+		"network/known-nodes":        api_code_websockets.HandleAuthenticated[struct{}, api_common.APINetworkKnownNodesReply](network_config_auth.ROLE_ADMIN, api.apiCommon.GetNetworkKnownNodes),
+		"network/known-nodes/add":    api_code_websockets.HandleAuthenticated[api_common.APINetworkKnownNodeAddRequest, api_common.APINetworkKnownNodeAddReply](network_config_auth.ROLE_ADMIN, api.apiCommon.NetworkKnownNodeAdd),
+		"network/known-nodes/remove": api_code_websockets.HandleAuthenticated[api_common.APINetworkKnownNodeRemoveRequest, api_common.APINetworkKnownNodeRemoveReply](network_config_auth.ROLE_ADMIN, api.apiCommon.NetworkKnownNodeRemove),
+		"sub/webhooks":               api_code_websockets.HandleAuthenticated[struct{}, api_common.APISubWebhooksReply](network_config_auth.ROLE_ADMIN, api.apiCommon.GetSubWebhooks),
+		"sub/webhook/add":            api_code_websockets.HandleAuthenticated[api_common.APISubWebhookAddRequest, api_common.APISubWebhookAddReply](network_config_auth.ROLE_ADMIN, api.apiCommon.SubWebhookAdd),
+		"sub/webhook/remove":         api_code_websockets.HandleAuthenticated[api_common.APISubWebhookRemoveRequest, api_common.APISubWebhookRemoveReply](network_config_auth.ROLE_ADMIN, api.apiCommon.SubWebhookRemove),
+		"wallet/get-addresses":       api_code_websockets.HandleAuthenticated[struct{}, api_common.APIWalletGetAccountsReply](network_config_auth.ROLE_WALLET_READ, api.apiCommon.GetWalletAddresses),
+		"wallet/generate-address":    api_code_websockets.HandleAuthenticated[api_common.APIWalletGenerateAddressRequest, api_common.APIWalletGenerateAddressReply](network_config_auth.ROLE_WALLET_SPEND, api.apiCommon.GetWalletGenerateAddress),
+		"wallet/create-address":      api_code_websockets.HandleAuthenticated[api_common.APIWalletCreateAddressRequest, api_common.APIWalletCreateAddressReply](network_config_auth.ROLE_WALLET_SPEND, api.apiCommon.GetWalletCreateAddress),
+		"wallet/delete-address":      api_code_websockets.HandleAuthenticated[api_common.APIWalletDeleteAddressRequest, api_common.APIWalletDeleteAddressReply](network_config_auth.ROLE_WALLET_SPEND, api.apiCommon.GetWalletDeleteAddress),
+		"wallet/get-balances":        api_code_websockets.HandleAuthenticated[api_common.APIWalletGetBalanceRequest, api_common.APIWalletGetBalancesReply](network_config_auth.ROLE_WALLET_READ, api.apiCommon.GetWalletBalances),
+		"wallet/decrypt-tx":          api_code_websockets.HandleAuthenticated[api_common.APIWalletDecryptTxRequest, api_common.APIWalletDecryptTxReply](network_config_auth.ROLE_WALLET_READ, api.apiCommon.GetWalletDecryptTx),
+		"wallet/private-transfer":    api_code_websockets.HandleAuthenticated[api_common.APIWalletPrivateTransferRequest, api_common.APIWalletPrivateTransferReply](network_config_auth.ROLE_WALLET_SPEND, api.apiCommon.WalletPrivateTransfer),
 		//below are ONLY websockets API
 		"block-miss-txs":    api_code_websockets.Handle[consensus.APIBlockCompleteMissingTxsRequest, consensus.APIBlockCompleteMissingTxsReply](api.Consensus.GetBlockCompleteMissingTxs),
 		"handshake":         api_code_websockets.Handshake,
@@ -111,7 +132,7 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 
 	if api.apiCommon.DelegatorNode != nil {
 		api.GetMap["delegator-node/info"] = api_code_websockets.Handle[struct{}, api_delegator_node.ApiDelegatorNodeInfoReply](api.apiCommon.DelegatorNode.GetDelegatorNodeInfo)
-		api.GetMap["delegator-node/notify"] = api_code_websockets.HandleAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply](api.apiCommon.DelegatorNode.DelegatorNotify)
+		api.GetMap["delegator-node/notify"] = api_code_websockets.HandleAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply](network_config_auth.ROLE_DELEGATOR, api.apiCommon.DelegatorNode.DelegatorNotify)
 	}
 
 	if ConfigureAPIRoutes != nil {
@@ -4,51 +4,94 @@ import (
 	"bytes"
 	"errors"
 	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/blockchain_sync"
 	"pandora-pay/blockchain/blocks/block"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/config"
 	"pandora-pay/config/globals"
-	"pandora-pay/cryptography"
 	"pandora-pay/gui"
+	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/recovery"
 	"pandora-pay/mempool"
 	"pandora-pay/network/api_code/api_code_types"
 	"pandora-pay/network/api_implementation/api_common"
+	"pandora-pay/network/connected_nodes"
+	"pandora-pay/network/network_config"
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/txs_validator"
+	"sync"
 	"time"
 )
 
+var errForkTooDeep = errors.New("Fork is too deep")
+var errInvalidHeadersChain = errors.New("Invalid headers chain")
+
 type ConsensusProcessForksThread struct {
 	chain   *blockchain.Blockchain
 	forks   *Forks
 	mempool *mempool.Mempool
 }
 
-func (thread *ConsensusProcessForksThread) downloadBlockHash(conn *connection.AdvancedConnection, fork *Fork, height uint64) ([]byte, error) {
-	answer, err := connection.SendJSONAwaitAnswer[api_common.APIBlockHashReply](conn, []byte("block-hash"), &api_common.APIBlockHashRequest{height}, nil, 0)
+func (thread *ConsensusProcessForksThread) downloadBlockHeaders(conn *connection.AdvancedConnection, start, count uint64) ([]*block.Block, error) {
+
+	answer, err := connection.SendJSONAwaitAnswer[api_common.APIBlockHeadersReply](conn, []byte("block-headers"), &api_common.APIBlockHeadersRequest{start, count}, nil, 0)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(answer.Hash) != cryptography.HashSize {
-		return nil, errors.New("Hash size is invalid")
+	if len(answer.Headers) == 0 || uint64(len(answer.Headers)) > count {
+		conn.Penalize(network_config.NETWORK_PENALTY_MALFORMED_MESSAGE, "Invalid headers")
+		return nil, errors.New("Invalid number of headers")
 	}
 
-	return answer.Hash, nil
+	headers := make([]*block.Block, len(answer.Headers))
+	for i := range answer.Headers {
+		blk := block.CreateEmptyBlock()
+		if err = blk.Deserialize(advanced_buffers.NewBufferReader(answer.Headers[i])); err == nil {
+			err = blk.BloomNow()
+		}
+		if err != nil || blk.Height != start+uint64(i) {
+			conn.Penalize(network_config.NETWORK_PENALTY_INVALID_BLOCK, "Invalid header")
+			return nil, helpers.ReturnErrorIfNot(err, "Header height is invalid")
+		}
+		headers[i] = blk
+	}
+
+	return headers, nil
+}
+
+//the headers must be linked by their hashes and kernel hashes
+func validateHeadersChain(prev *block.Block, headers []*block.Block) error {
+	for _, header := range headers {
+		if header.Height != prev.Height+1 {
+			return errors.New("Header height is not consecutive")
+		}
+		if !bytes.Equal(header.PrevHash, prev.Bloom.Hash) {
+			return errors.New("Header PrevHash is not matching")
+		}
+		if !bytes.Equal(header.PrevKernelHash, prev.Bloom.KernelHash) {
+			return errors.New("Header PrevKernelHash is not matching")
+		}
+		prev = header
+	}
+	return nil
 }
 
-func (thread *ConsensusProcessForksThread) downloadBlockComplete(conn *connection.AdvancedConnection, fork *Fork, height uint64) (*block_complete.BlockComplete, error) {
+// downloadBlockComplete downloads the block of a validated header. The block is requested by hash, so any peer having it can answer
+func (thread *ConsensusProcessForksThread) downloadBlockComplete(conn *connection.AdvancedConnection, header *block.Block) (*block_complete.BlockComplete, error) {
 
-	blkWithTx, err := connection.SendJSONAwaitAnswer[api_common.APIBlockReply](conn, []byte("block"), &api_common.APIBlockRequest{height, nil, api_code_types.RETURN_SERIALIZED}, nil, 0)
+	blkWithTx, err := connection.SendJSONAwaitAnswer[api_common.APIBlockReply](conn, []byte("block"), &api_common.APIBlockRequest{header.Height, header.Bloom.Hash, api_code_types.RETURN_SERIALIZED}, nil, 0)
 	if err != nil {
 		return nil, err
 	}
 
 	blkWithTx.Block = block.CreateEmptyBlock()
 	if err = blkWithTx.Block.Deserialize(advanced_buffers.NewBufferReader(blkWithTx.BlockSerialized)); err != nil {
+		conn.Penalize(network_config.NETWORK_PENALTY_INVALID_BLOCK, "Invalid block")
 		return nil, err
 	}
 
@@ -88,11 +131,13 @@ func (thread *ConsensusProcessForksThread) downloadBlockComplete(conn *connectio
 		}
 
 		if len(blkCompleteMissingTxs.Txs) != len(missingTxs) {
+			conn.Penalize(network_config.NETWORK_PENALTY_MALFORMED_MESSAGE, "Invalid missing txs")
 			return nil, errors.New("blkCompleteMissingTxs.Txs length is not matching")
 		}
 
 		for _, missingTx := range blkCompleteMissingTxs.Txs {
 			if missingTx == nil {
+				conn.Penalize(network_config.NETWORK_PENALTY_MALFORMED_MESSAGE, "Invalid missing txs")
 				return nil, errors.New("blkCompleteMissingTxs.Tx is null")
 			}
 		}
@@ -100,6 +145,7 @@ func (thread *ConsensusProcessForksThread) downloadBlockComplete(conn *connectio
 		for i, missingTx := range missingTxs {
 			tx := &transaction.Transaction{}
 			if err = tx.Deserialize(advanced_buffers.NewBufferReader(blkCompleteMissingTxs.Txs[i])); err != nil {
+				conn.Penalize(network_config.NETWORK_PENALTY_INVALID_TX, "Invalid tx")
 				return nil, err
 			}
 			txs[missingTx] = tx
@@ -109,16 +155,106 @@ func (thread *ConsensusProcessForksThread) downloadBlockComplete(conn *connectio
 	blkComplete.Txs = txs
 
 	if err = txs_validator.TxsValidator.ValidateTxs(txs); err != nil {
+		conn.Penalize(network_config.NETWORK_PENALTY_INVALID_TX, "Invalid tx")
 		return nil, err
 	}
 
 	if err = blkComplete.BloomAll(); err != nil {
+		conn.Penalize(network_config.NETWORK_PENALTY_INVALID_BLOCK, "Invalid block")
 		return nil, err
 	}
 
+	if !bytes.Equal(blkComplete.Bloom.Hash, header.Bloom.Hash) {
+		conn.Penalize(network_config.NETWORK_PENALTY_INVALID_BLOCK, "Block hash is not matching")
+		return nil, errors.New("Block hash is not matching the header")
+	}
+
 	return blkComplete, nil
 }
 
+// isBlockNotFoundError is true when the peer answered that it doesn't have the block, for instance after it switched to another chain
+func isBlockNotFoundError(err error) bool {
+	switch err.Error() {
+	case "Block was not found", "Block was not found by hash", "Block not found":
+		return true
+	}
+	return false
+}
+
+//the headers are downloaded backwards from a single peer until a header matches our chain. loadHash returns the hash of our block at a height
+//most forks are a few blocks deep, so the first batch is small and it is doubled for each request
+//errForkTooDeep is returned together with the height where the search stopped
+func downloadForkHeaders(fork *Fork, chainData *blockchain.BlockchainData, download func(start, count uint64) ([]*block.Block, error), loadHash func(height uint64) ([]byte, error)) (uint64, *block.Block, []*block.Block, error) {
+
+	start := fork.End
+	if start > chainData.Height {
+		start = chainData.Height
+	}
+
+	//deeper forks are rejected by the maximum reorg depth. Without it, the search stops after the uncles allowed
+	maxDepth := config.FORK_MAX_REORG_DEPTH
+	if maxDepth == 0 {
+		maxDepth = config.FORK_MAX_UNCLE_ALLOWED + chainData.ConsecutiveSelfForged
+	}
+
+	tooDeep := func(start uint64) bool {
+		return chainData.Height-start > maxDepth
+	}
+
+	var ancestor *block.Block
+	headers := []*block.Block{}
+	batchSize := config.FORK_FIRST_HEADERS
+
+	for start > 0 {
+
+		if tooDeep(start) {
+			return start, nil, nil, errForkTooDeep
+		}
+
+		count := generics.Min(batchSize, start)
+		batchSize = generics.Min(batchSize*2, config.FORK_MAX_HEADERS)
+		batch, err := download(start-count, count)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		if uint64(len(batch)) != count {
+			return 0, nil, nil, errors.New("Peer returned fewer headers")
+		}
+
+		i := len(batch) - 1
+		for ; i >= 0; i-- {
+			chainHash, err := loadHash(batch[i].Height)
+			if err == nil && bytes.Equal(batch[i].Bloom.Hash, chainHash) {
+				ancestor = batch[i]
+				break
+			}
+		}
+
+		headers = append(batch[i+1:], headers...)
+		start = start - count + uint64(i+1)
+
+		if ancestor != nil {
+			break
+		}
+	}
+
+	if tooDeep(start) {
+		return start, nil, nil, errForkTooDeep
+	}
+
+	if ancestor != nil {
+		if err := validateHeadersChain(ancestor, headers); err != nil {
+			return 0, nil, nil, errInvalidHeadersChain
+		}
+	} else if len(headers) > 0 {
+		if err := validateHeadersChain(headers[0], headers[1:]); err != nil {
+			return 0, nil, nil, errInvalidHeadersChain
+		}
+	}
+
+	return start, ancestor, headers, nil
+}
+
 func (thread *ConsensusProcessForksThread) downloadFork(fork *Fork) bool {
 
 	fork.Lock()
@@ -133,66 +269,187 @@ func (thread *ConsensusProcessForksThread) downloadFork(fork *Fork) bool {
 		return true
 	}
 
-	start := fork.End
-	if start > chainData.Height {
-		start = chainData.Height
-	}
-
-	for {
+	for fork.errors <= 2 {
 
-		if start == 0 { //let's exit
-			break
+		conn := fork.getRandomConn()
+		if conn == nil {
+			return false
 		}
-		if (chainData.Height-start > config.FORK_MAX_UNCLE_ALLOWED+chainData.ConsecutiveSelfForged) && (chainData.Height-start > chainData.ConsecutiveSelfForged) {
+
+		start, ancestor, headers, err := downloadForkHeaders(fork, chainData, func(start, count uint64) ([]*block.Block, error) {
+			return thread.downloadBlockHeaders(conn, start, count)
+		}, thread.chain.OpenLoadBlockHash)
+		if err == errForkTooDeep {
+			//only the forks violating the maximum reorg depth or a checkpoint are invalid
+			if err = thread.chain.VerifyFork(start, nil); err != nil {
+				conn.Penalize(network_config.NETWORK_PENALTY_INVALID_FORK, err.Error())
+			}
 			return false
 		}
+		if err == errInvalidHeadersChain {
+			conn.Penalize(network_config.NETWORK_PENALTY_INVALID_FORK, err.Error())
+		}
+		if err != nil {
+			fork.errors += 1
+			continue
+		}
 
-		if fork.errors > 2 {
+		//the fork is violating the maximum reorg depth or a checkpoint
+		if err = thread.chain.VerifyFork(start, headers); err != nil {
+			conn.Penalize(network_config.NETWORK_PENALTY_INVALID_FORK, err.Error())
 			return false
 		}
 
-		if fork.errors < -10 {
-			fork.errors = -10
+		fork.Current = start
+		fork.Headers = headers
+		fork.lastHeader = ancestor
+		if len(headers) > 0 {
+			fork.lastHeader = headers[len(headers)-1]
+		}
+		fork.downloadStart = start
+		fork.Initialized = true
+
+		return true
+	}
+
+	return false
+}
+
+//the headers above our chain are downloaded forwards and linked to the last validated header
+func (thread *ConsensusProcessForksThread) downloadRemainingHeaders(fork *Fork) {
+
+	for uint64(len(fork.Headers)) < config.FORK_MAX_DOWNLOAD {
+
+		next := fork.Current + uint64(len(fork.Headers))
+		if next >= fork.End || fork.errors > 2 {
+			return
 		}
 
 		conn := fork.getRandomConn()
 		if conn == nil {
-			return false
+			return
 		}
 
-		hash, err := thread.downloadBlockHash(conn, fork, start-1)
+		headers, err := thread.downloadBlockHeaders(conn, next, generics.Min(config.FORK_MAX_HEADERS, fork.End-next))
 		if err != nil {
 			fork.errors += 1
 			continue
 		}
 
-		chainHash, err := thread.chain.OpenLoadBlockHash(start - 1)
-		if err == nil && bytes.Equal(hash, chainHash) {
-			break
-		}
-
-		blkComplete, err := thread.downloadBlockComplete(conn, fork, start-1)
-		if err != nil {
+		if err = validateHeadersChain(headers[0], headers[1:]); err != nil {
+			conn.Penalize(network_config.NETWORK_PENALTY_INVALID_FORK, "Invalid headers chain")
 			fork.errors += 1
 			continue
 		}
 
-		if !bytes.Equal(blkComplete.Bloom.Hash, hash) { //it is not the same block
+		//the peer can be on a different chain than the previous headers
+		if fork.lastHeader != nil {
+			if err = validateHeadersChain(fork.lastHeader, headers[:1]); err != nil {
+				fork.errors += 1
+				continue
+			}
+		}
+
+		if err = thread.chain.VerifyFork(fork.Current, headers); err != nil {
+			conn.Penalize(network_config.NETWORK_PENALTY_INVALID_FORK, err.Error())
 			fork.errors += 1
 			continue
 		}
 
-		//prepend
-		fork.Blocks.PushFront(blkComplete)
+		fork.Headers = append(fork.Headers, headers...)
+		fork.lastHeader = headers[len(headers)-1]
+	}
+}
 
-		start -= 1
+//the peers which announced the fork are used first. Any other full node can provide the blocks as they are requested by hash
+func (thread *ConsensusProcessForksThread) getDownloadConns(fork *Fork) (forkConns, otherConns []*connection.AdvancedConnection) {
+
+	forkConns = make([]*connection.AdvancedConnection, 0)
+	otherConns = make([]*connection.AdvancedConnection, 0)
+	included := make(map[*connection.AdvancedConnection]bool)
+
+	for _, conn := range fork.conns {
+		if !conn.IsClosed.IsSet() {
+			forkConns = append(forkConns, conn)
+			included[conn] = true
+		}
+	}
+
+	for _, conn := range connected_nodes.ConnectedNodes.AllList.Get() {
+		if !included[conn] && !conn.IsClosed.IsSet() && conn.Handshake != nil && conn.Handshake.Consensus == config.NODE_CONSENSUS_TYPE_FULL {
+			otherConns = append(otherConns, conn)
+			included[conn] = true
+		}
+	}
+
+	return
+}
+
+// downloadBlocksParallel downloads the blocks of the headers using several peers. A failed block is retried with another peer of the fork. The peers which time out are not used anymore.
+// The other peers are asked only for the blocks the peers of the fork answered they don't have, and these answers are not counted as failed attempts
+func downloadBlocksParallel(forkConns, otherConns []*connection.AdvancedConnection, headers []*block.Block, download func(conn *connection.AdvancedConnection, header *block.Block) (*block_complete.BlockComplete, error)) []*block_complete.BlockComplete {
+
+	results := make([]*block_complete.BlockComplete, len(headers))
+
+	jobs := make(chan int, len(headers))
+	for i := range headers {
+		jobs <- i
+	}
+	close(jobs)
+
+	timedOut := &generics.Map[*connection.AdvancedConnection, bool]{}
+
+	getConn := func(conns []*connection.AdvancedConnection, index int, notFound map[*connection.AdvancedConnection]bool) *connection.AdvancedConnection {
+		for i := 0; i < len(conns); i++ {
+			conn := conns[(index+i)%len(conns)]
+			if _, found := timedOut.Load(conn); !found && !notFound[conn] && !conn.IsClosed.IsSet() {
+				return conn
+			}
+		}
+		return nil
 	}
 
-	fork.Current = start + uint64(fork.Blocks.Length)
+	wg := &sync.WaitGroup{}
+	for worker := 0; worker < generics.Min(config.FORK_DOWNLOAD_WORKERS, len(headers)); worker++ {
+		wg.Add(1)
+		recovery.SafeGo(func() {
+			defer wg.Done()
+			for index := range jobs {
 
-	fork.Initialized = true
+				notFound := make(map[*connection.AdvancedConnection]bool)
 
-	return true
+				for attempt := 0; attempt < config.FORK_DOWNLOAD_RETRIES; {
+
+					conn := getConn(forkConns, index+attempt, notFound)
+					if conn == nil && len(notFound) > 0 {
+						conn = getConn(otherConns, index+attempt, notFound)
+					}
+					if conn == nil {
+						break
+					}
+
+					blkComplete, err := download(conn, headers[index])
+					if err == nil {
+						results[index] = blkComplete
+						break
+					}
+
+					if isBlockNotFoundError(err) {
+						notFound[conn] = true
+						continue
+					}
+
+					attempt++
+					if err.Error() == "Timeout" { //the connection already penalized the slow response
+						timedOut.Store(conn, true)
+					}
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	return results
 }
 
 func (thread *ConsensusProcessForksThread) downloadRemainingBlocks(fork *Fork) bool {
@@ -200,37 +457,46 @@ func (thread *ConsensusProcessForksThread) downloadRemainingBlocks(fork *Fork) b
 	fork.Lock()
 	defer fork.Unlock()
 
-	for i := uint64(0); i < config.FORK_MAX_DOWNLOAD; i++ {
+	if fork.errors < -10 {
+		fork.errors = -10
+	}
 
-		if fork.Current == fork.End {
-			break
-		}
+	thread.downloadRemainingHeaders(fork)
 
-		if fork.errors > 2 {
-			return false
-		}
-		if fork.errors < -10 {
-			fork.errors = -10
-		}
+	count := generics.Min(uint64(len(fork.Headers)), config.FORK_MAX_DOWNLOAD)
+	if count == 0 {
+		return fork.Blocks.Length > 0
+	}
 
-		conn := fork.getRandomConn()
-		if conn == nil {
-			return false
-		}
+	forkConns, otherConns := thread.getDownloadConns(fork)
+	if len(forkConns) == 0 {
+		return false
+	}
 
-		blkComplete, err := thread.downloadBlockComplete(conn, fork, fork.Current)
-		if err != nil {
+	blocks := downloadBlocksParallel(forkConns, otherConns, fork.Headers[:count], thread.downloadBlockComplete)
+
+	//only the consecutive blocks can be added
+	downloaded := 0
+	for _, blkComplete := range blocks {
+		if blkComplete == nil {
 			fork.errors += 1
-			continue
+			break
 		}
-
 		fork.Blocks.Push(blkComplete)
-		fork.Current += 1
-
+		downloaded++
 	}
+	fork.Headers = fork.Headers[downloaded:]
+	fork.Current += uint64(downloaded)
 
-	return fork.Blocks.Length > 0
+	thread.chain.Sync.UpdateDownload(&blockchain_sync.BlockchainSyncDownload{
+		fork.downloadStart,
+		fork.End,
+		fork.Current + uint64(len(fork.Headers)),
+		fork.Current,
+		len(forkConns) + len(otherConns),
+	})
 
+	return fork.Blocks.Length > 0
 }
 
 func (thread *ConsensusProcessForksThread) execute() {
@@ -263,6 +529,12 @@ func (thread *ConsensusProcessForksThread) execute() {
 							if config.DEBUG {
 								gui.GUI.Error("Invalid Fork", err)
 							}
+							//only the blocks with an invalid content are penalized. The other errors are not caused by the peers
+							if _, ok := err.(*blockchain.BlockInvalidError); ok {
+								fork.Lock()
+								fork.penalizeConns(network_config.NETWORK_PENALTY_INVALID_FORK, "Invalid fork")
+								fork.Unlock()
+							}
 						} else {
 							fork.Lock()
 							if fork.Current < fork.End {
@@ -293,6 +565,7 @@ func (thread *ConsensusProcessForksThread) execute() {
 
 			if willRemove {
 				thread.forks.removeFork(fork)
+				thread.chain.Sync.UpdateDownload(nil)
 			}
 
 		}
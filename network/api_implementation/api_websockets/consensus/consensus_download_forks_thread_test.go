@@ -0,0 +1,214 @@
+package consensus
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/tevino/abool"
+	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/config"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/network/websocks/connection"
+	"sync"
+	"testing"
+)
+
+//the headers are linked to prev. A nil prev starts a new chain from the height 0
+func createTestHeaders(prev *block.Block, count int) []*block.Block {
+
+	headers := make([]*block.Block, count)
+	for i := range headers {
+		header := &block.Block{
+			BlockHeader:    &block.BlockHeader{Height: 0},
+			PrevHash:       cryptography.RandomHash(),
+			PrevKernelHash: cryptography.RandomHash(),
+			Bloom:          &block.BlockBloom{Hash: cryptography.RandomHash(), KernelHash: cryptography.RandomHash()},
+		}
+		if prev != nil {
+			header.Height = prev.Height + 1
+			header.PrevHash = prev.Bloom.Hash
+			header.PrevKernelHash = prev.Bloom.KernelHash
+		}
+		headers[i] = header
+		prev = header
+	}
+	return headers
+}
+
+func TestValidateHeadersChain(t *testing.T) {
+
+	headers := createTestHeaders(nil, 10)
+	assert.Nil(t, validateHeadersChain(headers[0], headers[1:]))
+	assert.Nil(t, validateHeadersChain(headers[4], headers[5:]))
+	assert.NotNil(t, validateHeadersChain(headers[4], headers[6:]), "a header is missing")
+
+	fork := createTestHeaders(headers[4], 5)
+	assert.Nil(t, validateHeadersChain(headers[4], fork))
+	assert.NotNil(t, validateHeadersChain(headers[3], fork))
+
+	header := *fork[2]
+	header.PrevHash = cryptography.RandomHash()
+	assert.NotNil(t, validateHeadersChain(fork[1], []*block.Block{&header}), "PrevHash is not matching")
+
+	header = *fork[2]
+	header.PrevKernelHash = cryptography.RandomHash()
+	assert.NotNil(t, validateHeadersChain(fork[1], []*block.Block{&header}), "PrevKernelHash is not matching")
+
+	header = *fork[2]
+	header.BlockHeader = &block.BlockHeader{Height: fork[2].Height + 1}
+	assert.NotNil(t, validateHeadersChain(fork[1], []*block.Block{&header}), "Height is not consecutive")
+}
+
+func TestDownloadForkHeaders(t *testing.T) {
+
+	chain := createTestHeaders(nil, 100)
+	chainData := &blockchain.BlockchainData{Height: uint64(len(chain))}
+
+	loadHash := func(height uint64) ([]byte, error) {
+		if height >= uint64(len(chain)) {
+			return nil, errors.New("Block was not found")
+		}
+		return chain[height].Bloom.Hash, nil
+	}
+
+	//the sizes of the requested batches
+	var requests []uint64
+
+	downloadFrom := func(peerChain []*block.Block) func(start, count uint64) ([]*block.Block, error) {
+		requests = nil
+		return func(start, count uint64) ([]*block.Block, error) {
+			requests = append(requests, count)
+			end := generics.Min(start+count, uint64(len(peerChain)))
+			if start >= end {
+				return nil, errors.New("Start is invalid")
+			}
+			return peerChain[start:end], nil
+		}
+	}
+
+	//the fork shares the blocks until the height 79
+	peerChain := append(append([]*block.Block{}, chain[:80]...), createTestHeaders(chain[79], 40)...)
+	fork := &Fork{End: uint64(len(peerChain))}
+
+	start, ancestor, headers, err := downloadForkHeaders(fork, chainData, downloadFrom(peerChain), loadHash)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(80), start)
+	assert.Equal(t, chain[79], ancestor)
+	assert.Equal(t, peerChain[80:100], headers, "the headers above our chain are downloaded later")
+	assert.Equal(t, []uint64{config.FORK_FIRST_HEADERS, config.FORK_FIRST_HEADERS * 2}, requests, "the batch is doubled until the ancestor is found")
+
+	//the peer is only announcing new blocks on top of our chain
+	peerChain = append(append([]*block.Block{}, chain...), createTestHeaders(chain[99], 5)...)
+	start, ancestor, headers, err = downloadForkHeaders(&Fork{End: uint64(len(peerChain))}, chainData, downloadFrom(peerChain), loadHash)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), start)
+	assert.Equal(t, chain[99], ancestor)
+	assert.Empty(t, headers)
+	assert.Equal(t, []uint64{config.FORK_FIRST_HEADERS}, requests)
+
+	//the headers of the fork are not linked
+	peerChain = append(append([]*block.Block{}, chain[:80]...), createTestHeaders(chain[79], 40)...)
+	peerChain[90] = createTestHeaders(peerChain[89], 1)[0]
+	_, _, _, err = downloadForkHeaders(fork, chainData, downloadFrom(peerChain), loadHash)
+	assert.Equal(t, errInvalidHeadersChain, err)
+
+	//the ancestor is deeper than the maximum reorg depth
+	maxReorgDepth := config.FORK_MAX_REORG_DEPTH
+	defer func() {
+		config.FORK_MAX_REORG_DEPTH = maxReorgDepth
+	}()
+	config.FORK_MAX_REORG_DEPTH = 30
+
+	deep := chainData.Height - config.FORK_MAX_REORG_DEPTH - 2
+	peerChain = append(append([]*block.Block{}, chain[:deep]...), createTestHeaders(chain[deep-1], 100)...)
+	start, _, _, err = downloadForkHeaders(&Fork{End: uint64(len(peerChain))}, chainData, downloadFrom(peerChain), loadHash)
+	assert.Equal(t, errForkTooDeep, err)
+	assert.Greater(t, chainData.Height-start, config.FORK_MAX_REORG_DEPTH, "the height where the search stopped is returned")
+
+	//the same fork is found when the maximum reorg depth is bigger
+	config.FORK_MAX_REORG_DEPTH = 50
+	start, ancestor, _, err = downloadForkHeaders(&Fork{End: uint64(len(peerChain))}, chainData, downloadFrom(peerChain), loadHash)
+	assert.Nil(t, err)
+	assert.Equal(t, deep, start)
+	assert.Equal(t, chain[deep-1], ancestor)
+	for i := 1; i < len(requests); i++ {
+		assert.Equal(t, generics.Min(requests[i-1]*2, config.FORK_MAX_HEADERS), requests[i])
+	}
+
+	//the peer returned fewer headers than requested
+	_, _, _, err = downloadForkHeaders(fork, chainData, func(start, count uint64) ([]*block.Block, error) {
+		return peerChain[start : start+count-1], nil
+	}, loadHash)
+	assert.NotNil(t, err)
+}
+
+func TestDownloadBlocksParallel(t *testing.T) {
+
+	newConn := func() *connection.AdvancedConnection {
+		return &connection.AdvancedConnection{IsClosed: abool.New()}
+	}
+
+	headers := createTestHeaders(nil, 20)
+
+	slow, missing, closed, other := newConn(), newConn(), newConn(), newConn()
+	closed.IsClosed.Set()
+
+	calls := make(map[*connection.AdvancedConnection]int)
+	callsLock := &sync.Mutex{}
+	count := func(conn *connection.AdvancedConnection) {
+		callsLock.Lock()
+		defer callsLock.Unlock()
+		calls[conn] += 1
+	}
+
+	download := func(conn *connection.AdvancedConnection, header *block.Block) (*block_complete.BlockComplete, error) {
+		count(conn)
+		switch conn {
+		case slow:
+			return nil, errors.New("Timeout")
+		case missing:
+			if header.Height%2 == 1 {
+				return nil, errors.New("Block was not found")
+			}
+		case closed:
+			t.Error("closed connections must not be used")
+		}
+		blkComplete := block_complete.CreateEmptyBlockComplete()
+		blkComplete.Block = header
+		return blkComplete, nil
+	}
+
+	results := downloadBlocksParallel([]*connection.AdvancedConnection{slow, missing, closed}, []*connection.AdvancedConnection{other}, headers, download)
+	for i, blkComplete := range results {
+		assert.NotNil(t, blkComplete)
+		if blkComplete != nil {
+			assert.Equal(t, headers[i], blkComplete.Block)
+		}
+	}
+
+	assert.LessOrEqual(t, calls[slow], config.FORK_DOWNLOAD_WORKERS, "the peer which timed out should not be used again")
+	assert.Equal(t, len(headers)/2, calls[other], "the other peers are used only for the blocks the fork peers don't have")
+
+	//the other peers are not used when the fork peers fail
+	calls = make(map[*connection.AdvancedConnection]int)
+	failing := newConn()
+	results = downloadBlocksParallel([]*connection.AdvancedConnection{failing}, []*connection.AdvancedConnection{other}, headers, func(conn *connection.AdvancedConnection, header *block.Block) (*block_complete.BlockComplete, error) {
+		count(conn)
+		return nil, errors.New("Invalid block")
+	})
+	for _, blkComplete := range results {
+		assert.Nil(t, blkComplete)
+	}
+	assert.Equal(t, len(headers)*config.FORK_DOWNLOAD_RETRIES, calls[failing])
+	assert.Equal(t, 0, calls[other])
+
+	//a not found answer is not counted as a failed attempt
+	calls = make(map[*connection.AdvancedConnection]int)
+	results = downloadBlocksParallel([]*connection.AdvancedConnection{missing}, nil, headers, download)
+	for i, blkComplete := range results {
+		assert.Equal(t, i%2 == 0, blkComplete != nil)
+	}
+	assert.Equal(t, len(headers), calls[missing], "a peer is not asked again for a block it doesn't have")
+}
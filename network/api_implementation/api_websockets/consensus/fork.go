@@ -3,6 +3,7 @@ package consensus
 import (
 	"math/big"
 	"math/rand"
+	"pandora-pay/blockchain/blocks/block"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/helpers/linked_list"
 	"pandora-pay/network/websocks/connection"
@@ -18,6 +19,9 @@ type Fork struct {
 	Hash               []byte                                                 `json:"hash" msgpack:"hash"`
 	HashStr            string                                                 `json:"hashStr" msgpack:"hashStr"`
 	PrevHash           []byte                                                 `json:"prevHash" msgpack:"prevHash"`
+	Headers            []*block.Block                                         `json:"-" msgpack:"-"` //validated headers of the blocks not downloaded yet, starting with Current
+	lastHeader         *block.Block                                           //last validated header, used to link the next headers
+	downloadStart      uint64
 	conns              []*connection.AdvancedConnection
 	errors             int
 	sync.RWMutex       `json:"-" msgpack:"-"`
@@ -54,3 +58,10 @@ func (fork *Fork) AddConn(conn *connection.AdvancedConnection, lock bool) {
 
 	fork.conns = append(fork.conns, conn)
 }
+
+//is locked before
+func (fork *Fork) penalizeConns(penalty uint64, message string) {
+	for _, conn := range fork.conns {
+		conn.Penalize(penalty, message)
+	}
+}
@@ -1,19 +1,57 @@
 package banned_nodes
 
 import (
+	"net"
 	"net/url"
+	"pandora-pay/gui"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/network/network_config"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
 type BannedNodesType struct {
 	bannedMap *generics.Map[string, *BannedNode]
+	scoresMap *generics.Map[string, *PeerScore]
+	lock      *sync.Mutex //used for updating the scores
 }
 
+// GetHost returns the host of an url or of an address ip:port. The misbehaviors are tracked by host
+func GetHost(address string) string {
+	if u, err := url.Parse(address); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
+
+func (this *BannedNodesType) isBanned(key string) bool {
+	bannedNode, found := this.bannedMap.Load(key)
+	if !found {
+		return false
+	}
+	if bannedNode.IsExpired(time.Now()) {
+		this.Unban(key)
+		return false
+	}
+	return true
+}
+
+// IsBanned checks the url and its host
 func (this *BannedNodesType) IsBanned(urlStr string) bool {
-	if _, found := this.bannedMap.Load(urlStr); found {
+	if this.isBanned(urlStr) {
 		return true
 	}
+	if host := GetHost(urlStr); host != urlStr {
+		return this.isBanned(host)
+	}
 	return false
 }
 
@@ -22,11 +60,154 @@ func (this *BannedNodesType) Ban(url *url.URL, urlStr, message string, duration
 		urlStr = url.String()
 	}
 	time := time.Now()
-	this.bannedMap.Store(urlStr, &BannedNode{
-		URL:        url,
+	bannedNode := &BannedNode{
+		URL:        urlStr,
 		Message:    message,
 		Timestamp:  time,
 		Expiration: time.Add(duration),
+	}
+	this.bannedMap.Store(urlStr, bannedNode)
+	this.save("bannedNodes:"+urlStr, bannedNode)
+}
+
+func (this *BannedNodesType) Unban(urlStr string) bool {
+	if _, found := this.bannedMap.LoadAndDelete(urlStr); !found {
+		return false
+	}
+	this.save("bannedNodes:"+urlStr, nil)
+	return true
+}
+
+// Penalize increases the misbehavior score of the host of the address. When the score reaches the threshold, the host is banned
+func (this *BannedNodesType) Penalize(address string, penalty uint64, message string) bool {
+
+	host := GetHost(address)
+	if host == "" {
+		return false
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	now := time.Now()
+
+	score := penalty
+	if peerScore, found := this.scoresMap.Load(host); found {
+		score += peerScore.GetScore(now)
+	}
+	peerScore := &PeerScore{host, score, now}
+
+	if peerScore.Score < network_config.NETWORK_BAN_SCORE_THRESHOLD {
+		this.scoresMap.Store(host, peerScore)
+		this.save("peerScores:"+host, peerScore)
+		return false
+	}
+
+	this.scoresMap.Delete(host)
+	this.save("peerScores:"+host, nil)
+
+	this.Ban(nil, host, message, network_config.NETWORK_BAN_DURATION)
+	return true
+}
+
+func (this *BannedNodesType) GetBannedNodes() []*BannedNode {
+	now := time.Now()
+	list := make([]*BannedNode, 0)
+	this.bannedMap.Range(func(key string, bannedNode *BannedNode) bool {
+		if !bannedNode.IsExpired(now) {
+			list = append(list, bannedNode)
+		}
+		return true
+	})
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Timestamp.Before(list[j].Timestamp)
+	})
+	return list
+}
+
+func (this *BannedNodesType) GetPeerScores() []*PeerScore {
+	now := time.Now()
+	list := make([]*PeerScore, 0)
+	this.scoresMap.Range(func(key string, peerScore *PeerScore) bool {
+		if score := peerScore.GetScore(now); score > 0 {
+			list = append(list, &PeerScore{peerScore.Host, score, peerScore.Timestamp})
+		}
+		return true
+	})
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Score > list[j].Score
+	})
+	return list
+}
+
+// save stores the value in the settings store. A nil value removes the key
+func (this *BannedNodesType) save(key string, value any) {
+
+	if store.StoreSettings == nil {
+		return
+	}
+
+	if err := store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+		if value == nil {
+			writer.Delete(key)
+			return
+		}
+		data, err := msgpack.Marshal(value)
+		if err != nil {
+			return
+		}
+		writer.Put(key, data)
+		return
+	}); err != nil {
+		gui.GUI.Error("Error saving banned nodes", err)
+	}
+}
+
+// Load restores the bans and the scores from the settings store. The expired ones are removed
+func (this *BannedNodesType) Load() error {
+
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		now := time.Now()
+		removed := make([]string, 0)
+
+		writer.IteratePrefix("bannedNodes:", 0, func(key string, value []byte) bool {
+			bannedNode := &BannedNode{}
+			if err = msgpack.Unmarshal(value, bannedNode); err != nil {
+				return false
+			}
+			if bannedNode.IsExpired(now) {
+				removed = append(removed, key)
+			} else {
+				this.bannedMap.Store(strings.TrimPrefix(key, "bannedNodes:"), bannedNode)
+			}
+			return true
+		})
+		if err != nil {
+			return
+		}
+
+		writer.IteratePrefix("peerScores:", 0, func(key string, value []byte) bool {
+			peerScore := &PeerScore{}
+			if err = msgpack.Unmarshal(value, peerScore); err != nil {
+				return false
+			}
+			if peerScore.GetScore(now) == 0 {
+				removed = append(removed, key)
+			} else {
+				this.scoresMap.Store(peerScore.Host, peerScore)
+			}
+			return true
+		})
+		if err != nil {
+			return
+		}
+
+		for _, key := range removed {
+			writer.Delete(key)
+		}
+
+		return
 	})
 }
 
@@ -35,5 +216,7 @@ var BannedNodes *BannedNodesType
 func init() {
 	BannedNodes = &BannedNodesType{
 		bannedMap: &generics.Map[string, *BannedNode]{},
+		scoresMap: &generics.Map[string, *PeerScore]{},
+		lock:      &sync.Mutex{},
 	}
 }
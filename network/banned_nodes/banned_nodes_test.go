@@ -0,0 +1,39 @@
+package banned_nodes
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/network/network_config"
+	"testing"
+	"time"
+)
+
+func TestBannedNodesPenalize(t *testing.T) {
+
+	assert.Equal(t, "1.2.3.4", GetHost("1.2.3.4:5000"))
+	assert.Equal(t, "1.2.3.4", GetHost("ws://1.2.3.4:5000/ws"))
+	assert.Equal(t, "1.2.3.4", GetHost("1.2.3.4"))
+
+	for i := uint64(0); i < network_config.NETWORK_BAN_SCORE_THRESHOLD/network_config.NETWORK_PENALTY_MALFORMED_MESSAGE-1; i++ {
+		assert.False(t, BannedNodes.Penalize("1.2.3.4:5000", network_config.NETWORK_PENALTY_MALFORMED_MESSAGE, "Malformed message"))
+	}
+	assert.False(t, BannedNodes.IsBanned("ws://1.2.3.4:5000/ws"))
+	assert.Equal(t, 1, len(BannedNodes.GetPeerScores()))
+
+	assert.True(t, BannedNodes.Penalize("1.2.3.4:6000", network_config.NETWORK_PENALTY_MALFORMED_MESSAGE, "Malformed message"))
+	assert.True(t, BannedNodes.IsBanned("ws://1.2.3.4:5000/ws"))
+	assert.True(t, BannedNodes.IsBanned("1.2.3.4:7000"))
+	assert.False(t, BannedNodes.IsBanned("1.2.3.5:5000"))
+	assert.Equal(t, 0, len(BannedNodes.GetPeerScores()))
+
+	assert.True(t, BannedNodes.Unban("1.2.3.4"))
+	assert.False(t, BannedNodes.IsBanned("1.2.3.4:5000"))
+
+	//expired bans are ignored
+	BannedNodes.Ban(nil, "1.2.3.6", "expired", -time.Second)
+	assert.False(t, BannedNodes.IsBanned("1.2.3.6"))
+	assert.Equal(t, 0, len(BannedNodes.GetBannedNodes()))
+
+	//the score decays in time
+	peerScore := &PeerScore{"1.2.3.7", 50, time.Now().Add(-2 * time.Hour)}
+	assert.Equal(t, 50-2*network_config.NETWORK_PEER_SCORE_DECAY_PER_HOUR, peerScore.GetScore(time.Now()))
+}
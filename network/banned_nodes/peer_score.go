@@ -0,0 +1,21 @@
+package banned_nodes
+
+import (
+	"pandora-pay/network/network_config"
+	"time"
+)
+
+// PeerScore is the misbehavior score of a host. It decays in time, so only frequent misbehaviors lead to a ban
+type PeerScore struct {
+	Host      string    `json:"host" msgpack:"host"`
+	Score     uint64    `json:"score" msgpack:"score"`
+	Timestamp time.Time `json:"timestamp" msgpack:"timestamp"`
+}
+
+func (this *PeerScore) GetScore(now time.Time) uint64 {
+	decay := uint64(now.Sub(this.Timestamp).Hours() * float64(network_config.NETWORK_PEER_SCORE_DECAY_PER_HOUR))
+	if decay >= this.Score {
+		return 0
+	}
+	return this.Score - decay
+}
@@ -3,6 +3,7 @@ package connected_nodes
 import (
 	"pandora-pay/helpers/container_list"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/network/websocks/connection"
 	"sync/atomic"
 )
@@ -56,4 +57,11 @@ func init() {
 		0,
 		0,
 	}
+
+	metrics.NewGaugeFunc("pandora_network_client_sockets", "Number of sockets connected by this node to other nodes", func() float64 {
+		return float64(atomic.LoadInt64(&ConnectedNodes.Clients))
+	})
+	metrics.NewGaugeFunc("pandora_network_server_sockets", "Number of sockets connected to this node", func() float64 {
+		return float64(atomic.LoadInt64(&ConnectedNodes.ServerSockets))
+	})
 }
@@ -1,17 +1,43 @@
 package known_node
 
 import (
+	"pandora-pay/network/network_config"
 	"sync/atomic"
+	"time"
+)
+
+type KnownNodeSource string
+
+const (
+	KNOWN_NODE_SOURCE_SEED     KnownNodeSource = "seed"
+	KNOWN_NODE_SOURCE_PEERS    KnownNodeSource = "peers"    //received from network/nodes
+	KNOWN_NODE_SOURCE_INCOMING KnownNodeSource = "incoming" //url provided in the handshake
+	KNOWN_NODE_SOURCE_MANUAL   KnownNodeSource = "manual"   //added using the API
 )
 
 type KnownNode struct {
 	URL    string
 	IsSeed bool
+	Source KnownNodeSource
 }
 
 type KnownNodeScored struct {
 	KnownNode
-	Score int32 //use atomic
+	Score         int32 //use atomic
+	Failures      int32 //use atomic. Failed connections since the last successful one
+	LastConnected int64 //use atomic. Unix time of the last successful connection
+	Added         int64 //Unix time
+}
+
+// KnownNodeInfo is the stored and exported state of a known node
+type KnownNodeInfo struct {
+	URL           string          `json:"url" msgpack:"url"`
+	IsSeed        bool            `json:"isSeed" msgpack:"isSeed"`
+	Source        KnownNodeSource `json:"source" msgpack:"source"`
+	Score         int32           `json:"score" msgpack:"score"`
+	Failures      int32           `json:"failures" msgpack:"failures"`
+	LastConnected int64           `json:"lastConnected" msgpack:"lastConnected"`
+	Added         int64           `json:"added" msgpack:"added"`
 }
 
 var KNOWN_KNODE_SCORE_MINIMUM = int32(-1000)
@@ -44,3 +70,48 @@ func (self *KnownNodeScored) DecreaseScore(delta int32, isServer bool) (bool, bo
 	}
 	return true, false, newScore
 }
+
+func (self *KnownNodeScored) GetInfo() *KnownNodeInfo {
+	return &KnownNodeInfo{
+		self.URL,
+		self.IsSeed,
+		self.Source,
+		atomic.LoadInt32(&self.Score),
+		atomic.LoadInt32(&self.Failures),
+		atomic.LoadInt64(&self.LastConnected),
+		self.Added,
+	}
+}
+
+// LastSeen is the last successful connection or, if it never connected, when it was added
+func (this *KnownNodeInfo) LastSeen() time.Time {
+	if this.LastConnected > this.Added {
+		return time.Unix(this.LastConnected, 0)
+	}
+	return time.Unix(this.Added, 0)
+}
+
+func (this *KnownNodeInfo) IsExpired(now time.Time) bool {
+	return now.Sub(this.LastSeen()) > network_config.NETWORK_KNOWN_NODES_MAX_AGE
+}
+
+// GetDecayedScore moves the score towards 0 as the node was not seen for a while. Old misbehaviors are forgiven and old merits are forgotten
+func (this *KnownNodeInfo) GetDecayedScore(now time.Time) int32 {
+
+	decay := int64(now.Sub(this.LastSeen()).Hours() * network_config.NETWORK_KNOWN_NODES_SCORE_DECAY_PER_HOUR)
+	if decay <= 0 {
+		return this.Score
+	}
+
+	score := int64(this.Score)
+	if score > 0 {
+		if decay >= score {
+			return 0
+		}
+		return int32(score - decay)
+	}
+	if decay >= -score {
+		return 0
+	}
+	return int32(score + decay)
+}
@@ -0,0 +1,28 @@
+package known_node
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/network/network_config"
+	"testing"
+	"time"
+)
+
+func TestKnownNodeInfoDecay(t *testing.T) {
+
+	now := time.Now()
+
+	info := &KnownNodeInfo{URL: "ws://1.2.3.4:16000/ws", Score: 100, Added: now.Add(-48 * time.Hour).Unix(), LastConnected: now.Add(-10 * time.Hour).Unix()}
+	assert.Equal(t, now.Add(-10*time.Hour).Unix(), info.LastSeen().Unix())
+	assert.Equal(t, int32(100-10*network_config.NETWORK_KNOWN_NODES_SCORE_DECAY_PER_HOUR), info.GetDecayedScore(now))
+	assert.False(t, info.IsExpired(now))
+
+	info.Score = -100
+	assert.Equal(t, int32(-100+10*network_config.NETWORK_KNOWN_NODES_SCORE_DECAY_PER_HOUR), info.GetDecayedScore(now))
+
+	info.Score = 20
+	assert.Equal(t, int32(0), info.GetDecayedScore(now))
+
+	info.LastConnected = 0
+	assert.Equal(t, now.Add(-48*time.Hour).Unix(), info.LastSeen().Unix())
+	assert.True(t, info.IsExpired(now.Add(network_config.NETWORK_KNOWN_NODES_MAX_AGE)))
+}
@@ -3,14 +3,20 @@ package known_nodes
 import (
 	"errors"
 	"math/rand"
+	"pandora-pay/gui"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/msgpack"
 	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/connected_nodes"
 	"pandora-pay/network/known_nodes/known_node"
 	"pandora-pay/network/network_config"
+	"pandora-pay/store"
 	"pandora-pay/store/min_max_heap"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type KnownNodesType struct {
@@ -36,6 +42,25 @@ func (this *KnownNodesType) GetList() []*known_node.KnownNodeScored {
 	return knownList
 }
 
+// GetInfoList returns the known nodes sorted by score
+func (this *KnownNodesType) GetInfoList() []*known_node.KnownNodeInfo {
+	knownList := this.GetList()
+
+	list := make([]*known_node.KnownNodeInfo, len(knownList))
+	for i, knownNode := range knownList {
+		list[i] = knownNode.GetInfo()
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Score > list[j].Score
+	})
+	return list
+}
+
+func (this *KnownNodesType) GetKnownNode(url string) *known_node.KnownNodeScored {
+	knownNode, _ := this.knownMap.Load(url)
+	return knownNode
+}
+
 func (this *KnownNodesType) GetRandomKnownNode() *known_node.KnownNodeScored {
 	this.knownListMutex.RLock()
 	defer this.knownListMutex.RUnlock()
@@ -86,18 +111,34 @@ func (this *KnownNodesType) DecreaseKnownNodeScore(knownNode *known_node.KnownNo
 }
 
 func (this *KnownNodesType) MarkKnownNodeConnected(knownNode *known_node.KnownNodeScored) {
+	atomic.StoreInt64(&knownNode.LastConnected, time.Now().Unix())
+	atomic.StoreInt32(&knownNode.Failures, 0)
+	this.save(knownNode)
+
 	this.knownNotConnectedMaxHeapMutex.Lock()
 	defer this.knownNotConnectedMaxHeapMutex.Unlock()
 	this.knownNotConnectedMaxHeap.DeleteByKey([]byte(knownNode.URL))
 }
 
+// MarkKnownNodeDisconnected makes the node available again for connecting unless it was removed meanwhile
 func (this *KnownNodesType) MarkKnownNodeDisconnected(knownNode *known_node.KnownNodeScored) {
+	this.save(knownNode)
+
 	this.knownNotConnectedMaxHeapMutex.Lock()
 	defer this.knownNotConnectedMaxHeapMutex.Unlock()
+	if !this.isKnown(knownNode) { //RemoveKnownNode deletes it from the heap after it is deleted from the map
+		return
+	}
 	this.knownNotConnectedMaxHeap.Update(float64(atomic.LoadInt32(&knownNode.Score)), []byte(knownNode.URL))
 }
 
-func (this *KnownNodesType) AddKnownNode(url string, isSeed bool) (*known_node.KnownNodeScored, error) {
+// MarkKnownNodeFailed counts a failed connection. The score is decreased by the caller
+func (this *KnownNodesType) MarkKnownNodeFailed(knownNode *known_node.KnownNodeScored) {
+	atomic.AddInt32(&knownNode.Failures, 1)
+	this.save(knownNode)
+}
+
+func (this *KnownNodesType) AddKnownNode(url string, isSeed bool, source known_node.KnownNodeSource) (*known_node.KnownNodeScored, error) {
 
 	if url == "" {
 		return nil, errors.New("url is empty")
@@ -115,13 +156,16 @@ func (this *KnownNodesType) AddKnownNode(url string, isSeed bool) (*known_node.K
 		KnownNode: known_node.KnownNode{
 			URL:    url,
 			IsSeed: isSeed,
+			Source: source,
 		},
 		Score: 0,
+		Added: time.Now().Unix(),
 	}
 
 	if _, exists := this.knownMap.LoadOrStore(url, knownNode); exists {
 		return nil, errors.New("Already exists")
 	}
+	this.save(knownNode)
 
 	this.knownListMutex.Lock()
 	this.knownList = append(this.knownList, knownNode)
@@ -142,6 +186,8 @@ func (this *KnownNodesType) RemoveKnownNode(knownNode *known_node.KnownNodeScore
 
 	if _, exists := this.knownMap.LoadAndDelete(knownNode.URL); exists {
 
+		this.delete(knownNode.URL)
+
 		this.knownNotConnectedMaxHeapMutex.Lock()
 		this.knownNotConnectedMaxHeap.DeleteByKey([]byte(knownNode.URL))
 		this.knownNotConnectedMaxHeapMutex.Unlock()
@@ -196,8 +242,10 @@ func (this *KnownNodesType) Reset(urls []string, isSeed bool) (err error) {
 			KnownNode: known_node.KnownNode{
 				URL:    url,
 				IsSeed: isSeed,
+				Source: known_node.KNOWN_NODE_SOURCE_SEED,
 			},
 			Score: 0,
+			Added: time.Now().Unix(),
 		}
 
 		this.knownMap.LoadOrStore(url, knownNode)
@@ -212,6 +260,135 @@ func (this *KnownNodesType) Reset(urls []string, isSeed bool) (err error) {
 	return
 }
 
+func (this *KnownNodesType) isKnown(knownNode *known_node.KnownNodeScored) bool {
+	knownNode2, _ := this.knownMap.Load(knownNode.URL)
+	return knownNode2 == knownNode
+}
+
+// save stores the known node in the settings store. A node that was removed or replaced is not stored again
+func (this *KnownNodesType) save(knownNode *known_node.KnownNodeScored) {
+
+	if store.StoreSettings == nil {
+		return
+	}
+
+	if err := store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+		if !this.isKnown(knownNode) { //checked inside the update as RemoveKnownNode deletes it from the store after it is deleted from the map
+			return
+		}
+		data, err := msgpack.Marshal(knownNode.GetInfo())
+		if err != nil {
+			return
+		}
+		writer.Put("knownNodes:"+knownNode.URL, data)
+		return
+	}); err != nil {
+		gui.GUI.Error("Error saving known node", err)
+	}
+}
+
+func (this *KnownNodesType) delete(url string) {
+
+	if store.StoreSettings == nil {
+		return
+	}
+
+	if err := store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		writer.Delete("knownNodes:" + url)
+		return nil
+	}); err != nil {
+		gui.GUI.Error("Error removing known node", err)
+	}
+}
+
+// Load restores the known nodes from the settings store. The scores decay with the time the nodes were not seen and the nodes not seen for too long are forgotten. The seeds should be already added using Reset
+func (this *KnownNodesType) Load() error {
+
+	if store.StoreSettings == nil {
+		return nil
+	}
+
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		now := time.Now()
+		removed := make([]string, 0)
+		infos := make([]*known_node.KnownNodeInfo, 0)
+
+		writer.IteratePrefix("knownNodes:", 0, func(key string, value []byte) bool {
+			info := &known_node.KnownNodeInfo{}
+			if err = msgpack.Unmarshal(value, info); err != nil {
+				return false
+			}
+			if this.GetKnownNode(info.URL) == nil && (info.IsExpired(now) || banned_nodes.BannedNodes.IsBanned(info.URL)) {
+				removed = append(removed, key)
+			} else {
+				infos = append(infos, info)
+			}
+			return true
+		})
+		if err != nil {
+			return
+		}
+
+		for _, key := range removed {
+			writer.Delete(key)
+		}
+
+		//the best nodes are restored first in case the limit is reached
+		sort.Slice(infos, func(i, j int) bool {
+			return infos[i].Score > infos[j].Score
+		})
+
+		this.knownNotConnectedMaxHeapMutex.Lock()
+		defer this.knownNotConnectedMaxHeapMutex.Unlock()
+
+		this.knownListMutex.Lock()
+		defer this.knownListMutex.Unlock()
+
+		for _, info := range infos {
+
+			score := info.GetDecayedScore(now)
+
+			knownNode, found := this.knownMap.Load(info.URL)
+			if !found {
+
+				if atomic.LoadInt32(&this.knownCount) > network_config.NETWORK_KNOWN_NODES_LIMIT {
+					break
+				}
+
+				source := info.Source
+				if source == known_node.KNOWN_NODE_SOURCE_SEED { //no longer a seed
+					source = known_node.KNOWN_NODE_SOURCE_PEERS
+				}
+
+				knownNode = &known_node.KnownNodeScored{
+					KnownNode: known_node.KnownNode{
+						URL:    info.URL,
+						IsSeed: false,
+						Source: source,
+					},
+				}
+				this.knownMap.Store(info.URL, knownNode)
+				this.knownList = append(this.knownList, knownNode)
+				atomic.AddInt32(&this.knownCount, 1)
+			}
+
+			atomic.StoreInt32(&knownNode.Score, score)
+			atomic.StoreInt32(&knownNode.Failures, info.Failures)
+			atomic.StoreInt64(&knownNode.LastConnected, info.LastConnected)
+			knownNode.Added = info.Added
+
+			if _, ok := connected_nodes.ConnectedNodes.AllAddresses.Load(info.URL); !ok {
+				if err = this.knownNotConnectedMaxHeap.Update(float64(score), []byte(info.URL)); err != nil {
+					return
+				}
+			}
+		}
+
+		return
+	})
+}
+
 func init() {
 	KnownNodes = &KnownNodesType{
 		&generics.Map[string, *known_node.KnownNodeScored]{},
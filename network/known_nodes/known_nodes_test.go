@@ -0,0 +1,85 @@
+package known_nodes
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/network/banned_nodes"
+	"pandora-pay/network/known_nodes/known_node"
+	"pandora-pay/network/network_config"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+	"time"
+)
+
+func TestKnownNodesSaveLoad(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("/settings")
+	assert.Nil(t, err)
+	defer db.Close()
+
+	store.StoreSettings = &store.Store{Name: "settings", Opened: true, DB: db}
+	defer func() {
+		store.StoreSettings = nil
+	}()
+
+	stored := func(url string) (found bool) {
+		assert.Nil(t, db.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+			found = reader.Get("knownNodes:"+url) != nil
+			return nil
+		}))
+		return
+	}
+
+	assert.Nil(t, KnownNodes.Reset(nil, false))
+
+	knownNode, err := KnownNodes.AddKnownNode("ws://1.2.3.4:16000/ws", false, known_node.KNOWN_NODE_SOURCE_PEERS)
+	assert.Nil(t, err)
+	KnownNodes.IncreaseKnownNodeScore(knownNode, 20, false)
+	KnownNodes.MarkKnownNodeFailed(knownNode)
+	KnownNodes.MarkKnownNodeDisconnected(knownNode)
+
+	//a removed node must not be stored again when its connection closes
+	removedNode, err := KnownNodes.AddKnownNode("ws://1.2.3.5:16000/ws", false, known_node.KNOWN_NODE_SOURCE_PEERS)
+	assert.Nil(t, err)
+	KnownNodes.RemoveKnownNode(removedNode)
+	KnownNodes.MarkKnownNodeDisconnected(removedNode)
+	KnownNodes.MarkKnownNodeFailed(removedNode)
+	assert.False(t, stored(removedNode.URL))
+	assert.Equal(t, knownNode, KnownNodes.GetBestNotConnectedKnownNode())
+
+	old := time.Now().Add(-network_config.NETWORK_KNOWN_NODES_MAX_AGE - time.Hour).Unix()
+	expired := &known_node.KnownNodeInfo{URL: "ws://1.2.3.6:16000/ws", Score: 50, Added: old, LastConnected: old}
+	banned := &known_node.KnownNodeInfo{URL: "ws://1.2.3.7:16000/ws", Score: 50, Added: time.Now().Unix()}
+	assert.Nil(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		for _, info := range []*known_node.KnownNodeInfo{expired, banned} {
+			data, err := msgpack.Marshal(info)
+			if err != nil {
+				return err
+			}
+			writer.Put("knownNodes:"+info.URL, data)
+		}
+		return nil
+	}))
+
+	banned_nodes.BannedNodes.Ban(nil, "1.2.3.7", "test", time.Hour)
+	defer banned_nodes.BannedNodes.Unban("1.2.3.7")
+
+	assert.Nil(t, KnownNodes.Reset(nil, false))
+	assert.Nil(t, KnownNodes.Load())
+
+	loaded := KnownNodes.GetKnownNode(knownNode.URL)
+	assert.NotNil(t, loaded)
+	assert.NotSame(t, knownNode, loaded)
+	assert.Equal(t, knownNode.Score, loaded.Score)
+	assert.Equal(t, int32(1), loaded.Failures)
+	assert.Equal(t, known_node.KNOWN_NODE_SOURCE_PEERS, loaded.Source)
+
+	assert.Nil(t, KnownNodes.GetKnownNode(removedNode.URL))
+	assert.Nil(t, KnownNodes.GetKnownNode(expired.URL))
+	assert.Nil(t, KnownNodes.GetKnownNode(banned.URL))
+	assert.False(t, stored(expired.URL), "expired nodes are removed from the store")
+	assert.False(t, stored(banned.URL), "banned nodes are removed from the store")
+	assert.Equal(t, 1, len(KnownNodes.GetList()))
+}
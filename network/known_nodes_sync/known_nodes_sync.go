@@ -3,6 +3,7 @@ package known_nodes_sync
 import (
 	"pandora-pay/network/api_implementation/api_common"
 	"pandora-pay/network/known_nodes"
+	"pandora-pay/network/known_nodes/known_node"
 	"pandora-pay/network/websocks/connection"
 )
 
@@ -24,7 +25,7 @@ func (self *KnownNodesSyncType) DownloadNetworkNodes(conn *connection.AdvancedCo
 
 	for _, node := range data.Nodes {
 		if node != nil {
-			known_nodes.KnownNodes.AddKnownNode(node.URL, false)
+			known_nodes.KnownNodes.AddKnownNode(node.URL, false, known_node.KNOWN_NODE_SOURCE_PEERS)
 		}
 	}
 
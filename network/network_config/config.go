@@ -1,6 +1,8 @@
 package network_config
 
 import (
+	"encoding/json"
+	"errors"
 	"pandora-pay/config"
 	"pandora-pay/config/arguments"
 	"pandora-pay/network/network_config/network_config_auth"
@@ -18,6 +20,27 @@ var (
 	NETWORK_ENABLE_SUBSCRIPTIONS               = false
 	NETWORK_CONNECTIONS_READY_THRESHOLD        = int64(1)
 	STATIC_FILES                               = map[string]string{}
+
+	API_RATE_LIMIT      = 100.0               //tokens refilled every second for an ip. 0 disables the rate limiting
+	API_RATE_LIMIT_AUTH = 1000.0              //tokens refilled every second for an authenticated user
+	API_RATE_LIMIT_PEER = 1000.0              //tokens refilled every second for a peer the node connected to
+	API_RATE_COSTS      = map[string]float64{ //methods which are not listed cost 1 token
+		"block":                     5,
+		"block-headers":             10,
+		"block-complete":            10,
+		"accounts/by-keys":          10,
+		"accounts/keys-by-index":    10,
+		"account/txs":               5,
+		"mempool":                   5,
+		"mempool/new-tx":            5,
+		"fee-estimate":              10,
+		"assets/list":               10,
+		"registrations/list":        10,
+		"conditional-payments/list": 10,
+		"faucet/coins":              100,
+		"wallet/private-transfer":   20,
+		"wallet/decrypt-tx":         20,
+	}
 )
 
 const (
@@ -29,6 +52,28 @@ const (
 	WEBSOCKETS_INCREASE_KNOWN_NODE_SCORE_INTERVAL = 1 * time.Minute
 	WEBSOCKETS_CONCURRENT_NEW_CONENCTIONS         = 5
 	WEBSOCKETS_TIMEOUT                            = 15 * time.Second //seconds
+
+	NETWORK_BAN_SCORE_THRESHOLD       = uint64(100)
+	NETWORK_BAN_DURATION              = 24 * time.Hour
+	NETWORK_PEER_SCORE_DECAY_PER_HOUR = uint64(10)
+	NETWORK_PENALTY_INVALID_BLOCK     = uint64(50)
+	NETWORK_PENALTY_INVALID_FORK      = uint64(10)
+	NETWORK_PENALTY_INVALID_TX        = uint64(20)
+	NETWORK_PENALTY_MALFORMED_MESSAGE = uint64(10)
+	NETWORK_PENALTY_SLOW_RESPONSE     = uint64(5)
+
+	NETWORK_KNOWN_NODES_SCORE_DECAY_PER_HOUR = 5                   //the score of a stored known node decays towards 0 while it is not seen
+	NETWORK_KNOWN_NODES_MAX_AGE              = 14 * 24 * time.Hour //stored known nodes not seen for longer are forgotten
+
+	API_RATE_BURST_SECONDS  = 10 //the bucket holds the tokens of 10 seconds
+	API_RATE_PURGE_INTERVAL = 1 * time.Minute
+
+	NETWORK_SUBSCRIPTION_STREAMS_MAX   = int64(500)
+	NETWORK_SUBSCRIPTION_STREAM_BUFFER = 100
+	NETWORK_WEBHOOKS_MAX               = 100
+	NETWORK_WEBHOOK_QUEUE              = 1000
+	NETWORK_WEBHOOK_RETRIES            = 5
+	NETWORK_WEBHOOK_RETRY_INTERVAL     = 1 * time.Second //doubled after every retry
 )
 
 func InitConfig() (err error) {
@@ -67,6 +112,37 @@ func InitConfig() (err error) {
 		STATIC_FILES["/static/challenge/"] = "../../../static/challenge"
 	}
 
+	if arguments.Arguments["--api-rate-limit"] != nil {
+		if API_RATE_LIMIT, err = strconv.ParseFloat(arguments.Arguments["--api-rate-limit"].(string), 64); err != nil {
+			return
+		}
+	}
+
+	if arguments.Arguments["--api-rate-limit-auth"] != nil {
+		if API_RATE_LIMIT_AUTH, err = strconv.ParseFloat(arguments.Arguments["--api-rate-limit-auth"].(string), 64); err != nil {
+			return
+		}
+	}
+
+	if arguments.Arguments["--api-rate-limit-peer"] != nil {
+		if API_RATE_LIMIT_PEER, err = strconv.ParseFloat(arguments.Arguments["--api-rate-limit-peer"].(string), 64); err != nil {
+			return
+		}
+	}
+
+	if arguments.Arguments["--api-rate-costs"] != nil {
+		costs := map[string]float64{}
+		if err = json.Unmarshal([]byte(arguments.Arguments["--api-rate-costs"].(string)), &costs); err != nil {
+			return
+		}
+		for method, cost := range costs {
+			if cost < 0 {
+				return errors.New("Rate limit cost can not be negative")
+			}
+			API_RATE_COSTS[method] = cost
+		}
+	}
+
 	if err = network_config_auth.InitConfig(); err != nil {
 		return
 	}
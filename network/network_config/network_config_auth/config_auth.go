@@ -1,32 +1,202 @@
 package network_config_auth
 
 import (
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
 	"pandora-pay/config/arguments"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"strings"
 )
 
+type Role string
+
+const (
+	ROLE_WALLET_READ  Role = "wallet-read"  //read the wallet addresses and balances
+	ROLE_WALLET_SPEND Role = "wallet-spend" //create transfers and change the wallet addresses
+	ROLE_DELEGATOR    Role = "delegator"    //notify the delegator node
+	ROLE_ADMIN        Role = "admin"        //all the methods
+)
+
+var ROLES = []Role{ROLE_WALLET_READ, ROLE_WALLET_SPEND, ROLE_DELEGATOR, ROLE_ADMIN}
+
+// ConfigAuth is a credential. Only the salted hash of the secret is stored
 type ConfigAuth struct {
-	Username string `json:"user" msgpack:"user"`
-	Password string `json:"pass"  msgpack:"pass"`
+	Username string         `json:"user" msgpack:"user"`
+	Salt     helpers.Base64 `json:"salt" msgpack:"salt"`
+	Hash     helpers.Base64 `json:"hash" msgpack:"hash"`
+	Roles    []Role         `json:"roles" msgpack:"roles"`
 }
 
 var (
+	CONFIG_AUTH_FILE       string
 	CONFIG_AUTH_USERS_LIST []*ConfigAuth
 	CONFIG_AUTH_USERS_MAP  map[string]*ConfigAuth
 )
 
-func InitConfig() (err error) {
+func HashSecret(salt []byte, secret string) []byte {
+	return cryptography.SHA3(append(helpers.CloneBytes(salt), secret...))
+}
 
-	if str := arguments.Arguments["--auth-users"]; str != nil {
-		if err = json.Unmarshal([]byte(str.(string)), &CONFIG_AUTH_USERS_LIST); err != nil {
-			return
+func (auth *ConfigAuth) CheckSecret(secret string) bool {
+	return subtle.ConstantTimeCompare(HashSecret(auth.Salt, secret), auth.Hash) == 1
+}
+
+// HasRole is safe to be called on a nil credential
+func (auth *ConfigAuth) HasRole(role Role) bool {
+	if auth == nil {
+		return false
+	}
+	for _, it := range auth.Roles {
+		if it == role || it == ROLE_ADMIN {
+			return true
+		}
+	}
+	return false
+}
+
+func (auth *ConfigAuth) validate() error {
+	if auth.Username == "" || strings.Contains(auth.Username, ":") {
+		return errors.New("Auth user is invalid")
+	}
+	if len(auth.Salt) == 0 || len(auth.Hash) != cryptography.HashSize {
+		return fmt.Errorf("Auth user %s has an invalid salt or hash", auth.Username)
+	}
+	for _, role := range auth.Roles {
+		found := false
+		for _, it := range ROLES {
+			if role == it {
+				found = true
+			}
 		}
+		if !found {
+			return fmt.Errorf("Auth user %s has an invalid role %s", auth.Username, role)
+		}
+	}
+	return nil
+}
+
+// Authenticate returns the credential of the user if the secret is correct
+func Authenticate(user, secret string) *ConfigAuth {
+	auth := CONFIG_AUTH_USERS_MAP[user]
+	if auth == nil || !auth.CheckSecret(secret) {
+		return nil
+	}
+	return auth
+}
+
+// AuthenticateBearer checks the "Authorization: Bearer user:secret" header. An empty header returns no credential and no error
+func AuthenticateBearer(header string) (*ConfigAuth, error) {
+	if header == "" {
+		return nil, nil
+	}
+
+	token := strings.TrimPrefix(header, "Bearer ")
+	if token == header {
+		return nil, errors.New("Authorization must be a Bearer token")
+	}
+
+	user, secret, found := strings.Cut(token, ":")
+	if !found {
+		return nil, errors.New("Bearer token is invalid")
+	}
+
+	auth := Authenticate(user, secret)
+	if auth == nil {
+		return nil, errors.New("Invalid User or Password")
 	}
+	return auth, nil
+}
 
+// NewConfigAuth creates a credential with a random secret which is returned only once
+func NewConfigAuth(user string, roles []Role) (auth *ConfigAuth, secret string, err error) {
+	secret = base64.RawURLEncoding.EncodeToString(cryptography.RandomHash())
+	salt := cryptography.RandomHash()
+	auth = &ConfigAuth{user, salt, HashSecret(salt, secret), roles}
+	if err = auth.validate(); err != nil {
+		return nil, "", err
+	}
+	return
+}
+
+func saveConfigFile() error {
+	data, err := json.MarshalIndent(CONFIG_AUTH_USERS_LIST, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(CONFIG_AUTH_FILE, data, 0600)
+}
+
+func createNewKey(str string) (err error) {
+
+	if CONFIG_AUTH_FILE == "" {
+		return errors.New("--auth-new-key requires --auth-file")
+	}
+
+	args := &struct {
+		Username string `json:"user"`
+		Roles    []Role `json:"roles"`
+	}{}
+	if err = json.Unmarshal([]byte(str), args); err != nil {
+		return
+	}
+	if CONFIG_AUTH_USERS_MAP[args.Username] != nil {
+		return errors.New("Auth user already exists")
+	}
+
+	auth, secret, err := NewConfigAuth(args.Username, args.Roles)
+	if err != nil {
+		return
+	}
+
+	CONFIG_AUTH_USERS_LIST = append(CONFIG_AUTH_USERS_LIST, auth)
+	CONFIG_AUTH_USERS_MAP[auth.Username] = auth
+	if err = saveConfigFile(); err != nil {
+		return
+	}
+
+	fmt.Println("API key created. It will not be shown again:", auth.Username+":"+secret)
+	return
+}
+
+func InitConfig() (err error) {
+
+	CONFIG_AUTH_USERS_LIST = []*ConfigAuth{}
 	CONFIG_AUTH_USERS_MAP = map[string]*ConfigAuth{}
+
+	if str := arguments.Arguments["--auth-file"]; str != nil {
+		CONFIG_AUTH_FILE = str.(string)
+
+		//the file is created by --auth-new-key
+		var data []byte
+		if data, err = os.ReadFile(CONFIG_AUTH_FILE); err != nil {
+			if !os.IsNotExist(err) {
+				return
+			}
+			err = nil
+		} else if len(data) > 0 {
+			if err = json.Unmarshal(data, &CONFIG_AUTH_USERS_LIST); err != nil {
+				return
+			}
+		}
+	}
+
 	for _, auth := range CONFIG_AUTH_USERS_LIST {
+		if err = auth.validate(); err != nil {
+			return
+		}
 		CONFIG_AUTH_USERS_MAP[auth.Username] = auth
 	}
 
+	if str := arguments.Arguments["--auth-new-key"]; str != nil {
+		if err = createNewKey(str.(string)); err != nil {
+			return
+		}
+	}
+
 	return
 }
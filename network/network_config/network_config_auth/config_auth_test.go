@@ -0,0 +1,45 @@
+package network_config_auth
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAuthenticateBearer(t *testing.T) {
+
+	monitoring, secret, err := NewConfigAuth("monitoring", []Role{ROLE_WALLET_READ})
+	assert.Nil(t, err)
+	admin, adminSecret, err := NewConfigAuth("admin", []Role{ROLE_ADMIN})
+	assert.Nil(t, err)
+
+	_, _, err = NewConfigAuth("user:name", nil)
+	assert.NotNil(t, err)
+	_, _, err = NewConfigAuth("user", []Role{"root"})
+	assert.NotNil(t, err)
+
+	CONFIG_AUTH_USERS_MAP = map[string]*ConfigAuth{monitoring.Username: monitoring, admin.Username: admin}
+
+	auth, err := AuthenticateBearer("")
+	assert.Nil(t, err)
+	assert.Nil(t, auth)
+	assert.False(t, auth.HasRole(ROLE_WALLET_READ))
+
+	auth, err = AuthenticateBearer("Bearer monitoring:" + secret)
+	assert.Nil(t, err)
+	assert.Equal(t, monitoring, auth)
+	assert.True(t, auth.HasRole(ROLE_WALLET_READ))
+	assert.False(t, auth.HasRole(ROLE_WALLET_SPEND))
+	assert.False(t, auth.HasRole(ROLE_ADMIN))
+
+	auth, err = AuthenticateBearer("Bearer admin:" + adminSecret)
+	assert.Nil(t, err)
+	assert.True(t, auth.HasRole(ROLE_WALLET_SPEND))
+	assert.True(t, auth.HasRole(ROLE_DELEGATOR))
+
+	for _, header := range []string{"Bearer monitoring:" + adminSecret, "Bearer admin:" + secret, "Bearer monitoring", "Basic monitoring:" + secret, "Bearer missing:" + secret} {
+		auth, err = AuthenticateBearer(header)
+		assert.NotNil(t, err, header)
+		assert.Nil(t, auth)
+	}
+
+}
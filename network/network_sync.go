@@ -72,6 +72,7 @@ func (this *networkType) continuouslyConnectingNewPeers() {
 							//gui.GUI.Error("error connecting", knownNode.URL, err)
 
 							if err.Error() != "Already connected" {
+								known_nodes.KnownNodes.MarkKnownNodeFailed(knownNode)
 								known_nodes.KnownNodes.DecreaseKnownNodeScore(knownNode, -20, false)
 							}
 
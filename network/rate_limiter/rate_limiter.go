@@ -0,0 +1,133 @@
+package rate_limiter
+
+import (
+	"encoding/json"
+	"math"
+	"pandora-pay/helpers/metrics"
+	"pandora-pay/network/banned_nodes"
+	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
+	"sync"
+	"time"
+)
+
+var RateLimitedTotal = metrics.NewCounter("pandora_api_rate_limited_total", "Number of API requests rejected by the rate limiter")
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiterType is a token bucket limiter. Every key has its own bucket
+type RateLimiterType struct {
+	buckets   map[string]*bucket
+	lastPurge time.Time
+	lock      *sync.Mutex
+}
+
+// RateLimitedError is returned when a bucket doesn't have enough tokens. It is serialized as JSON to be returned by all transports
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (err *RateLimitedError) Error() string {
+	data, _ := json.Marshal(&struct {
+		Error      string `json:"error"`
+		RetryAfter int64  `json:"retryAfter"` //milliseconds
+	}{"Rate limited", err.RetryAfter.Milliseconds()})
+	return string(data)
+}
+
+// RetryAfterSeconds is used for the Retry-After header
+func (err *RateLimitedError) RetryAfterSeconds() int64 {
+	return int64(math.Ceil(err.RetryAfter.Seconds()))
+}
+
+//buckets which got refilled are removed
+func (this *RateLimiterType) purge(now time.Time) {
+	for key, b := range this.buckets {
+		if now.Sub(b.last) >= network_config.API_RATE_BURST_SECONDS*time.Second {
+			delete(this.buckets, key)
+		}
+	}
+	this.lastPurge = now
+}
+
+// Allow consumes the cost from the bucket of the key. A rate of 0 disables the limiter
+func (this *RateLimiterType) Allow(key string, cost, rate float64, now time.Time) (bool, time.Duration) {
+
+	if rate <= 0 || cost <= 0 {
+		return true, 0
+	}
+
+	burst := rate * network_config.API_RATE_BURST_SECONDS
+	cost = math.Min(cost, burst) //otherwise the method could never be called
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if now.Sub(this.lastPurge) >= network_config.API_RATE_PURGE_INTERVAL {
+		this.purge(now)
+	}
+
+	b := this.buckets[key]
+	if b == nil {
+		b = &bucket{burst, now}
+		this.buckets[key] = b
+	} else if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*rate)
+		b.last = now
+	}
+
+	if b.tokens < cost {
+		return false, time.Duration((cost - b.tokens) / rate * float64(time.Second))
+	}
+
+	b.tokens -= cost
+	return true, 0
+}
+
+// AllowRequest checks the request of a method. Authenticated users are limited by their username, the others by their ip
+func (this *RateLimiterType) AllowRequest(method, remoteAddr string, auth *network_config_auth.ConfigAuth) error {
+
+	cost, found := network_config.API_RATE_COSTS[method]
+	if !found {
+		cost = 1
+	}
+
+	key, rate := "ip:"+banned_nodes.GetHost(remoteAddr), network_config.API_RATE_LIMIT
+	if auth != nil {
+		key, rate = "user:"+auth.Username, network_config.API_RATE_LIMIT_AUTH
+	}
+
+	if allowed, retryAfter := this.Allow(key, cost, rate, time.Now()); !allowed {
+		RateLimitedTotal.Inc()
+		return &RateLimitedError{retryAfter}
+	}
+	return nil
+}
+
+// AllowPeerRequest checks the request of a method sent by a peer the node connected to. The peers have their own bucket by ip, so they don't consume the tokens of the API users
+func (this *RateLimiterType) AllowPeerRequest(method, remoteAddr string) error {
+
+	cost, found := network_config.API_RATE_COSTS[method]
+	if !found {
+		cost = 1
+	}
+
+	if allowed, retryAfter := this.Allow("peer:"+banned_nodes.GetHost(remoteAddr), cost, network_config.API_RATE_LIMIT_PEER, time.Now()); !allowed {
+		RateLimitedTotal.Inc()
+		return &RateLimitedError{retryAfter}
+	}
+	return nil
+}
+
+func NewRateLimiter() *RateLimiterType {
+	return &RateLimiterType{
+		map[string]*bucket{},
+		time.Now(),
+		&sync.Mutex{},
+	}
+}
+
+var RateLimiter = NewRateLimiter()
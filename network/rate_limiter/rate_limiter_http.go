@@ -0,0 +1,77 @@
+package rate_limiter
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
+	"strconv"
+)
+
+// WriteHttpError answers with 429 and a Retry-After header when the request was rate limited
+func WriteHttpError(w http.ResponseWriter, err error) {
+	rateErr, ok := err.(*RateLimitedError)
+	if !ok {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.FormatInt(rateErr.RetryAfterSeconds(), 10))
+	w.WriteHeader(http.StatusTooManyRequests)
+	w.Write([]byte(rateErr.Error()))
+}
+
+// the rpc server accepts the same method in multiple forms, so the cost is found by comparing the normalized methods
+func getRouteByMethod(method string, normalizeMethod func(string) string) string {
+
+	if _, found := network_config.API_RATE_COSTS[method]; found {
+		return method
+	}
+
+	normalized := normalizeMethod(method)
+	for route := range network_config.API_RATE_COSTS {
+		if normalizeMethod(route) == normalized {
+			return route
+		}
+	}
+
+	return method
+}
+
+// RPCHandler limits the JSON RPC requests by their method before they are served. The method is normalized by normalizeMethod as the rpc server does
+func RPCHandler(next http.Handler, normalizeMethod func(string) string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		req.Body = http.MaxBytesReader(w, req.Body, int64(network_config.WEBSOCKETS_MAX_READ))
+
+		if network_config.API_RATE_LIMIT <= 0 && network_config.API_RATE_LIMIT_AUTH <= 0 {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(data))
+
+		//invalid requests are answered by the rpc server
+		request := &struct {
+			Method string `json:"method"`
+		}{}
+		json.Unmarshal(data, request)
+
+		//invalid credentials are rejected by the rpc methods
+		auth, _ := network_config_auth.AuthenticateBearer(req.Header.Get("Authorization"))
+
+		if err = RateLimiter.AllowRequest(getRouteByMethod(request.Method, normalizeMethod), req.RemoteAddr, auth); err != nil {
+			WriteHttpError(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,54 @@
+package rate_limiter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/network/network_config"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAllow(t *testing.T) {
+
+	limiter := NewRateLimiter()
+	now := time.Now()
+	burst := 10.0 * network_config.API_RATE_BURST_SECONDS
+
+	allowed, _ := limiter.Allow("ip:1", burst, 10, now)
+	assert.True(t, allowed)
+
+	allowed, retryAfter := limiter.Allow("ip:1", 5, 10, now)
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	allowed, _ = limiter.Allow("ip:2", 5, 10, now)
+	assert.True(t, allowed, "the buckets are independent")
+
+	allowed, _ = limiter.Allow("ip:1", 5, 10, now.Add(500*time.Millisecond))
+	assert.True(t, allowed)
+
+	allowed, _ = limiter.Allow("ip:1", 1000*burst, 0, now)
+	assert.True(t, allowed, "a rate of 0 disables the limiter")
+
+	allowed, _ = limiter.Allow("ip:3", 1000*burst, 10, now)
+	assert.True(t, allowed, "the cost is capped to the burst")
+
+	limiter.purge(now.Add((network_config.API_RATE_BURST_SECONDS + 1) * time.Second))
+	assert.Empty(t, limiter.buckets)
+
+	err := &RateLimitedError{1500 * time.Millisecond}
+	assert.Equal(t, `{"error":"Rate limited","retryAfter":1500}`, err.Error())
+	assert.Equal(t, int64(2), err.RetryAfterSeconds())
+}
+
+func TestGetRouteByMethod(t *testing.T) {
+
+	normalizeMethod := func(method string) string {
+		return strings.ToLower(strings.NewReplacer("-", "", "/", "").Replace(method))
+	}
+
+	assert.Equal(t, "block-complete", getRouteByMethod("block-complete", normalizeMethod))
+	assert.Equal(t, "block-complete", getRouteByMethod("blockComplete", normalizeMethod))
+	assert.Equal(t, "block-complete", getRouteByMethod("block/complete", normalizeMethod))
+	assert.Equal(t, "unknown", getRouteByMethod("unknown", normalizeMethod))
+}
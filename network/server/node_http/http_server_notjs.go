@@ -11,23 +11,29 @@ import (
 	"net/http"
 	"net/url"
 	"pandora-pay/blockchain"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/mempool"
+	"pandora-pay/network/api_code/api_code_types"
 	"pandora-pay/network/api_implementation/api_common"
 	"pandora-pay/network/api_implementation/api_http"
 	"pandora-pay/network/api_implementation/api_websockets"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
+	"pandora-pay/network/rate_limiter"
 	"pandora-pay/network/server/node_http_rpc"
 	"pandora-pay/network/websocks"
 	"pandora-pay/settings"
 	"pandora-pay/wallet"
+	"strings"
+	"time"
 )
 
 type httpServerType struct {
 	Api           *api_http.API
 	ApiWebsockets *api_websockets.APIWebsockets
 	ApiStore      *api_common.APIStore
-	GetMap        map[string]func(values url.Values) (any, error)
-	PostMap       map[string]func(values io.ReadCloser) (any, error)
+	GetMap        map[string]func(values url.Values, auth *network_config_auth.ConfigAuth) (any, error)
+	PostMap       map[string]func(values io.ReadCloser, auth *network_config_auth.ConfigAuth) (any, error)
 }
 
 var HttpServer *httpServerType
@@ -40,9 +46,19 @@ func (this *httpServerType) get(w http.ResponseWriter, req *http.Request) {
 		}
 	}()
 
-	var err error
 	var output interface{}
 
+	auth, err := network_config_auth.AuthenticateBearer(req.Header.Get("Authorization"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err = rate_limiter.RateLimiter.AllowRequest(strings.TrimPrefix(req.URL.Path, "/"), req.RemoteAddr, auth); err != nil {
+		rate_limiter.WriteHttpError(w, err)
+		return
+	}
+
 	callback := this.GetMap[req.URL.Path]
 	if callback != nil {
 
@@ -51,7 +67,9 @@ func (this *httpServerType) get(w http.ResponseWriter, req *http.Request) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		output, err = callback(args)
+		start := time.Now()
+		output, err = callback(args, auth)
+		api_code_types.APIRequestDuration.WithLabelValue(strings.TrimPrefix(req.URL.Path, "/")).Observe(time.Since(start).Seconds())
 	} else {
 		err = errors.New("Unknown request")
 	}
@@ -86,12 +104,24 @@ func (this *httpServerType) post(w http.ResponseWriter, req *http.Request) {
 		}
 	}()
 
-	var err error
 	var output interface{}
 
+	auth, err := network_config_auth.AuthenticateBearer(req.Header.Get("Authorization"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err = rate_limiter.RateLimiter.AllowRequest(strings.TrimPrefix(req.URL.Path, "/"), req.RemoteAddr, auth); err != nil {
+		rate_limiter.WriteHttpError(w, err)
+		return
+	}
+
 	callback := this.PostMap[req.URL.Path]
 	if callback != nil {
-		output, err = callback(req.Body)
+		start := time.Now()
+		output, err = callback(req.Body, auth)
+		api_code_types.APIRequestDuration.WithLabelValue(strings.TrimPrefix(req.URL.Path, "/")).Observe(time.Since(start).Seconds())
 	} else {
 		err = errors.New("Unknown request")
 	}
@@ -123,6 +153,8 @@ func (this *httpServerType) GetHttpHandler() *http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/ws", websocks.Websockets.HandleUpgradeConnection)
+	mux.HandleFunc("/metrics", metrics.Handler)
+	mux.HandleFunc("/sub/stream", websocks.Websockets.HandleSubscriptionStream)
 
 	for key, filepath := range network_config.STATIC_FILES {
 		fs := http.FileServer(http.Dir(filepath))
@@ -155,13 +187,16 @@ func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, me
 	api := api_http.NewAPI(apiStore, apiCommon, chain)
 
 	websocks.NewWebsockets(chain, mempool, settings, apiWebsockets.GetMap)
+	if err = websocks.InitializeWebhooks(); err != nil {
+		return err
+	}
 
 	HttpServer = &httpServerType{
 		api,
 		apiWebsockets,
 		apiStore,
-		make(map[string]func(values url.Values) (any, error)),
-		make(map[string]func(values io.ReadCloser) (any, error)),
+		make(map[string]func(values url.Values, auth *network_config_auth.ConfigAuth) (any, error)),
+		make(map[string]func(values io.ReadCloser, auth *network_config_auth.ConfigAuth) (any, error)),
 	}
 
 	if err = node_http_rpc.InitializeRPC(apiCommon); err != nil {
@@ -1,21 +1,37 @@
 package node_http_rpc
 
 import (
+	"context"
 	"github.com/gorilla/rpc"
 	"net/http"
+	"pandora-pay/network/api_code/api_code_types"
 	"pandora-pay/network/api_implementation/api_common"
+	"pandora-pay/network/rate_limiter"
+	"time"
 )
 
+type rpcStartKey struct{}
+
 func InitializeRPC(apiCommon *api_common.APICommon) (err error) {
 
 	s := rpc.NewServer()
 
 	s.RegisterCodec(NewUpCodec(), "application/json")
-	if err = s.RegisterService(apiCommon, "api"); err != nil {
+	if err = s.RegisterService(&rpcService{apiCommon}, "api"); err != nil {
 		return
 	}
 
-	http.Handle("/rpc/api/v1", s)
+	//the method is known only after the request was decoded by the codec, so the start is kept in the context of the request
+	s.RegisterInterceptFunc(func(i *rpc.RequestInfo) *http.Request {
+		return i.Request.WithContext(context.WithValue(i.Request.Context(), rpcStartKey{}, time.Now()))
+	})
+	s.RegisterAfterFunc(func(i *rpc.RequestInfo) {
+		if start, ok := i.Request.Context().Value(rpcStartKey{}).(time.Time); ok {
+			api_code_types.APIRequestDuration.WithLabelValue(i.Method).Observe(time.Since(start).Seconds())
+		}
+	})
+
+	http.Handle("/rpc/api/v1", rate_limiter.RPCHandler(s, NormalizeMethod))
 
 	return
 }
@@ -0,0 +1,85 @@
+package node_http_rpc
+
+import (
+	"net/http"
+	"pandora-pay/network/api_implementation/api_common"
+	"pandora-pay/network/network_config/network_config_auth"
+)
+
+// rpcService exposes the public methods of APICommon and the authenticated methods which require a bearer token
+type rpcService struct {
+	*api_common.APICommon
+}
+
+func authorize(r *http.Request, role network_config_auth.Role) bool {
+	auth, err := network_config_auth.AuthenticateBearer(r.Header.Get("Authorization"))
+	return err == nil && auth.HasRole(role)
+}
+
+func (this *rpcService) MempoolRemoveTx(r *http.Request, args *api_common.APIMempoolRemoveTxRequest, reply *api_common.APIMempoolRemoveTxReply) error {
+	return this.APICommon.MempoolRemoveTx(r, args, reply, authorize(r, network_config_auth.ROLE_ADMIN))
+}
+
+func (this *rpcService) NetworkBans(r *http.Request, args *struct{}, reply *api_common.APINetworkBansReply) error {
+	return this.APICommon.GetNetworkBans(r, args, reply, authorize(r, network_config_auth.ROLE_ADMIN))
+}
+
+func (this *rpcService) NetworkBansAdd(r *http.Request, args *api_common.APINetworkBanAddRequest, reply *api_common.APINetworkBanAddReply) error {
+	return this.APICommon.NetworkBanAdd(r, args, reply, authorize(r, network_config_auth.ROLE_ADMIN))
+}
+
+func (this *rpcService) NetworkBansRemove(r *http.Request, args *api_common.APINetworkBanRemoveRequest, reply *api_common.APINetworkBanRemoveReply) error {
+	return this.APICommon.NetworkBanRemove(r, args, reply, authorize(r, network_config_auth.ROLE_ADMIN))
+}
+
+func (this *rpcService) NetworkKnownNodes(r *http.Request, args *struct{}, reply *api_common.APINetworkKnownNodesReply) error {
+	return this.APICommon.GetNetworkKnownNodes(r, args, reply, authorize(r, network_config_auth.ROLE_ADMIN))
+}
+
+func (this *rpcService) NetworkKnownNodesAdd(r *http.Request, args *api_common.APINetworkKnownNodeAddRequest, reply *api_common.APINetworkKnownNodeAddReply) error {
+	return this.APICommon.NetworkKnownNodeAdd(r, args, reply, authorize(r, network_config_auth.ROLE_ADMIN))
+}
+
+func (this *rpcService) NetworkKnownNodesRemove(r *http.Request, args *api_common.APINetworkKnownNodeRemoveRequest, reply *api_common.APINetworkKnownNodeRemoveReply) error {
+	return this.APICommon.NetworkKnownNodeRemove(r, args, reply, authorize(r, network_config_auth.ROLE_ADMIN))
+}
+
+func (this *rpcService) SubWebhooks(r *http.Request, args *struct{}, reply *api_common.APISubWebhooksReply) error {
+	return this.APICommon.GetSubWebhooks(r, args, reply, authorize(r, network_config_auth.ROLE_ADMIN))
+}
+
+func (this *rpcService) SubWebhookAdd(r *http.Request, args *api_common.APISubWebhookAddRequest, reply *api_common.APISubWebhookAddReply) error {
+	return this.APICommon.SubWebhookAdd(r, args, reply, authorize(r, network_config_auth.ROLE_ADMIN))
+}
+
+func (this *rpcService) SubWebhookRemove(r *http.Request, args *api_common.APISubWebhookRemoveRequest, reply *api_common.APISubWebhookRemoveReply) error {
+	return this.APICommon.SubWebhookRemove(r, args, reply, authorize(r, network_config_auth.ROLE_ADMIN))
+}
+
+func (this *rpcService) WalletGetAddresses(r *http.Request, args *struct{}, reply *api_common.APIWalletGetAccountsReply) error {
+	return this.APICommon.GetWalletAddresses(r, args, reply, authorize(r, network_config_auth.ROLE_WALLET_READ))
+}
+
+func (this *rpcService) WalletGenerateAddress(r *http.Request, args *api_common.APIWalletGenerateAddressRequest, reply *api_common.APIWalletGenerateAddressReply) error {
+	return this.APICommon.GetWalletGenerateAddress(r, args, reply, authorize(r, network_config_auth.ROLE_WALLET_SPEND))
+}
+
+func (this *rpcService) WalletCreateAddress(r *http.Request, args *api_common.APIWalletCreateAddressRequest, reply *api_common.APIWalletCreateAddressReply) error {
+	return this.APICommon.GetWalletCreateAddress(r, args, reply, authorize(r, network_config_auth.ROLE_WALLET_SPEND))
+}
+
+func (this *rpcService) WalletDeleteAddress(r *http.Request, args *api_common.APIWalletDeleteAddressRequest, reply *api_common.APIWalletDeleteAddressReply) error {
+	return this.APICommon.GetWalletDeleteAddress(r, args, reply, authorize(r, network_config_auth.ROLE_WALLET_SPEND))
+}
+
+func (this *rpcService) WalletGetBalances(r *http.Request, args *api_common.APIWalletGetBalanceRequest, reply *api_common.APIWalletGetBalancesReply) error {
+	return this.APICommon.GetWalletBalances(r, args, reply, authorize(r, network_config_auth.ROLE_WALLET_READ))
+}
+
+func (this *rpcService) WalletDecryptTx(r *http.Request, args *api_common.APIWalletDecryptTxRequest, reply *api_common.APIWalletDecryptTxReply) error {
+	return this.APICommon.GetWalletDecryptTx(r, args, reply, authorize(r, network_config_auth.ROLE_WALLET_READ))
+}
+
+func (this *rpcService) WalletPrivateTransfer(r *http.Request, args *api_common.APIWalletPrivateTransferRequest, reply *api_common.APIWalletPrivateTransferReply) error {
+	return this.APICommon.WalletPrivateTransfer(r, args, reply, authorize(r, network_config_auth.ROLE_WALLET_SPEND))
+}
@@ -51,27 +51,35 @@ type UpCodecRequest struct {
 // on to the calling rpc server.
 func (c *UpCodecRequest) Method() (string, error) {
 	m, err := c.CodecRequest.Method()
-	if len(m) > 1 && err == nil {
+	if err == nil {
+		return NormalizeMethod(m), err
+	}
+	return m, err
+}
 
-		final := make([]byte, len(m))
-		c := 0
-		for i := 0; i < len(m); i++ {
-			if m[i] == '/' || m[i] == '-' {
-				final[c] = m[i+1] - 32
-				c += 1
-				i += 1
-				continue
-			} else if i == 0 {
-				final[c] = m[0] - 32
-				c += 1
-			} else {
-				final[c] = m[i]
-				c += 1
-			}
-		}
+// NormalizeMethod converts a method like "fee-estimate", "fee/estimate" or "feeEstimate" into the service method "api.FeeEstimate"
+func NormalizeMethod(m string) string {
 
-		upMethod := "api." + string(final[:c])
-		return upMethod, err
+	if len(m) <= 1 {
+		return m
 	}
-	return m, err
+
+	final := make([]byte, len(m))
+	c := 0
+	for i := 0; i < len(m); i++ {
+		if (m[i] == '/' || m[i] == '-') && i+1 < len(m) {
+			final[c] = m[i+1] - 32
+			c += 1
+			i += 1
+			continue
+		} else if i == 0 {
+			final[c] = m[0] - 32
+			c += 1
+		} else {
+			final[c] = m[i]
+			c += 1
+		}
+	}
+
+	return "api." + string(final[:c])
 }
@@ -5,12 +5,17 @@ import (
 	"errors"
 	"github.com/blang/semver/v4"
 	"github.com/tevino/abool"
+	"pandora-pay/gui"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/helpers/msgpack"
 	"pandora-pay/helpers/recovery"
+	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/known_nodes/known_node"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
+	"pandora-pay/network/rate_limiter"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/network/websocks/websock"
 	"sync"
@@ -29,7 +34,7 @@ const (
 var uuidGenerator uint32 //use atomic
 
 type AdvancedConnection struct {
-	Authenticated            *abool.AtomicBool
+	Auth                     *generics.Value[*network_config_auth.ConfigAuth] //credential of the logged user
 	UUID                     advanced_connection_types.UUID
 	Conn                     *websock.Conn
 	Handshake                *ConnectionHandshake
@@ -51,6 +56,10 @@ type AdvancedConnection struct {
 	onIncreaseKnownNodeScore func(knownNode *known_node.KnownNodeScored, delta int32, isServer bool) bool
 }
 
+func (c *AdvancedConnection) GetUUID() advanced_connection_types.UUID {
+	return c.UUID
+}
+
 func (c *AdvancedConnection) GetTimeout() time.Duration {
 	return network_config.WEBSOCKETS_TIMEOUT
 }
@@ -64,6 +73,14 @@ func (c *AdvancedConnection) Close() error {
 	return nil
 }
 
+// Penalize increases the misbehavior score of the remote host and closes the connection when the host gets banned
+func (c *AdvancedConnection) Penalize(penalty uint64, message string) {
+	if banned_nodes.BannedNodes.Penalize(c.RemoteAddr, penalty, message) {
+		gui.GUI.Warning("Peer banned", c.RemoteAddr, message)
+		c.Close()
+	}
+}
+
 func (c *AdvancedConnection) connSendMessage(message any, ctxDuration time.Duration) error {
 
 	data, err := msgpack.Marshal(message)
@@ -138,6 +155,9 @@ func (c *AdvancedConnection) sendNowAwait(name []byte, data []byte, reply bool,
 	case <-c.Closed:
 		return &advanced_connection_types.AdvancedConnectionReply{nil, errors.New("Timeout Closed"), true}
 	case <-ctx.Done():
+		if ctxParent == nil || ctxParent.Err() == nil { //the request was not canceled by us
+			c.Penalize(network_config.NETWORK_PENALTY_SLOW_RESPONSE, "Slow response")
+		}
 		return &advanced_connection_types.AdvancedConnectionReply{nil, errors.New("Timeout"), true}
 	}
 }
@@ -190,7 +210,18 @@ func (c *AdvancedConnection) get(message *advanced_connection_types.AdvancedConn
 
 	route := string(message.Name)
 	if callback := c.getMap[route]; callback != nil {
+		//the handshake of the incoming connections can't be verified, so they are limited by ip or by the authenticated user. The peers the node connected to have their own budget
+		if c.ConnectionType {
+			err = rate_limiter.RateLimiter.AllowRequest(route, c.RemoteAddr, c.Auth.Load())
+		} else {
+			err = rate_limiter.RateLimiter.AllowPeerRequest(route, c.RemoteAddr)
+		}
+		if err != nil {
+			return
+		}
+		start := time.Now()
 		output, err = callback(c, message.Data)
+		api_code_types.APIRequestDuration.WithLabelValue(route).Observe(time.Since(start).Seconds())
 	} else {
 		err = errors.New("Unknown request")
 	}
@@ -269,7 +300,9 @@ func (c *AdvancedConnection) ReadPump() {
 
 		recovery.SafeGo(func() {
 			message := &advanced_connection_types.AdvancedConnectionMessage{}
-			if err = msgpack.Unmarshal(read, message); err == nil && message != nil {
+			if err := msgpack.Unmarshal(read, message); err != nil {
+				c.Penalize(network_config.NETWORK_PENALTY_MALFORMED_MESSAGE, "Malformed message")
+			} else if message != nil {
 				c.processRead(message)
 			}
 		})
@@ -329,17 +362,21 @@ func (c *AdvancedConnection) IncreaseKnownNodeScore() {
 
 }
 
-func NewAdvancedConnection(conn *websock.Conn, remoteAddr string, knownNode *known_node.KnownNodeScored, getMap map[string]func(conn *AdvancedConnection, values []byte) (any, error), connectionType bool, newSubscriptionCn, removeSubscriptionCn chan<- *SubscriptionNotification, onClosedConnection func(*AdvancedConnection), onIncreaseKnownNodeScore func(*known_node.KnownNodeScored, int32, bool) bool) (*AdvancedConnection, error) {
-
+// NewUUID generates a new unique UUID for a connection or a subscriber
+func NewUUID() advanced_connection_types.UUID {
 	//making sure u is not collided with UUID_ALL and UUID_SKIP_ALL
 	uuid := advanced_connection_types.UUID(atomic.AddUint32(&uuidGenerator, 1))
 	for uuid <= advanced_connection_types.UUID_SKIP_ALL {
 		uuid = advanced_connection_types.UUID(atomic.AddUint32(&uuidGenerator, 1))
 	}
+	return uuid
+}
+
+func NewAdvancedConnection(conn *websock.Conn, remoteAddr string, knownNode *known_node.KnownNodeScored, getMap map[string]func(conn *AdvancedConnection, values []byte) (any, error), connectionType bool, newSubscriptionCn, removeSubscriptionCn chan<- *SubscriptionNotification, onClosedConnection func(*AdvancedConnection), onIncreaseKnownNodeScore func(*known_node.KnownNodeScored, int32, bool) bool) (*AdvancedConnection, error) {
 
 	advancedConnection := &AdvancedConnection{
-		abool.New(),
-		uuid,
+		&generics.Value[*network_config_auth.ConfigAuth]{},
+		NewUUID(),
 		conn,
 		nil,
 		nil,
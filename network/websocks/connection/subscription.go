@@ -2,6 +2,8 @@ package connection
 
 import (
 	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"time"
 )
 
 type Subscription struct {
@@ -10,7 +12,14 @@ type Subscription struct {
 	ReturnType api_code_types.APIReturnType
 }
 
+// SubscriptionConn receives the notifications of the subscriptions. It is implemented by the websockets and by the HTTP subscribers
+type SubscriptionConn interface {
+	GetUUID() advanced_connection_types.UUID
+	Send(name []byte, data []byte, ctxDuration time.Duration) error
+	SendJSON(name []byte, data any, ctxDuration time.Duration) error
+}
+
 type SubscriptionNotification struct {
 	Subscription *Subscription
-	Conn         *AdvancedConnection
+	Conn         SubscriptionConn
 }
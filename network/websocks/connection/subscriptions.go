@@ -23,12 +23,16 @@ type Subscriptions struct {
 func checkSubscriptionLength(key []byte, subscriptionType api_code_types.SubscriptionType) error {
 	var length int
 	switch subscriptionType {
-	case api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT, api_code_types.SUBSCRIPTION_ACCOUNT, api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, api_code_types.SUBSCRIPTION_REGISTRATION:
+	case api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT, api_code_types.SUBSCRIPTION_ACCOUNT, api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, api_code_types.SUBSCRIPTION_REGISTRATION, api_code_types.SUBSCRIPTION_PENDING_STAKE:
 		length = cryptography.PublicKeySize
 	case api_code_types.SUBSCRIPTION_ASSET:
 		length = config_coins.ASSET_LENGTH
-	case api_code_types.SUBSCRIPTION_TRANSACTION:
+	case api_code_types.SUBSCRIPTION_TRANSACTION, api_code_types.SUBSCRIPTION_CONDITIONAL_PAYMENT:
 		length = cryptography.HashSize
+	case api_code_types.SUBSCRIPTION_CHAIN, api_code_types.SUBSCRIPTION_MEMPOOL, api_code_types.SUBSCRIPTION_REORG_ALERT:
+		length = 0
+	default:
+		return errors.New("Invalid subscription type")
 	}
 	if len(key) != length {
 		return errors.New("Key is invalid")
@@ -36,13 +40,19 @@ func checkSubscriptionLength(key []byte, subscriptionType api_code_types.Subscri
 	return nil
 }
 
-func (s *Subscriptions) AddSubscription(subscriptionType api_code_types.SubscriptionType, key []byte, returnType api_code_types.APIReturnType) error {
+// ValidateSubscription checks a subscription requested manually
+func ValidateSubscription(subscriptionType api_code_types.SubscriptionType, key []byte) error {
 
 	if subscriptionType == api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT || subscriptionType == api_code_types.SUBSCRIPTION_REGISTRATION {
 		return errors.New("These subscriptions are automatically. They can't be subsribed manually")
 	}
 
-	if err := checkSubscriptionLength(key, subscriptionType); err != nil {
+	return checkSubscriptionLength(key, subscriptionType)
+}
+
+func (s *Subscriptions) AddSubscription(subscriptionType api_code_types.SubscriptionType, key []byte, returnType api_code_types.APIReturnType) error {
+
+	if err := ValidateSubscription(subscriptionType, key); err != nil {
 		return err
 	}
 
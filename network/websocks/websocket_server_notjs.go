@@ -6,8 +6,10 @@ package websocks
 import (
 	"net/http"
 	"pandora-pay/helpers/recovery"
+	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/connected_nodes"
 	"pandora-pay/network/known_nodes"
+	"pandora-pay/network/known_nodes/known_node"
 	"pandora-pay/network/network_config"
 	"pandora-pay/network/websocks/websock"
 	"sync/atomic"
@@ -20,6 +22,11 @@ func (this *websocketsType) HandleUpgradeConnection(w http.ResponseWriter, r *ht
 		return
 	}
 
+	if banned_nodes.BannedNodes.IsBanned(r.RemoteAddr) {
+		http.Error(w, "Socket is banned", 403)
+		return
+	}
+
 	c, err := websock.Upgrade(w, r)
 	if err != nil {
 		return
@@ -31,7 +38,7 @@ func (this *websocketsType) HandleUpgradeConnection(w http.ResponseWriter, r *ht
 	}
 
 	if conn.Handshake.URL != "" {
-		conn.KnownNode, err = known_nodes.KnownNodes.AddKnownNode(conn.Handshake.URL, false)
+		conn.KnownNode, err = known_nodes.KnownNodes.AddKnownNode(conn.Handshake.URL, false, known_node.KNOWN_NODE_SOURCE_INCOMING)
 		if conn.KnownNode != nil {
 			recovery.SafeGo(conn.IncreaseKnownNodeScore)
 		}
@@ -219,6 +219,7 @@ func (this *websocketsType) InitializeConnection(conn *connection.AdvancedConnec
 
 	handshakeReceived := &connection.ConnectionHandshake{}
 	if err := msgpack.Unmarshal(out.Out, handshakeReceived); err != nil {
+		conn.Penalize(network_config.NETWORK_PENALTY_MALFORMED_MESSAGE, "Invalid handshake")
 		return errors.New("Handshake received was invalid")
 	}
 
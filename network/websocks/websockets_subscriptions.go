@@ -2,6 +2,8 @@ package websocks
 
 import (
 	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/data_storage/pending_stakes_list/pending_stakes"
+	"pandora-pay/cryptography"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/msgpack"
 	"pandora-pay/helpers/recovery"
@@ -11,30 +13,41 @@ import (
 	"pandora-pay/network/network_config"
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"strconv"
 )
 
 type WebsocketSubscriptions struct {
 	chain                             *blockchain.Blockchain
 	mempool                           *mempool.Mempool
-	websocketClosedCn                 chan *connection.AdvancedConnection
+	websocketClosedCn                 chan connection.SubscriptionConn
 	newSubscriptionCn                 chan *connection.SubscriptionNotification
 	removeSubscriptionCn              chan *connection.SubscriptionNotification
 	accountsSubscriptions             map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 	accountsTransactionsSubscriptions map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 	assetsSubscriptions               map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 	transactionsSubscriptions         map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	chainSubscriptions                map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	mempoolSubscriptions              map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	conditionalPaymentsSubscriptions  map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	pendingStakesSubscriptions        map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	reorgAlertsSubscriptions          map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 }
 
 func newWebsocketSubscriptions(chain *blockchain.Blockchain, mempool *mempool.Mempool) (subs *WebsocketSubscriptions) {
 
 	subs = &WebsocketSubscriptions{
-		chain, mempool, make(chan *connection.AdvancedConnection),
+		chain, mempool, make(chan connection.SubscriptionConn),
 		make(chan *connection.SubscriptionNotification),
 		make(chan *connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 	}
 
 	if network_config.NETWORK_ENABLE_SUBSCRIPTIONS {
@@ -93,6 +106,17 @@ func (this *WebsocketSubscriptions) send(subscriptionType api_code_types.Subscri
 	}
 }
 
+func (this *WebsocketSubscriptions) sendPendingStakes(pendingStakes *pending_stakes.PendingStakes, processed bool) {
+	for _, pending := range pendingStakes.Pending {
+		if list := this.pendingStakesSubscriptions[string(pending.PublicKey)]; list != nil {
+			this.send(api_code_types.SUBSCRIPTION_PENDING_STAKE, []byte("sub/notify"), pending.PublicKey, list, pending, nil, &api_types.APISubscriptionNotificationPendingStakeExtra{
+				pendingStakes.Height,
+				processed,
+			})
+		}
+	}
+}
+
 func (this *WebsocketSubscriptions) getSubsMap(subscriptionType api_code_types.SubscriptionType) (subsMap map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification) {
 	switch subscriptionType {
 	case api_code_types.SUBSCRIPTION_ACCOUNT, api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT, api_code_types.SUBSCRIPTION_REGISTRATION:
@@ -103,18 +127,28 @@ func (this *WebsocketSubscriptions) getSubsMap(subscriptionType api_code_types.S
 		subsMap = this.assetsSubscriptions
 	case api_code_types.SUBSCRIPTION_TRANSACTION:
 		subsMap = this.transactionsSubscriptions
+	case api_code_types.SUBSCRIPTION_CHAIN:
+		subsMap = this.chainSubscriptions
+	case api_code_types.SUBSCRIPTION_MEMPOOL:
+		subsMap = this.mempoolSubscriptions
+	case api_code_types.SUBSCRIPTION_CONDITIONAL_PAYMENT:
+		subsMap = this.conditionalPaymentsSubscriptions
+	case api_code_types.SUBSCRIPTION_PENDING_STAKE:
+		subsMap = this.pendingStakesSubscriptions
+	case api_code_types.SUBSCRIPTION_REORG_ALERT:
+		subsMap = this.reorgAlertsSubscriptions
 	}
 	return
 }
 
-func (this *WebsocketSubscriptions) removeConnection(conn *connection.AdvancedConnection, subscriptionType api_code_types.SubscriptionType) {
+func (this *WebsocketSubscriptions) removeConnection(conn connection.SubscriptionConn, subscriptionType api_code_types.SubscriptionType) {
 
 	subsMap := this.getSubsMap(subscriptionType)
 
 	var deleted []string
 	for key, value := range subsMap {
-		if value[conn.UUID] != nil {
-			delete(value, conn.UUID)
+		if value[conn.GetUUID()] != nil {
+			delete(value, conn.GetUUID())
 		}
 		if len(value) == 0 {
 			deleted = append(deleted, key)
@@ -130,6 +164,12 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 	updateNotificationsCn := this.chain.UpdateSocketsSubscriptionsNotifications.AddListener()
 	defer this.chain.UpdateSocketsSubscriptionsNotifications.RemoveChannel(updateNotificationsCn)
 
+	updateChainCn := this.chain.UpdateSocketsSubscriptionsChain.AddListener()
+	defer this.chain.UpdateSocketsSubscriptionsChain.RemoveChannel(updateChainCn)
+
+	updateReorgAlertCn := this.chain.UpdateSocketsSubscriptionsReorgAlert.AddListener()
+	defer this.chain.UpdateSocketsSubscriptionsReorgAlert.RemoveChannel(updateReorgAlertCn)
+
 	updateTransactionsCn := this.chain.UpdateSocketsSubscriptionsTransactions.AddListener()
 	defer this.chain.UpdateSocketsSubscriptionsTransactions.RemoveChannel(updateTransactionsCn)
 
@@ -151,7 +191,7 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 			if subsMap[keyStr] == nil {
 				subsMap[keyStr] = make(map[advanced_connection_types.UUID]*connection.SubscriptionNotification)
 			}
-			subsMap[keyStr][subscription.Conn.UUID] = subscription
+			subsMap[keyStr][subscription.Conn.GetUUID()] = subscription
 
 		case subscription := <-this.removeSubscriptionCn:
 
@@ -161,7 +201,7 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 
 			keyStr := string(subscription.Subscription.Key)
 			if subsMap[keyStr] != nil {
-				delete(subsMap[keyStr], subscription.Conn.UUID)
+				delete(subsMap[keyStr], subscription.Conn.GetUUID())
 				if len(subsMap[keyStr]) == 0 {
 					delete(subsMap, keyStr)
 				}
@@ -230,6 +270,79 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 				}
 			}
 
+			if len(this.conditionalPaymentsSubscriptions) > 0 {
+				for _, conditionalPayments := range dataStorage.ConditionalPaymentsCollection.GetAllMaps() {
+					for k, v := range conditionalPayments.HashMap.Committed {
+
+						//key is txId_payloadIndex
+						if len(k) <= cryptography.HashSize+1 {
+							continue
+						}
+
+						txId := k[:cryptography.HashSize]
+						if list := this.conditionalPaymentsSubscriptions[txId]; list != nil {
+
+							payloadIndex, err := strconv.Atoi(k[cryptography.HashSize+1:])
+							if err != nil {
+								continue
+							}
+
+							extra := &api_types.APISubscriptionNotificationConditionalPaymentExtra{byte(payloadIndex), conditionalPayments.BlockHeight, v.Stored == "del"}
+							if extra.Deleted {
+								this.send(api_code_types.SUBSCRIPTION_CONDITIONAL_PAYMENT, []byte("sub/notify"), []byte(txId), list, nil, nil, extra)
+							} else {
+								this.send(api_code_types.SUBSCRIPTION_CONDITIONAL_PAYMENT, []byte("sub/notify"), []byte(txId), list, v.Element, nil, extra)
+							}
+						}
+					}
+				}
+			}
+
+			if len(this.pendingStakesSubscriptions) > 0 {
+				for _, v := range dataStorage.PendingStakes.HashMap.Committed {
+					if v.Stored == "update" {
+						this.sendPendingStakes(v.Element, false)
+					}
+				}
+				for _, pendingStakes := range dataStorage.PendingStakes.Processed {
+					this.sendPendingStakes(pendingStakes, true)
+				}
+			}
+
+		case chainUpdate := <-updateChainCn:
+
+			if list := this.chainSubscriptions[""]; list != nil {
+
+				extra := &api_types.APISubscriptionNotificationChainExtra{
+					Height:                chainUpdate.Height,
+					Hash:                  chainUpdate.Hash,
+					PrevHash:              chainUpdate.PrevHash,
+					KernelHash:            chainUpdate.KernelHash,
+					Timestamp:             chainUpdate.Timestamp,
+					TransactionsCount:     chainUpdate.TransactionsCount,
+					RemovedBlocksHeights:  chainUpdate.RemovedBlocksHeights,
+					InsertedBlocksHeights: make([]uint64, len(chainUpdate.InsertedBlocks)),
+					InsertedBlocksHashes:  make([][]byte, len(chainUpdate.InsertedBlocks)),
+				}
+				for i, blkComplete := range chainUpdate.InsertedBlocks {
+					extra.InsertedBlocksHeights[i] = blkComplete.Height
+					extra.InsertedBlocksHashes[i] = blkComplete.Bloom.Hash
+				}
+
+				this.send(api_code_types.SUBSCRIPTION_CHAIN, []byte("sub/notify"), nil, list, nil, nil, extra)
+			}
+
+		case alert := <-updateReorgAlertCn:
+
+			if list := this.reorgAlertsSubscriptions[""]; list != nil {
+				this.send(api_code_types.SUBSCRIPTION_REORG_ALERT, []byte("sub/notify"), nil, list, nil, nil, &api_types.APISubscriptionNotificationReorgAlertExtra{
+					alert.ChainHeight,
+					alert.ForkHeight,
+					alert.Depth,
+					alert.Reason,
+				})
+			}
+
 		case txsUpdates, ok := <-updateTransactionsCn:
 			if !ok {
 				return
@@ -263,17 +376,21 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 			for key := range txUpdate.Keys {
 				if list := this.accountsTransactionsSubscriptions[key]; list != nil {
 					this.send(api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, []byte("sub/notify"), []byte(key), list, nil, txUpdate.Tx.Bloom.Hash, &api_types.APISubscriptionNotificationAccountTxExtra{
-						Mempool: &api_types.APISubscriptionNotificationAccountTxExtraMempool{txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification},
+						Mempool: &api_types.APISubscriptionNotificationAccountTxExtraMempool{txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification, txUpdate.EvictedReason},
 					})
 				}
 			}
 
 			if list := this.transactionsSubscriptions[txUpdate.Tx.Bloom.HashStr]; list != nil {
 				this.send(api_code_types.SUBSCRIPTION_TRANSACTION, []byte("sub/notify"), txUpdate.Tx.Bloom.Hash, list, nil, nil, &api_types.APISubscriptionNotificationTxExtra{
-					Mempool: &api_types.APISubscriptionNotificationTxExtraMempool{txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification},
+					Mempool: &api_types.APISubscriptionNotificationTxExtraMempool{txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification, txUpdate.EvictedReason},
 				})
 			}
 
+			if list := this.mempoolSubscriptions[""]; list != nil {
+				this.send(api_code_types.SUBSCRIPTION_MEMPOOL, []byte("sub/notify"), nil, list, nil, txUpdate.Tx.Bloom.Hash, &api_types.APISubscriptionNotificationTxExtraMempool{txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification, txUpdate.EvictedReason})
+			}
+
 		case conn, ok := <-this.websocketClosedCn:
 			if !ok {
 				return
@@ -283,6 +400,10 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 			this.removeConnection(conn, api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS)
 			this.removeConnection(conn, api_code_types.SUBSCRIPTION_ASSET)
 			this.removeConnection(conn, api_code_types.SUBSCRIPTION_TRANSACTION)
+			this.removeConnection(conn, api_code_types.SUBSCRIPTION_CHAIN)
+			this.removeConnection(conn, api_code_types.SUBSCRIPTION_MEMPOOL)
+			this.removeConnection(conn, api_code_types.SUBSCRIPTION_CONDITIONAL_PAYMENT)
+			this.removeConnection(conn, api_code_types.SUBSCRIPTION_PENDING_STAKE)
 
 		}
 
@@ -0,0 +1,96 @@
+package websocks
+
+import (
+	"encoding/json"
+	"errors"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/network_config"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"time"
+)
+
+// httpSubscriber delivers the notifications of a single subscription to a plain HTTP client as JSON
+type httpSubscriber struct {
+	uuid         advanced_connection_types.UUID
+	subscription *connection.Subscription
+	deliver      func(data []byte)
+}
+
+func (this *httpSubscriber) GetUUID() advanced_connection_types.UUID {
+	return this.uuid
+}
+
+// Send is used when the notification has no data
+func (this *httpSubscriber) Send(name []byte, data []byte, ctxDuration time.Duration) error {
+	out, err := json.Marshal(&api_code_types.APISubscriptionNotificationJSON{this.subscription.Type, this.subscription.Key, nil, nil})
+	if err != nil {
+		return err
+	}
+	this.deliver(out)
+	return nil
+}
+
+func (this *httpSubscriber) SendJSON(name []byte, data any, ctxDuration time.Duration) error {
+
+	notification, ok := data.(*api_code_types.APISubscriptionNotification)
+	if !ok {
+		return errors.New("Invalid notification")
+	}
+
+	final := &api_code_types.APISubscriptionNotificationJSON{notification.SubscriptionType, notification.Key, nil, decodeMsgpack(notification.Extra)}
+	if notification.Data != nil {
+		if this.subscription.ReturnType == api_code_types.RETURN_JSON {
+			final.Data = decodeMsgpack(notification.Data)
+		} else {
+			final.Data = helpers.Base64(notification.Data)
+		}
+	}
+
+	out, err := json.Marshal(final)
+	if err != nil { //the data can't be represented in JSON
+		final.Data, final.Extra = helpers.Base64(notification.Data), helpers.Base64(notification.Extra)
+		if out, err = json.Marshal(final); err != nil {
+			return err
+		}
+	}
+
+	this.deliver(out)
+	return nil
+}
+
+func decodeMsgpack(data []byte) any {
+	if data == nil {
+		return nil
+	}
+	var out any
+	if err := msgpack.Unmarshal(data, &out); err != nil {
+		return helpers.Base64(data)
+	}
+	return out
+}
+
+func (this *WebsocketSubscriptions) subscribeHttp(subscriber *httpSubscriber) error {
+	if !network_config.NETWORK_ENABLE_SUBSCRIPTIONS {
+		return errors.New("Subscriptions are disabled")
+	}
+	if err := connection.ValidateSubscription(subscriber.subscription.Type, subscriber.subscription.Key); err != nil {
+		return err
+	}
+	this.newSubscriptionCn <- &connection.SubscriptionNotification{subscriber.subscription, subscriber}
+	return nil
+}
+
+func (this *WebsocketSubscriptions) unsubscribeHttp(subscriber *httpSubscriber) {
+	this.websocketClosedCn <- subscriber
+}
+
+func newHttpSubscriber(subscriptionType api_code_types.SubscriptionType, key []byte, returnType api_code_types.APIReturnType, deliver func(data []byte)) *httpSubscriber {
+	return &httpSubscriber{
+		connection.NewUUID(),
+		&connection.Subscription{subscriptionType, key, returnType},
+		deliver,
+	}
+}
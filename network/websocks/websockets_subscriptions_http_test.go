@@ -0,0 +1,39 @@
+package websocks
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/network/api_code/api_code_types"
+	"testing"
+)
+
+func TestHttpSubscriberNotification(t *testing.T) {
+
+	var delivered []byte
+	subscriber := newHttpSubscriber(api_code_types.SUBSCRIPTION_TRANSACTION, []byte{1, 2}, api_code_types.RETURN_JSON, func(data []byte) {
+		delivered = data
+	})
+
+	extra, err := msgpack.Marshal(map[string]any{"inserted": true})
+	assert.Nil(t, err)
+	data, err := msgpack.Marshal(map[string]any{"height": 5})
+	assert.Nil(t, err)
+
+	assert.Nil(t, subscriber.SendJSON([]byte("sub/notify"), &api_code_types.APISubscriptionNotification{api_code_types.SUBSCRIPTION_TRANSACTION, []byte{1, 2}, data, extra}, 0))
+
+	out := map[string]any{}
+	assert.Nil(t, json.Unmarshal(delivered, &out))
+	assert.Equal(t, float64(api_code_types.SUBSCRIPTION_TRANSACTION), out["type"])
+	assert.Equal(t, "AQI=", out["key"])
+	assert.Equal(t, map[string]any{"height": float64(5)}, out["data"])
+	assert.Equal(t, map[string]any{"inserted": true}, out["extra"])
+
+	subscriber.subscription.ReturnType = api_code_types.RETURN_SERIALIZED
+	assert.Nil(t, subscriber.SendJSON([]byte("sub/notify"), &api_code_types.APISubscriptionNotification{api_code_types.SUBSCRIPTION_TRANSACTION, []byte{1, 2}, []byte{3}, nil}, 0))
+	assert.Equal(t, `{"type":5,"key":"AQI=","data":"Aw=="}`, string(delivered))
+}
+
+func TestWebhookSignature(t *testing.T) {
+	assert.Equal(t, "8e1a45bf4cb0f06fc9070524ebc7719df42e3abca9ada83da0314a32a75bbc62", Signature("secret", 100, []byte("{}")))
+}
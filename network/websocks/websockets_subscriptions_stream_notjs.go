@@ -0,0 +1,89 @@
+//go:build !js
+// +build !js
+
+package websocks
+
+import (
+	"fmt"
+	"github.com/tevino/abool"
+	"net/http"
+	"pandora-pay/helpers/urldecoder"
+	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/network_config"
+	"sync/atomic"
+	"time"
+)
+
+var subscriptionStreams int64 //use atomic
+
+// HandleSubscriptionStream streams the notifications of a subscription as server-sent events
+func (this *websocketsType) HandleSubscriptionStream(w http.ResponseWriter, r *http.Request) {
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	request := &api_code_types.APISubscriptionRequest{nil, api_code_types.SUBSCRIPTION_ACCOUNT, api_code_types.RETURN_JSON}
+	if err := urldecoder.Decoder.Decode(request, r.URL.Query()); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if atomic.AddInt64(&subscriptionStreams, 1) > network_config.NETWORK_SUBSCRIPTION_STREAMS_MAX {
+		atomic.AddInt64(&subscriptionStreams, -1)
+		http.Error(w, "Too many subscription streams", http.StatusServiceUnavailable)
+		return
+	}
+	defer atomic.AddInt64(&subscriptionStreams, -1)
+
+	eventsCn := make(chan []byte, network_config.NETWORK_SUBSCRIPTION_STREAM_BUFFER)
+	overflowCn := make(chan struct{})
+	overflow := abool.New()
+
+	//the client is disconnected when it is too slow to read the notifications
+	subscriber := newHttpSubscriber(request.Type, request.Key, request.ReturnType, func(data []byte) {
+		select {
+		case eventsCn <- data:
+		default:
+			if overflow.SetToIf(false, true) {
+				close(overflowCn)
+			}
+		}
+	})
+
+	if err := this.subscriptions.subscribeHttp(subscriber); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer this.subscriptions.unsubscribeHttp(subscriber)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	pingTicker := time.NewTicker(network_config.WEBSOCKETS_PING_INTERVAL)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case data := <-eventsCn:
+			if _, err := fmt.Fprintf(w, "event: sub/notify\ndata: %s\n\n", data); err != nil {
+				return
+			}
+		case <-pingTicker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-overflowCn:
+			return
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+
+}
@@ -0,0 +1,263 @@
+package websocks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/network_config"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type Webhook struct {
+	ID         string                          `json:"id" msgpack:"id"`
+	URL        string                          `json:"url" msgpack:"url"`
+	Secret     string                          `json:"-" msgpack:"secret"`
+	Type       api_code_types.SubscriptionType `json:"type" msgpack:"type"`
+	Key        helpers.Base64                  `json:"key" msgpack:"key"`
+	ReturnType api_code_types.APIReturnType    `json:"returnType" msgpack:"returnType"`
+	Created    int64                           `json:"created" msgpack:"created"`
+}
+
+// webhookSubscriber posts the notifications of a subscription to the webhook URL.
+// Every request is signed with HMAC-SHA256(secret, timestamp + "." + body)
+type webhookSubscriber struct {
+	*httpSubscriber
+	webhook *Webhook
+	queueCn chan []byte
+	closed  chan struct{}
+}
+
+type webhooksType struct {
+	subscriptions *WebsocketSubscriptions
+	list          *generics.Map[string, *webhookSubscriber]
+	lock          *sync.Mutex
+	client        *http.Client
+}
+
+var Webhooks *webhooksType
+
+func Signature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (this *webhooksType) post(webhook *Webhook, body []byte) error {
+
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Pandora-Webhook", webhook.ID)
+	req.Header.Set("X-Pandora-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Pandora-Signature", "sha256="+Signature(webhook.Secret, timestamp, body))
+
+	res, err := this.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("Webhook replied with status %d", res.StatusCode)
+	}
+	return nil
+}
+
+func (this *webhooksType) run(subscriber *webhookSubscriber) {
+	for {
+		select {
+		case body := <-subscriber.queueCn:
+
+			var err error
+			retryInterval := network_config.NETWORK_WEBHOOK_RETRY_INTERVAL
+			for retry := 0; retry <= network_config.NETWORK_WEBHOOK_RETRIES; retry++ {
+				if retry > 0 {
+					select {
+					case <-time.After(retryInterval):
+						retryInterval *= 2
+					case <-subscriber.closed:
+						return
+					}
+				}
+				if err = this.post(subscriber.webhook, body); err == nil {
+					break
+				}
+			}
+
+			if err != nil {
+				gui.GUI.Error("Webhook notification was dropped", subscriber.webhook.ID, err)
+			}
+
+		case <-subscriber.closed:
+			return
+		}
+	}
+}
+
+func (this *webhooksType) start(webhook *Webhook) error {
+
+	subscriber := &webhookSubscriber{
+		webhook: webhook,
+		queueCn: make(chan []byte, network_config.NETWORK_WEBHOOK_QUEUE),
+		closed:  make(chan struct{}),
+	}
+	subscriber.httpSubscriber = newHttpSubscriber(webhook.Type, webhook.Key, webhook.ReturnType, func(data []byte) {
+		select {
+		case subscriber.queueCn <- data:
+		default:
+			gui.GUI.Error("Webhook queue is full", webhook.ID)
+		}
+	})
+
+	if err := this.subscriptions.subscribeHttp(subscriber.httpSubscriber); err != nil {
+		return err
+	}
+
+	this.list.Store(webhook.ID, subscriber)
+	recovery.SafeGo(func() {
+		this.run(subscriber)
+	})
+	return nil
+}
+
+func (this *webhooksType) save(key string, webhook *Webhook) error {
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+		if webhook == nil {
+			writer.Delete(key)
+			return
+		}
+		data, err := msgpack.Marshal(webhook)
+		if err != nil {
+			return
+		}
+		writer.Put(key, data)
+		return
+	})
+}
+
+func (this *webhooksType) AddWebhook(webhookUrl, secret string, subscriptionType api_code_types.SubscriptionType, key []byte, returnType api_code_types.APIReturnType) (*Webhook, error) {
+
+	u, err := url.Parse(webhookUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("Webhook URL is invalid")
+	}
+	if secret == "" {
+		return nil, errors.New("Webhook secret is missing")
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	count := 0
+	this.list.Range(func(key string, value *webhookSubscriber) bool {
+		count++
+		return true
+	})
+	if count >= network_config.NETWORK_WEBHOOKS_MAX {
+		return nil, errors.New("Too many webhooks")
+	}
+
+	webhook := &Webhook{hex.EncodeToString(helpers.RandomBytes(16)), webhookUrl, secret, subscriptionType, key, returnType, time.Now().Unix()}
+	if err = this.start(webhook); err != nil {
+		return nil, err
+	}
+	if err = this.save("webhooks:"+webhook.ID, webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (this *webhooksType) RemoveWebhook(id string) (bool, error) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	subscriber, found := this.list.LoadAndDelete(id)
+	if !found {
+		return false, nil
+	}
+
+	this.subscriptions.unsubscribeHttp(subscriber.httpSubscriber)
+	close(subscriber.closed)
+
+	if err := this.save("webhooks:"+id, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (this *webhooksType) GetWebhooks() []*Webhook {
+	list := make([]*Webhook, 0)
+	this.list.Range(func(key string, subscriber *webhookSubscriber) bool {
+		list = append(list, subscriber.webhook)
+		return true
+	})
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Created < list[j].Created
+	})
+	return list
+}
+
+func (this *webhooksType) load() error {
+
+	var webhooks []*Webhook
+	if err := store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		reader.IteratePrefix("webhooks:", 0, func(key string, value []byte) bool {
+			webhook := &Webhook{}
+			if err = msgpack.Unmarshal(value, webhook); err != nil {
+				return false
+			}
+			webhooks = append(webhooks, webhook)
+			return true
+		})
+		return
+	}); err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		if err := this.start(webhook); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InitializeWebhooks starts the webhooks saved in the settings store
+func InitializeWebhooks() error {
+
+	Webhooks = &webhooksType{
+		Websockets.subscriptions,
+		&generics.Map[string, *webhookSubscriber]{},
+		&sync.Mutex{},
+		&http.Client{Timeout: network_config.WEBSOCKETS_TIMEOUT},
+	}
+
+	if !network_config.NETWORK_ENABLE_SUBSCRIPTIONS {
+		return nil
+	}
+
+	return Webhooks.load()
+}
@@ -2,6 +2,8 @@ package start
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"math"
 	"os"
@@ -28,6 +30,7 @@ import (
 	"pandora-pay/txs_builder"
 	"pandora-pay/txs_validator"
 	"pandora-pay/wallet"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"syscall"
@@ -93,9 +96,29 @@ func StartMainNow() (err error) {
 	if err = genesis.GenesisInit(app.Wallet.GetFirstAddressForDevnetGenesisAirdrop); err != nil {
 		return
 	}
+	if arguments.Arguments["--import-snapshot"] != nil {
+		if arguments.Arguments["--snapshot-trusted-hash"] == nil {
+			return errors.New("--snapshot-trusted-hash is required to import a snapshot")
+		}
+		var trustedHash []byte
+		if trustedHash, err = base64.StdEncoding.DecodeString(arguments.Arguments["--snapshot-trusted-hash"].(string)); err != nil {
+			return
+		}
+		if err = app.Chain.ImportSnapshot(arguments.Arguments["--import-snapshot"].(string), trustedHash); err != nil {
+			return
+		}
+	}
 	if err = app.Chain.InitializeChain(); err != nil {
 		return
 	}
+	if arguments.Arguments["--export-snapshot"] != nil {
+		if err = app.Chain.ExportSnapshot(arguments.Arguments["--export-snapshot"].(string)); err != nil {
+			return
+		}
+	}
+	if err = app.Mempool.LoadFromStore(app.Chain.GetChainData().Height); err != nil {
+		return
+	}
 
 	if runtime.GOARCH != "wasm" && arguments.Arguments["--balance-decryptor-disable-init"] == false {
 		tableSize := 0
@@ -105,12 +128,29 @@ func StartMainNow() (err error) {
 			}
 			tableSize = 1 << tableSize
 		}
+		maxTableSize := 0
+		if arguments.Arguments["--balance-decryptor-table-max-size"] != nil {
+			if maxTableSize, err = strconv.Atoi(arguments.Arguments["--balance-decryptor-table-max-size"].(string)); err != nil {
+				return
+			}
+			maxTableSize = 1 << maxTableSize
+		}
+		balance_decryptor.BalanceDecryptor.SetTablePath(filepath.Join(config.ORIGINAL_PATH, "_build", "balance_decryptor.table"))
 		go func() {
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
+			statusCallback := func(status string) {
+				gui.GUI.Info2Update("Decryptor", status)
+			}
 			gui.GUI.Info2Update("Decryptor", "Init... "+strconv.Itoa(int(math.Log2(float64(tableSize)))))
-			balance_decryptor.BalanceDecryptor.SetTableSize(tableSize, ctx, func(string) {})
-			gui.GUI.Info2Update("Decryptor", "Ready "+strconv.Itoa(int(math.Log2(float64(tableSize)))))
+			balance_decryptor.BalanceDecryptor.SetTableSize(tableSize, ctx, statusCallback)
+			gui.GUI.Info2Update("Decryptor", "Ready "+strconv.Itoa(int(math.Log2(float64(balance_decryptor.BalanceDecryptor.GetTableSize())))))
+			if maxTableSize > 0 {
+				if err := balance_decryptor.BalanceDecryptor.GrowTableSize(maxTableSize, ctx, statusCallback); err != nil {
+					gui.GUI.Error("Balance Decryptor table could not be grown", err)
+				}
+				gui.GUI.Info2Update("Decryptor", "Ready "+strconv.Itoa(int(math.Log2(float64(balance_decryptor.BalanceDecryptor.GetTableSize())))))
+			}
 		}()
 	}
 
@@ -7,6 +7,7 @@ import (
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/store/sparse_merkle_tree"
 	"pandora-pay/store/store_db/store_db_interface"
 	"strconv"
 )
@@ -26,6 +27,7 @@ type HashMap[T HashMapElementSerializableInterface] struct {
 	DeletedEvent   func(key []byte) error
 	StoredEvent    func(key []byte, committed *CommittedMapElement[T], index uint64) error
 	Indexable      bool
+	StateTree      *sparse_merkle_tree.SparseMerkleTree //authenticates the committed elements when it is set
 }
 
 func (hashMap *HashMap[T]) deserialize(key, data []byte, index uint64) (T, error) {
@@ -103,6 +105,129 @@ func (hashMap *HashMap[T]) GetRandom() (T, error) {
 	return hashMap.GetByIndex(index)
 }
 
+// Iterate returns in key order at most limit elements stored after the cursor key. An empty cursor starts from the beginning.
+// The next cursor is empty when there are no more elements
+// support only for commited data
+func (hashMap *HashMap[T]) Iterate(cursor string, limit int) (keys []string, elements []T, next string, err error) {
+
+	if hashMap.changed {
+		return nil, nil, "", errors.New("Iterate is supported only when is committed")
+	}
+	if limit <= 0 {
+		return nil, nil, "", errors.New("Invalid limit")
+	}
+
+	prefix := hashMap.name + ":map:"
+	start := prefix
+	if cursor != "" {
+		start += cursor + "\x00"
+	}
+
+	//one more element is read to find out if there is a next page
+	hashMap.Tx.Iterate(start, store_db_interface.PrefixEnd(prefix), limit+1, func(storeKey string, data []byte) bool {
+
+		key := storeKey[len(prefix):]
+		if len(keys) == limit {
+			next = keys[len(keys)-1]
+			return false
+		}
+
+		var index uint64
+		if hashMap.Indexable {
+			//safe because the bytes will be converted into an integer
+			indexData := hashMap.Tx.Get(hashMap.name + ":listKeys:" + key)
+			if indexData == nil {
+				err = errors.New("Key not found")
+				return false
+			}
+			if index, err = strconv.ParseUint(string(indexData), 10, 64); err != nil {
+				return false
+			}
+		}
+
+		var element T
+		if element, err = hashMap.deserialize([]byte(key), data, index); err != nil {
+			return false
+		}
+
+		keys = append(keys, key)
+		elements = append(elements, element)
+		return true
+	})
+
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return
+}
+
+// StateKey returns the key of an element in the StateTree
+func (hashMap *HashMap[T]) StateKey(key string) []byte {
+	return sparse_merkle_tree.Key(hashMap.name, key)
+}
+
+// StateIndexKey returns the key of the index of an element in the StateTree. The indexes are authenticated as the ring members are selected by index
+func (hashMap *HashMap[T]) StateIndexKey(key string) []byte {
+	return sparse_merkle_tree.Key(hashMap.name+":listKeys", key)
+}
+
+// RebuildStateTree adds all the committed elements and their indexes to the StateTree
+func (hashMap *HashMap[T]) RebuildStateTree() (err error) {
+
+	if hashMap.StateTree == nil {
+		return errors.New("HashMap is not authenticated")
+	}
+	if hashMap.changed {
+		return errors.New("RebuildStateTree is supported only when is committed")
+	}
+
+	//the tree is updated after the iteration as the store can't be changed while it is iterated
+	keys := make([][]byte, 0)
+	values := make([][]byte, 0)
+
+	hashMap.Tx.IteratePrefix(hashMap.name+":map:", 0, func(key string, data []byte) bool {
+		keys = append(keys, hashMap.StateKey(key[len(hashMap.name+":map:"):]))
+		values = append(values, sparse_merkle_tree.ValueHash(data))
+		return true
+	})
+
+	if hashMap.Indexable {
+		hashMap.Tx.IteratePrefix(hashMap.name+":listKeys:", 0, func(key string, data []byte) bool {
+			keys = append(keys, hashMap.StateIndexKey(key[len(hashMap.name+":listKeys:"):]))
+			values = append(values, sparse_merkle_tree.ValueHash(data))
+			return true
+		})
+	}
+
+	for i := range keys {
+		if err = hashMap.StateTree.Update(keys[i], values[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// StateProof returns the committed serialized element together with its inclusion or non-inclusion proof
+// support only for commited data
+func (hashMap *HashMap[T]) StateProof(key string) ([]byte, *sparse_merkle_tree.Proof, error) {
+
+	if hashMap.StateTree == nil {
+		return nil, nil, errors.New("HashMap is not authenticated")
+	}
+	if hashMap.changed {
+		return nil, nil, errors.New("StateProof is supported only when is committed")
+	}
+
+	proof, err := hashMap.StateTree.Proof(hashMap.StateKey(key))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return hashMap.Tx.Get(hashMap.name + ":map:" + key), proof, nil
+}
+
 func (hashMap *HashMap[T]) Get(key string) (out T, err error) {
 
 	if hashMap.keyLength != 0 && len(key) != hashMap.keyLength {
@@ -225,6 +350,21 @@ func (hashMap *HashMap[T]) Update(key string, data T) error {
 	return nil
 }
 
+// ImportSerialized stores a new element received serialized keeping the index it had in the store it was exported from
+func (hashMap *HashMap[T]) ImportSerialized(key string, serialized []byte, index uint64) error {
+
+	element, err := hashMap.deserialize([]byte(key), serialized, index)
+	if err != nil {
+		return err
+	}
+	if err = hashMap.Update(key, element); err != nil {
+		return err
+	}
+
+	hashMap.setNewElementIndex(key, index)
+	return nil
+}
+
 func (hashMap *HashMap[T]) Delete(key string) {
 
 	exists := hashMap.Changes[key]
@@ -359,9 +499,21 @@ func (hashMap *HashMap[T]) CommitChanges() (err error) {
 					hashMap.Tx.Delete(hashMap.name + ":map:" + k)
 					hashMap.Tx.Delete(hashMap.name + ":exists:" + k)
 
+					if hashMap.StateTree != nil {
+						if err = hashMap.StateTree.Update(hashMap.StateKey(k), nil); err != nil {
+							return
+						}
+					}
+
 					if hashMap.Indexable && v.indexProcess {
 						hashMap.Tx.Delete(hashMap.name + ":list:" + strconv.FormatUint(v.index, 10))
 						hashMap.Tx.Delete(hashMap.name + ":listKeys:" + k)
+
+						if hashMap.StateTree != nil {
+							if err = hashMap.StateTree.Update(hashMap.StateIndexKey(k), nil); err != nil {
+								return
+							}
+						}
 					}
 
 				}
@@ -406,6 +558,12 @@ func (hashMap *HashMap[T]) CommitChanges() (err error) {
 						hashMap.Tx.Put(hashMap.name+":list:"+strconv.FormatUint(v.index, 10), []byte(k))
 						//safe
 						hashMap.Tx.Put(hashMap.name+":listKeys:"+k, []byte(strconv.FormatUint(v.index, 10)))
+
+						if hashMap.StateTree != nil {
+							if err = hashMap.StateTree.Update(hashMap.StateIndexKey(k), sparse_merkle_tree.ValueHash([]byte(strconv.FormatUint(v.index, 10)))); err != nil {
+								return
+							}
+						}
 					}
 
 				}
@@ -423,6 +581,12 @@ func (hashMap *HashMap[T]) CommitChanges() (err error) {
 			if hashMap.Tx.IsWritable() {
 				//clone required because the element could change later on
 				hashMap.Tx.Put(hashMap.name+":map:"+k, committed.serialized)
+
+				if hashMap.StateTree != nil {
+					if err = hashMap.StateTree.Update(hashMap.StateKey(k), sparse_merkle_tree.ValueHash(committed.serialized)); err != nil {
+						return
+					}
+				}
 			}
 
 			committed.Status = "view"
@@ -489,6 +653,7 @@ func CreateNewHashMap[T HashMapElementSerializableInterface](tx store_db_interfa
 		nil,
 		nil,
 		indexable,
+		nil,
 	}
 
 	//safe to Get because data will be converted into an integer
@@ -0,0 +1,147 @@
+package hash_map
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/store/sparse_merkle_tree"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"strconv"
+	"testing"
+)
+
+type testElement struct {
+	key   []byte
+	index uint64
+	value uint64
+}
+
+func (this *testElement) Validate() error   { return nil }
+func (this *testElement) SetIndex(v uint64) { this.index = v }
+func (this *testElement) SetKey(key []byte) { this.key = key }
+func (this *testElement) GetIndex() uint64  { return this.index }
+func (this *testElement) IsDeletable() bool { return false }
+func (this *testElement) Serialize(w *advanced_buffers.BufferWriter) {
+	w.WriteUvarint(this.value)
+}
+func (this *testElement) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+	this.value, err = r.ReadUvarint()
+	return
+}
+
+func createTestHashMap(tx store_db_interface.StoreDBTransactionInterface) *HashMap[*testElement] {
+	hashMap := CreateNewHashMap[*testElement](tx, "testMap", 0, true)
+	hashMap.CreateObject = func(key []byte, index uint64) (*testElement, error) {
+		return &testElement{key: key, index: index}, nil
+	}
+	return hashMap
+}
+
+func TestHashMapIterate(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("/memory")
+	assert.Nil(t, err)
+	defer db.Close()
+
+	assert.Nil(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+
+		hashMap := createTestHashMap(writer)
+		for i := uint64(0); i < 5; i++ {
+			assert.Nil(t, hashMap.Update("key"+strconv.FormatUint(i, 10), &testElement{value: i}))
+		}
+
+		_, _, _, err := hashMap.Iterate("", 10)
+		assert.EqualError(t, err, "Iterate is supported only when is committed")
+
+		return hashMap.CommitChanges()
+	}))
+
+	assert.Nil(t, db.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+
+		hashMap := createTestHashMap(reader)
+
+		_, _, _, err := hashMap.Iterate("", 0)
+		assert.NotNil(t, err)
+
+		var all []string
+		cursor := ""
+		for page := 0; ; page++ {
+
+			keys, elements, next, err := hashMap.Iterate(cursor, 2)
+			assert.Nil(t, err)
+			assert.LessOrEqual(t, len(keys), 2)
+			assert.Equal(t, len(keys), len(elements))
+
+			for i, key := range keys {
+				assert.Equal(t, key, string(elements[i].key))
+				assert.Equal(t, key, "key"+strconv.FormatUint(elements[i].value, 10))
+				index, err := hashMap.GetIndexByKey(key)
+				assert.Nil(t, err)
+				assert.Equal(t, index, elements[i].index)
+			}
+			all = append(all, keys...)
+
+			if next == "" {
+				assert.Equal(t, 2, page)
+				break
+			}
+			assert.Equal(t, keys[len(keys)-1], next)
+			cursor = next
+		}
+		assert.Equal(t, []string{"key0", "key1", "key2", "key3", "key4"}, all)
+
+		//the last page is full and there is nothing after it
+		keys, _, next, err := hashMap.Iterate("key2", 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"key3", "key4"}, keys)
+		assert.Equal(t, "", next)
+
+		keys, _, next, err = hashMap.Iterate("key4", 2)
+		assert.Nil(t, err)
+		assert.Empty(t, keys)
+		assert.Equal(t, "", next)
+
+		return nil
+	}))
+}
+
+func TestHashMapReadTransitionalChanges(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("/memory")
+	assert.Nil(t, err)
+	defer db.Close()
+
+	assert.Nil(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+
+		stateTree := sparse_merkle_tree.NewSparseMerkleTree(writer, "stateTree")
+
+		hashMap := createTestHashMap(writer)
+		hashMap.StateTree = stateTree
+		for i := uint64(0); i < 4; i++ {
+			assert.Nil(t, hashMap.Update("key"+strconv.FormatUint(i, 10), &testElement{value: i}))
+		}
+		assert.Nil(t, hashMap.CommitChanges())
+		root := stateTree.Root()
+
+		//the first element is not the last one added
+		hashMap.Delete("key0")
+		assert.Nil(t, hashMap.Update("key1", &testElement{value: 10}))
+		_, err := hashMap.WriteTransitionalChangesToStore("1")
+		assert.Nil(t, err)
+		assert.Nil(t, hashMap.CommitChanges())
+		assert.NotEqual(t, root, stateTree.Root())
+
+		hashMap = createTestHashMap(writer)
+		hashMap.StateTree = stateTree
+		assert.Nil(t, hashMap.ReadTransitionalChangesFromStore("1"))
+		assert.Nil(t, hashMap.CommitChanges())
+
+		index, err := hashMap.GetIndexByKey("key0")
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(0), index)
+		assert.Equal(t, uint64(4), hashMap.Count)
+		assert.Equal(t, root, stateTree.Root())
+
+		return nil
+	}))
+}
@@ -4,11 +4,13 @@ import (
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/helpers/msgpack"
+	"strconv"
 )
 
 type transactionChange struct {
 	Key        []byte
 	Transition []byte
+	Index      []byte //index of the previous element in the indexable hash maps, nil in the older transitions
 }
 
 type transactionChanges struct {
@@ -37,6 +39,11 @@ func (hashMap *HashMap[T]) WriteTransitionalChangesToStore(prefix string) (bool,
 				change.Transition = hashMap.Tx.Get(hashMap.name + ":map:" + k)
 			}
 
+			//the changes are not committed yet
+			if hashMap.Indexable && change.Transition != nil {
+				change.Index = hashMap.Tx.Get(hashMap.name + ":listKeys:" + k)
+			}
+
 			empty = false
 			changes.List = append(changes.List, change)
 		}
@@ -92,9 +99,26 @@ func (hashMap *HashMap[T]) ReadTransitionalChangesFromStore(prefix string) error
 			if err = hashMap.Update(string(change.Key), element); err != nil {
 				return err
 			}
+
+			//the element is restored at its previous index, otherwise the indexes authenticated by the StateTree would change
+			if change.Index != nil {
+				var index uint64
+				if index, err = strconv.ParseUint(string(change.Index), 10, 64); err != nil {
+					return err
+				}
+				hashMap.setNewElementIndex(string(change.Key), index)
+			}
 		}
 
 	}
 
 	return nil
 }
+
+// changes the index given by Update to an element which was not stored
+func (hashMap *HashMap[T]) setNewElementIndex(key string, index uint64) {
+	if exists := hashMap.Changes[key]; exists != nil && exists.indexProcess {
+		exists.index = index
+		exists.Element.SetIndex(index)
+	}
+}
@@ -14,6 +14,8 @@ type HashMapInterface interface {
 	WriteTransitionalChangesToStore(prefix string) (bool, error)
 	DeleteTransitionalChangesFromStore(prefix string)
 	ReadTransitionalChangesFromStore(prefix string) error
+	RebuildStateTree() error
+	ImportSerialized(key string, serialized []byte, index uint64) error
 }
 
 type HashMapElementSerializableInterface interface {
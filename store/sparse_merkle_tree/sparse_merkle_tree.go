@@ -0,0 +1,283 @@
+package sparse_merkle_tree
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+)
+
+/**
+Compact Sparse Merkle Tree
+Keys are 32 byte hashes and their bits are the path from the root. A subtree with a single leaf is stored as the leaf itself,
+so every leaf is placed on the shortest path which is not shared with another key.
+The root doesn't depend on the order of the updates.
+*/
+
+const (
+	MAX_DEPTH = cryptography.HashSize * 8
+
+	nodeLeaf     = byte(0)
+	nodeInternal = byte(1)
+)
+
+var EmptyHash = make([]byte, cryptography.HashSize)
+
+type SparseMerkleTree struct {
+	name string
+	Tx   store_db_interface.StoreDBTransactionInterface
+}
+
+type Proof struct {
+	Siblings      [][]byte `json:"siblings" msgpack:"siblings"`                               //siblings from the root towards the leaf
+	LeafKey       []byte   `json:"leafKey,omitempty" msgpack:"leafKey,omitempty"`             //leaf found at the end of the path. Missing when the path ends in an empty node
+	LeafValueHash []byte   `json:"leafValueHash,omitempty" msgpack:"leafValueHash,omitempty"` //value hash of the leaf found at the end of the path
+}
+
+func getBit(key []byte, depth int) byte {
+	return (key[depth/8] >> (7 - uint(depth%8))) & 1
+}
+
+func leafNode(key, valueHash []byte) []byte {
+	return append(append([]byte{nodeLeaf}, key...), valueHash...)
+}
+
+func internalNode(left, right []byte) []byte {
+	return append(append([]byte{nodeInternal}, left...), right...)
+}
+
+func hashNode(data []byte) []byte {
+	if data == nil {
+		return EmptyHash
+	}
+	return cryptography.SHA3(data)
+}
+
+// Key returns the key used in the tree for a key of a store
+func Key(name, key string) []byte {
+	return cryptography.SHA3([]byte(name + ":" + key))
+}
+
+// ValueHash returns the hash of a serialized value stored in the tree
+func ValueHash(serialized []byte) []byte {
+	return cryptography.SHA3(serialized)
+}
+
+func (tree *SparseMerkleTree) nodeKey(key []byte, depth int) string {
+	path := helpers.CloneBytes(key[:(depth+7)/8])
+	if depth%8 != 0 {
+		path[len(path)-1] &= 0xff << (8 - uint(depth%8))
+	}
+	return tree.name + ":node:" + strconv.Itoa(depth) + ":" + string(path)
+}
+
+func siblingPath(key []byte, depth int) []byte {
+	sibling := helpers.CloneBytes(key)
+	sibling[depth/8] ^= 1 << (7 - uint(depth%8))
+	return sibling
+}
+
+func (tree *SparseMerkleTree) Root() []byte {
+	return hashNode(tree.Tx.Get(tree.nodeKey(EmptyHash, 0)))
+}
+
+// Update sets the value hash of a key. A nil value hash removes the key
+func (tree *SparseMerkleTree) Update(key, valueHash []byte) error {
+
+	if len(key) != cryptography.HashSize {
+		return errors.New("Sparse Merkle Tree key is invalid")
+	}
+	if valueHash != nil && len(valueHash) != cryptography.HashSize {
+		return errors.New("Sparse Merkle Tree value hash is invalid")
+	}
+	if !tree.Tx.IsWritable() {
+		return errors.New("Sparse Merkle Tree can be updated only in a writable transaction")
+	}
+
+	_, err := tree.update(key, valueHash, 0)
+	return err
+}
+
+// update returns the new node stored at the depth on the path of the key
+func (tree *SparseMerkleTree) update(key, valueHash []byte, depth int) ([]byte, error) {
+
+	if depth > MAX_DEPTH {
+		return nil, errors.New("Sparse Merkle Tree is too deep")
+	}
+
+	nodeKey := tree.nodeKey(key, depth)
+	data := tree.Tx.Get(nodeKey)
+
+	if data == nil {
+		if valueHash == nil {
+			return nil, nil
+		}
+		data = leafNode(key, valueHash)
+		tree.Tx.Put(nodeKey, data)
+		return data, nil
+	}
+
+	if data[0] == nodeLeaf {
+
+		leafKey := data[1 : 1+cryptography.HashSize]
+
+		if bytes.Equal(leafKey, key) {
+			if valueHash == nil {
+				tree.Tx.Delete(nodeKey)
+				return nil, nil
+			}
+			data = leafNode(key, valueHash)
+			tree.Tx.Put(nodeKey, data)
+			return data, nil
+		}
+
+		if valueHash == nil {
+			return data, nil
+		}
+
+		//the existing leaf is moved one level down and the node becomes an internal node
+		tree.Tx.Put(tree.nodeKey(leafKey, depth+1), data)
+		if getBit(leafKey, depth) == 0 {
+			data = internalNode(hashNode(data), EmptyHash)
+		} else {
+			data = internalNode(EmptyHash, hashNode(data))
+		}
+	}
+
+	left := data[1 : 1+cryptography.HashSize]
+	right := data[1+cryptography.HashSize:]
+
+	child, err := tree.update(key, valueHash, depth+1)
+	if err != nil {
+		return nil, err
+	}
+
+	var siblingHash []byte
+	if getBit(key, depth) == 0 {
+		left, siblingHash = hashNode(child), right
+	} else {
+		right, siblingHash = hashNode(child), left
+	}
+
+	//a subtree with a single leaf is replaced by the leaf
+	if bytes.Equal(siblingHash, EmptyHash) {
+		if child == nil {
+			tree.Tx.Delete(nodeKey)
+			return nil, nil
+		}
+		if child[0] == nodeLeaf {
+			tree.Tx.Delete(tree.nodeKey(key, depth+1))
+			tree.Tx.Put(nodeKey, child)
+			return child, nil
+		}
+	} else if child == nil {
+		siblingKey := tree.nodeKey(siblingPath(key, depth), depth+1)
+		sibling := tree.Tx.Get(siblingKey)
+		if sibling == nil {
+			return nil, errors.New("Sparse Merkle Tree sibling was not found")
+		}
+		if sibling[0] == nodeLeaf {
+			tree.Tx.Delete(siblingKey)
+			tree.Tx.Put(nodeKey, sibling)
+			return sibling, nil
+		}
+	}
+
+	data = internalNode(left, right)
+	tree.Tx.Put(nodeKey, data)
+	return data, nil
+}
+
+// Proof returns the inclusion or the non-inclusion proof of a key
+func (tree *SparseMerkleTree) Proof(key []byte) (*Proof, error) {
+
+	if len(key) != cryptography.HashSize {
+		return nil, errors.New("Sparse Merkle Tree key is invalid")
+	}
+
+	proof := &Proof{Siblings: [][]byte{}}
+	for depth := 0; depth <= MAX_DEPTH; depth++ {
+
+		data := tree.Tx.Get(tree.nodeKey(key, depth))
+		if data == nil {
+			return proof, nil
+		}
+
+		if data[0] == nodeLeaf {
+			proof.LeafKey = data[1 : 1+cryptography.HashSize]
+			proof.LeafValueHash = data[1+cryptography.HashSize:]
+			return proof, nil
+		}
+
+		if getBit(key, depth) == 0 {
+			proof.Siblings = append(proof.Siblings, data[1+cryptography.HashSize:])
+		} else {
+			proof.Siblings = append(proof.Siblings, data[1:1+cryptography.HashSize])
+		}
+	}
+
+	return nil, errors.New("Sparse Merkle Tree is too deep")
+}
+
+// VerifyProof checks the proof of a key against the root. A nil value hash verifies that the key is not included
+func VerifyProof(root, key, valueHash []byte, proof *Proof) bool {
+
+	if proof == nil || len(key) != cryptography.HashSize || len(proof.Siblings) > MAX_DEPTH {
+		return false
+	}
+
+	var hash []byte
+	if proof.LeafKey == nil {
+		if valueHash != nil {
+			return false
+		}
+		hash = EmptyHash
+	} else {
+		if len(proof.LeafKey) != cryptography.HashSize || len(proof.LeafValueHash) != cryptography.HashSize {
+			return false
+		}
+		if bytes.Equal(proof.LeafKey, key) {
+			if !bytes.Equal(proof.LeafValueHash, valueHash) {
+				return false
+			}
+		} else {
+			if valueHash != nil {
+				return false
+			}
+			//a different leaf proves the non-inclusion only if it is on the path of the key
+			for depth := range proof.Siblings {
+				if getBit(proof.LeafKey, depth) != getBit(key, depth) {
+					return false
+				}
+			}
+		}
+		hash = hashNode(leafNode(proof.LeafKey, proof.LeafValueHash))
+	}
+
+	for depth := len(proof.Siblings) - 1; depth >= 0; depth-- {
+		sibling := proof.Siblings[depth]
+		if len(sibling) != cryptography.HashSize {
+			return false
+		}
+		if getBit(key, depth) == 0 {
+			hash = hashNode(internalNode(hash, sibling))
+		} else {
+			hash = hashNode(internalNode(sibling, hash))
+		}
+	}
+
+	return bytes.Equal(hash, root)
+}
+
+func (tree *SparseMerkleTree) SetTx(tx store_db_interface.StoreDBTransactionInterface) {
+	tree.Tx = tx
+}
+
+func NewSparseMerkleTree(tx store_db_interface.StoreDBTransactionInterface, name string) *SparseMerkleTree {
+	return &SparseMerkleTree{
+		name,
+		tx,
+	}
+}
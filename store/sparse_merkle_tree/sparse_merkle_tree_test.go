@@ -0,0 +1,77 @@
+package sparse_merkle_tree
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"pandora-pay/cryptography"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+func TestSparseMerkleTree(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("/smt")
+	assert.Nil(t, err)
+	defer db.Close()
+
+	keys := make([][]byte, 50)
+	values := make([][]byte, len(keys))
+	for i := range keys {
+		keys[i] = cryptography.RandomHash()
+		values[i] = ValueHash(cryptography.RandomHash())
+	}
+
+	//keys sharing a long prefix
+	keys[1] = append([]byte{}, keys[0]...)
+	keys[1][31] ^= 1
+
+	assert.Nil(t, db.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+
+		tree := NewSparseMerkleTree(dbTx, "tree")
+		assert.Equal(t, EmptyHash, tree.Root())
+
+		for i := range keys {
+			assert.Nil(t, tree.Update(keys[i], values[i]))
+		}
+		root := tree.Root()
+
+		for i := range keys {
+			proof, err := tree.Proof(keys[i])
+			assert.Nil(t, err)
+			assert.True(t, VerifyProof(root, keys[i], values[i], proof), "Inclusion proof is invalid")
+			assert.False(t, VerifyProof(root, keys[i], nil, proof), "Non-inclusion proof should be invalid")
+			assert.False(t, VerifyProof(root, keys[i], values[(i+1)%len(values)], proof), "Inclusion proof should be invalid")
+		}
+
+		missing := cryptography.RandomHash()
+		proof, err := tree.Proof(missing)
+		assert.Nil(t, err)
+		assert.True(t, VerifyProof(root, missing, nil, proof), "Non-inclusion proof is invalid")
+		assert.False(t, VerifyProof(root, missing, values[0], proof), "Inclusion proof should be invalid")
+
+		//the root doesn't depend on the order of the updates and on the removed keys
+		tree2 := NewSparseMerkleTree(dbTx, "tree2")
+		for _, i := range rand.Perm(len(keys)) {
+			assert.Nil(t, tree2.Update(keys[i], cryptography.RandomHash()))
+			assert.Nil(t, tree2.Update(keys[i], values[i]))
+			assert.Nil(t, tree2.Update(missing, values[i]))
+		}
+		assert.Nil(t, tree2.Update(missing, nil))
+		assert.Equal(t, root, tree2.Root())
+
+		for i := range keys {
+			assert.Nil(t, tree2.Update(keys[i], nil))
+		}
+		assert.Equal(t, EmptyHash, tree2.Root())
+		count := 0
+		dbTx.IteratePrefix("tree2:", 0, func(key string, value []byte) bool {
+			count++
+			return true
+		})
+		assert.Equal(t, 0, count, "Removed nodes should be deleted")
+
+		return nil
+	}))
+
+}
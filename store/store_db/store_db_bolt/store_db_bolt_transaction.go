@@ -36,3 +36,22 @@ func (tx *StoreDBBoltTransaction) Exists(key string) bool {
 func (tx *StoreDBBoltTransaction) Delete(key string) {
 	tx.bucket.Delete([]byte(key))
 }
+
+func (tx *StoreDBBoltTransaction) Iterate(start, end string, limit int, callback func(key string, value []byte) bool) {
+	c := tx.bucket.Cursor()
+	count := 0
+	for k, v := c.Seek([]byte(start)); k != nil && (end == "" || string(k) < end); k, v = c.Next() {
+		if limit > 0 && count >= limit {
+			return
+		}
+		count++
+		//bolt requires the data to be cloned
+		if !callback(string(k), helpers.CloneBytes(v)) {
+			return
+		}
+	}
+}
+
+func (tx *StoreDBBoltTransaction) IteratePrefix(prefix string, limit int, callback func(key string, value []byte) bool) {
+	tx.Iterate(prefix, store_db_interface.PrefixEnd(prefix), limit, callback)
+}
@@ -41,7 +41,34 @@ func (tx *StoreDBBuntTransaction) Exists(key string) bool {
 
 func (tx *StoreDBBuntTransaction) Delete(key string) {
 	_, err := tx.buntTx.Delete(key)
-	if err != buntdb.ErrNotFound {
+	if err != nil && err != buntdb.ErrNotFound {
 		panic(err)
 	}
 }
+
+func (tx *StoreDBBuntTransaction) Iterate(start, end string, limit int, callback func(key string, value []byte) bool) {
+
+	count := 0
+	iterator := func(key, value string) bool {
+		if limit > 0 && count >= limit {
+			return false
+		}
+		count++
+		//value is cloned
+		return callback(key, []byte(value))
+	}
+
+	var err error
+	if end == "" {
+		err = tx.buntTx.AscendGreaterOrEqual("", start, iterator)
+	} else {
+		err = tx.buntTx.AscendRange("", start, end, iterator)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (tx *StoreDBBuntTransaction) IteratePrefix(prefix string, limit int, callback func(key string, value []byte) bool) {
+	tx.Iterate(prefix, store_db_interface.PrefixEnd(prefix), limit, callback)
+}
@@ -0,0 +1,18 @@
+package store_db_interface
+
+//PrefixEnd returns the smallest key that is greater than all the keys beginning with prefix.
+//An empty result means there is no upper bound
+func PrefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return ""
+}
+
+func InRange(key, start, end string) bool {
+	return key >= start && (end == "" || key < end)
+}
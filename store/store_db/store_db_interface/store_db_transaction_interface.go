@@ -6,4 +6,9 @@ type StoreDBTransactionInterface interface {
 	Exists(key string) bool
 	Delete(key string)
 	IsWritable() bool
+	//Iterate visits in ascending order the keys within [start, end). An empty end means there is no upper bound and a limit of 0 means there is no limit.
+	//The iteration stops when the callback returns false. The store must not be modified from the callback
+	Iterate(start, end string, limit int, callback func(key string, value []byte) bool)
+	//IteratePrefix visits in ascending order the keys that begin with prefix
+	IteratePrefix(prefix string, limit int, callback func(key string, value []byte) bool)
 }
@@ -6,6 +6,7 @@ import (
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
 	"syscall/js"
 )
 
@@ -151,3 +152,81 @@ func (tx *StoreDBJSTransaction) writeTx() error {
 
 	return nil
 }
+
+func (tx *StoreDBJSTransaction) keys() []string {
+
+	respCh := make(chan []string)
+	defer close(respCh)
+
+	errCh := make(chan error)
+	defer close(errCh)
+
+	promise := tx.jsStore.Call("keys")
+
+	promise.Call("then", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		var result []string
+		if !args[0].IsNull() && !args[0].IsUndefined() {
+			result = make([]string, args[0].Get("length").Int())
+			for i := range result {
+				result[i] = args[0].Index(i).String()
+			}
+		}
+
+		respCh <- result
+		return nil
+	}), js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		errCh <- fmt.Errorf("error reading keys js db %s", args[0].Get("message").String())
+		return nil
+	}))
+
+	select {
+	case resp := <-respCh:
+		return resp
+	case <-errCh:
+		return nil
+	}
+}
+
+//the local changes of the transaction are merged over the store
+func (tx *StoreDBJSTransaction) Iterate(start, end string, limit int, callback func(key string, value []byte) bool) {
+
+	found := make(map[string]bool)
+	for _, key := range tx.keys() {
+		if store_db_interface.InRange(key, start, end) {
+			found[key] = true
+		}
+	}
+	tx.local.Range(func(key string, data *StoreDBJSTransactionData) bool {
+		if store_db_interface.InRange(key, start, end) {
+			found[key] = true
+		}
+		return true
+	})
+
+	keys := make([]string, 0, len(found))
+	for key := range found {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	count := 0
+	for _, key := range keys {
+		if limit > 0 && count >= limit {
+			return
+		}
+
+		value := tx.Get(key)
+		if value == nil {
+			continue
+		}
+
+		count++
+		if !callback(key, helpers.CloneBytes(value)) {
+			return
+		}
+	}
+}
+
+func (tx *StoreDBJSTransaction) IteratePrefix(prefix string, limit int, callback func(key string, value []byte) bool) {
+	tx.Iterate(prefix, store_db_interface.PrefixEnd(prefix), limit, callback)
+}
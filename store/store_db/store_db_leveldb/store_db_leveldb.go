@@ -0,0 +1,89 @@
+package store_db_leveldb
+
+import (
+	"github.com/syndtr/goleveldb/leveldb"
+	"os"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type StoreDBLevelDB struct {
+	store_db_interface.StoreDBInterface
+	DB   *leveldb.DB
+	Name []byte
+}
+
+func (store *StoreDBLevelDB) Close() error {
+	return store.DB.Close()
+}
+
+//a snapshot provides a consistent read-only view over the store
+func (store *StoreDBLevelDB) View(callback func(dbTx store_db_interface.StoreDBTransactionInterface) error) error {
+
+	snapshot, err := store.DB.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snapshot.Release()
+
+	tx := &StoreDBLevelDBTransaction{
+		reader: snapshot,
+	}
+	return callback(tx)
+}
+
+//leveldb allows only one open transaction at a time, similar to bolt's single writer
+func (store *StoreDBLevelDB) Update(callback func(dbTx store_db_interface.StoreDBTransactionInterface) error) (err error) {
+
+	levelTx, err := store.DB.OpenTransaction()
+	if err != nil {
+		return err
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			levelTx.Discard()
+		}
+	}()
+
+	tx := &StoreDBLevelDBTransaction{
+		reader:  levelTx,
+		levelTx: levelTx,
+		write:   true,
+	}
+
+	if err = callback(tx); err != nil {
+		return
+	}
+
+	if err = levelTx.Commit(); err != nil {
+		return
+	}
+	committed = true
+
+	return
+}
+
+func CreateStoreDBLevelDB(name string) (*StoreDBLevelDB, error) {
+
+	var err error
+
+	store := &StoreDBLevelDB{
+		Name: []byte(name),
+	}
+
+	prefix := "./store"
+	if _, err = os.Stat(prefix); os.IsNotExist(err) {
+		if err = os.Mkdir(prefix, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	// Open the store folder in your current directory.
+	// It will be created if it doesn't exist.
+	if store.DB, err = leveldb.OpenFile(prefix+name+"_store"+".leveldb", nil); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
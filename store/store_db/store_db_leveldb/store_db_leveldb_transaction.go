@@ -0,0 +1,98 @@
+package store_db_leveldb
+
+import (
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"pandora-pay/helpers"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+//implemented by both leveldb.Snapshot and leveldb.Transaction
+type levelDBReader interface {
+	Get(key []byte, ro *opt.ReadOptions) ([]byte, error)
+	Has(key []byte, ro *opt.ReadOptions) (bool, error)
+	NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator
+}
+
+type StoreDBLevelDBTransaction struct {
+	store_db_interface.StoreDBTransactionInterface
+	reader  levelDBReader
+	levelTx *leveldb.Transaction
+	write   bool
+}
+
+func (tx *StoreDBLevelDBTransaction) IsWritable() bool {
+	return tx.write
+}
+
+func (tx *StoreDBLevelDBTransaction) Put(key string, value []byte) {
+	if !tx.write {
+		panic("Transaction is not writeable")
+	}
+	//value is cloned
+	if err := tx.levelTx.Put([]byte(key), value, nil); err != nil {
+		panic(err)
+	}
+}
+
+func (tx *StoreDBLevelDBTransaction) Get(key string) []byte {
+	//value is cloned
+	data, err := tx.reader.Get([]byte(key), nil)
+	if err != nil {
+		if err != leveldb.ErrNotFound {
+			panic(err)
+		}
+		return nil
+	}
+	return data
+}
+
+func (tx *StoreDBLevelDBTransaction) Exists(key string) bool {
+	exists, err := tx.reader.Has([]byte(key), nil)
+	if err != nil {
+		panic(err)
+	}
+	return exists
+}
+
+func (tx *StoreDBLevelDBTransaction) Delete(key string) {
+	if !tx.write {
+		panic("Transaction is not writeable")
+	}
+	if err := tx.levelTx.Delete([]byte(key), nil); err != nil {
+		panic(err)
+	}
+}
+
+func (tx *StoreDBLevelDBTransaction) Iterate(start, end string, limit int, callback func(key string, value []byte) bool) {
+
+	slice := &util.Range{Start: []byte(start)}
+	if end != "" {
+		slice.Limit = []byte(end)
+	}
+
+	it := tx.reader.NewIterator(slice, nil)
+	defer it.Release()
+
+	count := 0
+	for it.Next() {
+		if limit > 0 && count >= limit {
+			break
+		}
+		count++
+		//the iterator reuses its buffers
+		if !callback(string(it.Key()), helpers.CloneBytes(it.Value())) {
+			break
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		panic(err)
+	}
+}
+
+func (tx *StoreDBLevelDBTransaction) IteratePrefix(prefix string, limit int, callback func(key string, value []byte) bool) {
+	tx.Iterate(prefix, store_db_interface.PrefixEnd(prefix), limit, callback)
+}
@@ -5,6 +5,7 @@ import (
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
 )
 
 type StoreDBMemoryTransactionData struct {
@@ -42,7 +43,7 @@ func (tx *StoreDBMemoryTransaction) Get(key string) []byte {
 
 	resp := helpers.CloneBytes(tx.store[key])
 	tx.local.Store(key, &StoreDBMemoryTransactionData{resp, "get"})
-	return resp
+	return helpers.CloneBytes(resp)
 }
 
 func (tx *StoreDBMemoryTransaction) Exists(key string) bool {
@@ -78,3 +79,41 @@ func (tx *StoreDBMemoryTransaction) writeTx() error {
 
 	return nil
 }
+
+//the local changes of the transaction are merged over the store
+func (tx *StoreDBMemoryTransaction) Iterate(start, end string, limit int, callback func(key string, value []byte) bool) {
+
+	values := make(map[string][]byte)
+	for key, value := range tx.store {
+		if store_db_interface.InRange(key, start, end) {
+			values[key] = value
+		}
+	}
+	tx.local.Range(func(key string, data *StoreDBMemoryTransactionData) bool {
+		if store_db_interface.InRange(key, start, end) {
+			values[key] = data.value
+		}
+		return true
+	})
+
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		if value != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for i, key := range keys {
+		if limit > 0 && i >= limit {
+			return
+		}
+		if !callback(key, helpers.CloneBytes(values[key])) {
+			return
+		}
+	}
+}
+
+func (tx *StoreDBMemoryTransaction) IteratePrefix(prefix string, limit int, callback func(key string, value []byte) bool) {
+	tx.Iterate(prefix, store_db_interface.PrefixEnd(prefix), limit, callback)
+}
@@ -0,0 +1,211 @@
+package store_db
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"pandora-pay/store/store_db/store_db_bolt"
+	"pandora-pay/store/store_db/store_db_bunt"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_leveldb"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+//every backend must behave identically, so all the tests are run against all of them
+func forEachStoreDB(t *testing.T, test func(t *testing.T, db store_db_interface.StoreDBInterface)) {
+
+	cwd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir(t.TempDir()))
+	defer os.Chdir(cwd)
+
+	creators := []struct {
+		name   string
+		create func() (store_db_interface.StoreDBInterface, error)
+	}{
+		{"bolt", func() (store_db_interface.StoreDBInterface, error) { return store_db_bolt.CreateStoreDBBolt("/bolt") }},
+		{"bunt", func() (store_db_interface.StoreDBInterface, error) { return store_db_bunt.CreateStoreDBBunt("/bunt", false) }},
+		{"bunt-memory", func() (store_db_interface.StoreDBInterface, error) { return store_db_bunt.CreateStoreDBBunt("/bunt", true) }},
+		{"leveldb", func() (store_db_interface.StoreDBInterface, error) { return store_db_leveldb.CreateStoreDBLevelDB("/leveldb") }},
+		{"memory", func() (store_db_interface.StoreDBInterface, error) { return store_db_memory.CreateStoreDBMemory("/memory") }},
+	}
+
+	for _, creator := range creators {
+		t.Run(creator.name, func(t *testing.T) {
+			db, err := creator.create()
+			assert.Nil(t, err)
+			defer func() {
+				assert.Nil(t, db.Close())
+			}()
+			test(t, db)
+		})
+	}
+}
+
+func TestStoreDBPutGetDelete(t *testing.T) {
+	forEachStoreDB(t, func(t *testing.T, db store_db_interface.StoreDBInterface) {
+
+		assert.Nil(t, db.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			assert.True(t, dbTx.IsWritable())
+			assert.Nil(t, dbTx.Get("a"))
+			assert.False(t, dbTx.Exists("a"))
+
+			dbTx.Put("a", []byte{1, 2, 3})
+			dbTx.Put("b", []byte{4})
+			assert.Equal(t, []byte{1, 2, 3}, dbTx.Get("a"))
+			assert.True(t, dbTx.Exists("a"))
+
+			dbTx.Delete("b")
+			assert.Nil(t, dbTx.Get("b"))
+			assert.False(t, dbTx.Exists("b"))
+			return nil
+		}))
+
+		assert.Nil(t, db.View(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			assert.False(t, dbTx.IsWritable())
+			assert.Equal(t, []byte{1, 2, 3}, dbTx.Get("a"))
+			assert.True(t, dbTx.Exists("a"))
+			assert.Nil(t, dbTx.Get("b"))
+			assert.False(t, dbTx.Exists("b"))
+			return nil
+		}))
+
+		assert.Nil(t, db.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			dbTx.Put("a", []byte{5})
+			dbTx.Delete("missing")
+			return nil
+		}))
+
+		assert.Nil(t, db.View(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			assert.Equal(t, []byte{5}, dbTx.Get("a"))
+			return nil
+		}))
+
+		assert.Nil(t, db.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			dbTx.Delete("a")
+			return nil
+		}))
+
+		assert.Nil(t, db.View(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			assert.Nil(t, dbTx.Get("a"))
+			return nil
+		}))
+	})
+}
+
+func TestStoreDBRollback(t *testing.T) {
+	forEachStoreDB(t, func(t *testing.T, db store_db_interface.StoreDBInterface) {
+
+		assert.Nil(t, db.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			dbTx.Put("a", []byte{1})
+			return nil
+		}))
+
+		errRollback := errors.New("Rollback")
+		assert.Equal(t, errRollback, db.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			dbTx.Put("a", []byte{2})
+			dbTx.Put("b", []byte{3})
+			return errRollback
+		}))
+
+		assert.Nil(t, db.View(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			assert.Equal(t, []byte{1}, dbTx.Get("a"))
+			assert.False(t, dbTx.Exists("b"))
+			return nil
+		}))
+
+		assert.Equal(t, errRollback, db.View(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			return errRollback
+		}))
+	})
+}
+
+//the values must not be shared with the underlying storage
+func TestStoreDBValuesAreCloned(t *testing.T) {
+	forEachStoreDB(t, func(t *testing.T, db store_db_interface.StoreDBInterface) {
+
+		value := []byte{1, 2, 3}
+		assert.Nil(t, db.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			dbTx.Put("a", value)
+			value[0] = 9
+			return nil
+		}))
+
+		assert.Nil(t, db.View(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			data := dbTx.Get("a")
+			assert.Equal(t, []byte{1, 2, 3}, data)
+			data[1] = 9
+			assert.Equal(t, []byte{1, 2, 3}, dbTx.Get("a"))
+			return nil
+		}))
+	})
+}
+
+func TestStoreDBIterate(t *testing.T) {
+	forEachStoreDB(t, func(t *testing.T, db store_db_interface.StoreDBInterface) {
+
+		collect := func(dbTx store_db_interface.StoreDBTransactionInterface, start, end string, limit int) (keys []string) {
+			dbTx.Iterate(start, end, limit, func(key string, value []byte) bool {
+				assert.Equal(t, []byte(key), value)
+				keys = append(keys, key)
+				return true
+			})
+			return
+		}
+
+		collectPrefix := func(dbTx store_db_interface.StoreDBTransactionInterface, prefix string, limit int) (keys []string) {
+			dbTx.IteratePrefix(prefix, limit, func(key string, value []byte) bool {
+				keys = append(keys, key)
+				return true
+			})
+			return
+		}
+
+		assert.Nil(t, db.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			for _, key := range []string{"b:2", "a:1", "b:1", "b:3", "c", "b:\xff"} {
+				dbTx.Put(key, []byte(key))
+			}
+			return nil
+		}))
+
+		assert.Nil(t, db.View(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			assert.Equal(t, []string{"a:1", "b:1", "b:2", "b:3", "b:\xff", "c"}, collect(dbTx, "", "", 0))
+			assert.Equal(t, []string{"b:1", "b:2"}, collect(dbTx, "b:", "b:3", 0))
+			assert.Equal(t, []string{"b:2", "b:3", "b:\xff", "c"}, collect(dbTx, "b:2", "", 0))
+			assert.Equal(t, []string{"a:1", "b:1"}, collect(dbTx, "", "", 2))
+			assert.Equal(t, []string{"b:1", "b:2", "b:3", "b:\xff"}, collectPrefix(dbTx, "b:", 0))
+			assert.Equal(t, []string{"b:1", "b:2"}, collectPrefix(dbTx, "b:", 2))
+			assert.Nil(t, collectPrefix(dbTx, "d", 0))
+
+			count := 0
+			dbTx.Iterate("", "", 0, func(key string, value []byte) bool {
+				count++
+				return count < 3
+			})
+			assert.Equal(t, 3, count)
+			return nil
+		}))
+
+		//uncommitted changes are visible inside the transaction
+		assert.Nil(t, db.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			dbTx.Delete("b:2")
+			dbTx.Put("b:0", []byte("b:0"))
+			assert.Equal(t, []string{"b:0", "b:1", "b:3", "b:\xff"}, collectPrefix(dbTx, "b:", 0))
+			return nil
+		}))
+
+		assert.Nil(t, db.View(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+			assert.Equal(t, []string{"b:0", "b:1", "b:3", "b:\xff"}, collectPrefix(dbTx, "b:", 0))
+			return nil
+		}))
+	})
+}
+
+func TestPrefixEnd(t *testing.T) {
+	assert.Equal(t, "b", store_db_interface.PrefixEnd("a"))
+	assert.Equal(t, "a;", store_db_interface.PrefixEnd("a:"))
+	assert.Equal(t, "b", store_db_interface.PrefixEnd("a\xff\xff"))
+	assert.Equal(t, "", store_db_interface.PrefixEnd("\xff"))
+	assert.Equal(t, "", store_db_interface.PrefixEnd(""))
+}
@@ -9,6 +9,7 @@ import (
 	"pandora-pay/store/store_db/store_db_bolt"
 	"pandora-pay/store/store_db/store_db_bunt"
 	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_leveldb"
 	"pandora-pay/store/store_db/store_db_memory"
 )
 
@@ -24,6 +25,8 @@ func createStoreNow(name, storeType string) (*Store, error) {
 		db, err = store_db_bunt.CreateStoreDBBunt(name, false)
 	case "bunt-memory":
 		db, err = store_db_bunt.CreateStoreDBBunt(name, true)
+	case "leveldb":
+		db, err = store_db_leveldb.CreateStoreDBLevelDB(name)
 	case "memory":
 		db, err = store_db_memory.CreateStoreDBMemory(name)
 	default:
@@ -46,7 +49,7 @@ func create_db() (err error) {
 
 	var prefix = ""
 
-	allowedStores := map[string]bool{"bolt": true, "bunt": true, "bunt-memory": true, "memory": true}
+	allowedStores := map[string]bool{"bolt": true, "bunt": true, "bunt-memory": true, "leveldb": true, "memory": true}
 
 	if StoreBlockchain, err = createStoreNow(prefix+"/blockchain", getStoreType(arguments.Arguments["--store-chain-type"].(string), allowedStores)); err != nil {
 		return
@@ -7,7 +7,11 @@ import (
 	"pandora-pay/blockchain/data_storage/assets/asset"
 	"pandora-pay/blockchain/data_storage/plain_accounts"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account_multisig"
 	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/config/config_fees"
 	"pandora-pay/mempool"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/store"
@@ -50,6 +54,24 @@ func (builder *TxsBuilderType) convertFloatAmounts(amounts []float64, ast *asset
 	return amountsFinal, nil
 }
 
+//the fee per byte computed automatically is estimated from the recent blocks and the mempool backlog
+func (builder *TxsBuilderType) estimateFee(fee *wizard.WizardTransactionFee, version transaction_type.TransactionVersion) (*wizard.WizardTransactionFee, error) {
+
+	if fee.Fixed > 0 || fee.PerByte > 0 || !fee.PerByteAuto {
+		return fee, nil
+	}
+
+	estimate, err := builder.mempool.EstimateFee(version, 0, 1, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fee = fee.Clone()
+	fee.PerByte = estimate.Recommended
+	fee.PerByteExtraSpace = config_fees.FEE_PER_BYTE_EXTRA_SPACE
+	return fee, nil
+}
+
 func (builder *TxsBuilderType) getWalletAddresses(senders []string) ([]*wallet_address.WalletAddress, error) {
 
 	sendersWalletAddress := make([]*wallet_address.WalletAddress, len(senders))
@@ -89,19 +111,61 @@ func (builder *TxsBuilderType) CreateSimpleTx(txData *TxBuilderCreateSimpleTx, p
 
 	statusCallback("Wallet Addresses Found")
 
+	fee, err := builder.estimateFee(txData.Fee, transaction_type.TX_SIMPLE)
+	if err != nil {
+		return nil, err
+	}
+
 	transfer := &wizard.WizardTxSimpleTransfer{
 		txData.Extra,
 		txData.Data,
-		txData.Fee,
+		fee,
 		txData.Nonce,
 		nil,
+		txData.MultisigAccount,
+		0,
 	}
 
 	var tx *transaction.Transaction
 	var plainAcc *plain_account.PlainAccount
+	var multisig *plain_account_multisig.PlainAccountMultisig
 	var chainHeight uint64
 
-	if len(sendersWalletAddresses) > 0 {
+	if txData.MultisigAccount != nil {
+
+		if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+			if multisig, err = plain_accounts.NewPlainAccountsMultisig(reader).Get(string(txData.MultisigAccount)); err != nil {
+				return
+			}
+			if multisig == nil {
+				return errors.New("Multisig Plain Account doesn't exist")
+			}
+
+			if plainAcc, err = plain_accounts.NewPlainAccounts(reader).Get(string(txData.MultisigAccount)); err != nil {
+				return
+			}
+			if plainAcc == nil {
+				return errors.New("Plain Account doesn't exist")
+			}
+
+			return
+		}); err != nil {
+			return nil, err
+		}
+
+		statusCallback("Getting Nonce from Mempool")
+		transfer.Nonce = builder.getNonce(txData.Nonce, txData.MultisigAccount, plainAcc.Nonce)
+		transfer.MultisigThreshold = multisig.Threshold
+
+		if len(sendersWalletAddresses) > 0 {
+			if !multisig.HasPublicKey(sendersWalletAddresses[0].PublicKey) {
+				return nil, errors.New("Sender is not a cosigner of the multisig")
+			}
+			transfer.Key = sendersWalletAddresses[0].PrivateKey.Key
+		}
+
+	} else if len(sendersWalletAddresses) > 0 {
 
 		if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
 
@@ -129,6 +193,11 @@ func (builder *TxsBuilderType) CreateSimpleTx(txData *TxBuilderCreateSimpleTx, p
 	}
 	statusCallback("Transaction Created")
 
+	//the multisig will be propagated once the cosigners signatures are aggregated
+	if multisig != nil && multisig.VerifySigners(tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple).Vin.MultisigPublicKeys) != nil {
+		return tx, nil
+	}
+
 	if propagateTx {
 		if err = builder.mempool.AddTxToMempool(tx, chainHeight, true, awaitAnswer, awaitBroadcast, advanced_connection_types.UUID_ALL, ctx); err != nil {
 			return nil, err
@@ -138,6 +207,44 @@ func (builder *TxsBuilderType) CreateSimpleTx(txData *TxBuilderCreateSimpleTx, p
 	return tx, nil
 }
 
+// AggregateSimpleTxMultisig merges the cosigners signatures of a multisig transaction and propagates it once the threshold is met
+func (builder *TxsBuilderType) AggregateSimpleTxMultisig(txs []*transaction.Transaction, propagateTx, awaitAnswer, awaitBroadcast bool, ctx context.Context) (*transaction.Transaction, error) {
+
+	if len(txs) == 0 {
+		return nil, errors.New("No transactions to aggregate")
+	}
+
+	tx := txs[0]
+	if err := wizard.AggregateSimpleTxMultisigSignatures(tx, txs[1:]); err != nil {
+		return nil, err
+	}
+
+	vin := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple).Vin
+
+	var multisig *plain_account_multisig.PlainAccountMultisig
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		multisig, err = plain_accounts.NewPlainAccountsMultisig(reader).Get(string(vin.PublicKey))
+		return
+	}); err != nil {
+		return nil, err
+	}
+	if multisig == nil {
+		return nil, errors.New("Multisig Plain Account doesn't exist")
+	}
+
+	if err := multisig.VerifySigners(vin.MultisigPublicKeys); err != nil {
+		return tx, err
+	}
+
+	if propagateTx {
+		if err := builder.mempool.AddTxToMempool(tx, 0, true, awaitAnswer, awaitBroadcast, advanced_connection_types.UUID_ALL, ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
 func TxsBuilderInit(wallet *wallet.Wallet, mempool *mempool.Mempool) error {
 
 	TxsBuilder = &TxsBuilderType{
@@ -11,15 +11,21 @@ import (
 	"pandora-pay/addresses"
 	"pandora-pay/blockchain/data_storage/assets"
 	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/blockchain/data_storage/conditional_payments_list/conditional_payment"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/asset_fee_liquidity"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account_multisig"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
+	"pandora-pay/config"
 	"pandora-pay/config/config_assets"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography"
 	"pandora-pay/gui"
 	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/helpers/files"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
@@ -186,6 +192,22 @@ func (builder *TxsBuilderType) readAsset(text string, allowEmptyAsset bool) []by
 	return assetId
 }
 
+//the multisig plain account pays the tx and the selected address signs as one of its cosigners
+func (builder *TxsBuilderType) readMultisigAccount(txData *TxBuilderCreateSimpleTx) {
+	if gui.GUI.OutputReadBool("Pay from a multisig plain account? y/n. Leave empty for no", true, false) {
+		txData.MultisigAccount = gui.GUI.OutputReadBytes("Multisig plain account", func(val []byte) bool {
+			return plain_account_multisig.IsMultisigKey(val)
+		})
+	}
+}
+
+func (builder *TxsBuilderType) outputSimpleTx(tx *transaction.Transaction, cmd string) {
+	gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+	if txBase := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple); txBase.HasVin() && txBase.Vin.IsMultisig() {
+		gui.GUI.OutputWrite(fmt.Sprintf("Multisig Tx signed by %d cosigners: %s", len(txBase.Vin.MultisigSignatures), base64.StdEncoding.EncodeToString(tx.Bloom.Serialized)))
+	}
+}
+
 func (builder *TxsBuilderType) initCLI() {
 
 	cliPrivateTransfer := func(cmd string, ctx context.Context) (err error) {
@@ -347,6 +369,197 @@ func (builder *TxsBuilderType) initCLI() {
 		return
 	}
 
+	cliPrivateAssetSupplyDecrease := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		extra := &wizard.WizardZetherPayloadExtraAssetSupplyDecrease{}
+		txData := &TxBuilderCreateZetherTxData{
+			Payloads: []*TxBuilderCreateZetherTxPayload{{
+				Extra: extra,
+			}},
+		}
+
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will burn the asset", ctx); err != nil {
+			return
+		}
+
+		txData.Payloads[0].Asset = builder.readAsset("Asset", false)
+
+		extra.AssetSupplyPrivateKey = gui.GUI.OutputReadBytes("Asset Supply Update Private Key", func(value []byte) bool {
+			return len(value) == cryptography.PrivateKeySize
+		})
+
+		if txData.Payloads[0].Burn, err = builder.readAmount(txData.Payloads[0].Asset, "Burn Amount"); err != nil {
+			return
+		}
+
+		if _, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Transfer Address", txData.Payloads[0].Asset, true); err != nil {
+			return
+		}
+
+		builder.readZetherRingConfiguration(txData.Payloads[0])
+		txData.Payloads[0].Data = builder.readData()
+		txData.Payloads[0].Fee = builder.readZetherFee(txData.Payloads[0].Asset)
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+
+		return
+	}
+
+	cliPrivateAssetPause := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		extra := &wizard.WizardZetherPayloadExtraAssetPause{}
+		txData := &TxBuilderCreateZetherTxData{
+			Payloads: []*TxBuilderCreateZetherTxPayload{{
+				Extra: extra,
+				Asset: config_coins.NATIVE_ASSET_FULL,
+			}},
+		}
+
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will pause or resume the asset", ctx); err != nil {
+			return
+		}
+
+		extra.AssetId = builder.readAsset("Asset", false)
+		extra.Paused = gui.GUI.OutputReadBool("Pause asset? y/n. Leave empty for yes. Use n to resume it", true, true)
+
+		extra.AssetUpdatePrivateKey = gui.GUI.OutputReadBytes("Asset Update Private Key", func(value []byte) bool {
+			return len(value) == cryptography.PrivateKeySize
+		})
+
+		if _, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Transfer Address", config_coins.NATIVE_ASSET_FULL, true); err != nil {
+			return
+		}
+
+		builder.readZetherRingConfiguration(txData.Payloads[0])
+		txData.Payloads[0].Data = builder.readData()
+		txData.Payloads[0].Fee = builder.readZetherFee(config_coins.NATIVE_ASSET_FULL)
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+
+		return
+	}
+
+	cliPrivateAssetFreeze := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		extra := &wizard.WizardZetherPayloadExtraAssetFreeze{}
+		txData := &TxBuilderCreateZetherTxData{
+			Payloads: []*TxBuilderCreateZetherTxPayload{{
+				Extra: extra,
+				Asset: config_coins.NATIVE_ASSET_FULL,
+			}},
+		}
+
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will freeze the asset", ctx); err != nil {
+			return
+		}
+
+		extra.AssetId = builder.readAsset("Asset", false)
+
+		extra.AssetUpdatePrivateKey = gui.GUI.OutputReadBytes("Asset Update Private Key", func(value []byte) bool {
+			return len(value) == cryptography.PrivateKeySize
+		})
+
+		if _, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Transfer Address", config_coins.NATIVE_ASSET_FULL, true); err != nil {
+			return
+		}
+
+		builder.readZetherRingConfiguration(txData.Payloads[0])
+		txData.Payloads[0].Data = builder.readData()
+		txData.Payloads[0].Fee = builder.readZetherFee(config_coins.NATIVE_ASSET_FULL)
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+
+		return
+	}
+
+	cliPrivateAssetUpdate := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		extra := &wizard.WizardZetherPayloadExtraAssetUpdate{}
+		txData := &TxBuilderCreateZetherTxData{
+			Payloads: []*TxBuilderCreateZetherTxPayload{{
+				Extra: extra,
+				Asset: config_coins.NATIVE_ASSET_FULL,
+			}},
+		}
+
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will update the asset", ctx); err != nil {
+			return
+		}
+
+		extra.AssetId = builder.readAsset("Asset", false)
+
+		if extra.ChangeDescription = gui.GUI.OutputReadBool("Change Description? y/n. Leave empty for no", true, false); extra.ChangeDescription {
+			extra.Description = gui.GUI.OutputReadString("New Description")
+		}
+		if extra.ChangeData = gui.GUI.OutputReadBool("Change Data? y/n. Leave empty for no", true, false); extra.ChangeData {
+			extra.Data = []byte(gui.GUI.OutputReadString("New Data"))
+		}
+		if extra.ChangeMaxSupply = gui.GUI.OutputReadBool("Change Max Supply? y/n. Leave empty for no", true, false); extra.ChangeMaxSupply {
+			if extra.MaxSupply, err = builder.readAmount(extra.AssetId, "New Max Supply"); err != nil {
+				return
+			}
+		}
+
+		extra.NewUpdatePublicKey = gui.GUI.OutputReadBytes("New Asset Update Public Key. Leave empty for no change", func(value []byte) bool {
+			return len(value) == 0 || len(value) == cryptography.PublicKeySize
+		})
+		extra.NewSupplyPublicKey = gui.GUI.OutputReadBytes("New Asset Supply Public Key. Leave empty for no change", func(value []byte) bool {
+			return len(value) == 0 || len(value) == cryptography.PublicKeySize
+		})
+
+		extra.AssetUpdatePrivateKey = gui.GUI.OutputReadBytes("Asset Update Private Key", func(value []byte) bool {
+			return len(value) == cryptography.PrivateKeySize
+		})
+
+		if _, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Transfer Address", config_coins.NATIVE_ASSET_FULL, true); err != nil {
+			return
+		}
+
+		builder.readZetherRingConfiguration(txData.Payloads[0])
+		txData.Payloads[0].Data = builder.readData()
+		txData.Payloads[0].Fee = builder.readZetherFee(config_coins.NATIVE_ASSET_FULL)
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+
+		return
+	}
+
 	cliPrivatePlainAccountFund := func(cmd string, ctx context.Context) (err error) {
 		builder.showWarningIfNotSyncCLI()
 
@@ -417,26 +630,41 @@ func (builder *TxsBuilderType) initCLI() {
 			return val >= 10 && val <= 100000
 		})
 
-		extra.DefaultResolution = gui.GUI.OutputReadBool("Default Resolution: y - reciever, n - sender", false, false)
-
-		extra.Threshold = byte(gui.GUI.OutputReadUint64("Threshold", true, 1, func(val uint64) bool {
-			return val >= 1 && val <= 5
-		}))
+		if gui.GUI.OutputReadBool("Hashlock payment? y - resolved by revealing a preimage, n - multisig", false, false) {
 
-		extra.MultisigPublicKeys = [][]byte{}
-		unique := make(map[string]bool)
-		for {
-			pubKey := gui.GUI.OutputReadBytes(fmt.Sprintf("PublicKey %d used in multisig payment", len(extra.MultisigPublicKeys)), func(val []byte) bool {
-				return len(val) == 0 || len(val) == cryptography.PublicKeySize
+			extra.Hashlock = gui.GUI.OutputReadBytes("Hashlock (SHA256 of the preimage). Leave empty to generate a new preimage", func(val []byte) bool {
+				return len(val) == 0 || len(val) == cryptography.HashSize
 			})
-			if len(pubKey) == 0 {
-				break
+			if len(extra.Hashlock) == 0 {
+				preimage := cryptography.RandomHash()
+				extra.Hashlock = conditional_payment.ComputeHashlock(preimage)
+				gui.GUI.OutputWrite(fmt.Sprintf("Preimage: %s. Keep it secret until the swap", base64.StdEncoding.EncodeToString(preimage)))
 			}
-			if unique[string(pubKey)] {
-				gui.GUI.OutputWrite("PublicKey already include")
-				continue
+			gui.GUI.OutputWrite(fmt.Sprintf("Hashlock: %s", base64.StdEncoding.EncodeToString(extra.Hashlock)))
+
+		} else {
+
+			extra.DefaultResolution = gui.GUI.OutputReadBool("Default Resolution: y - reciever, n - sender", false, false)
+
+			extra.Threshold = byte(gui.GUI.OutputReadUint64("Threshold", true, 1, func(val uint64) bool {
+				return val >= 1 && val <= 5
+			}))
+
+			extra.MultisigPublicKeys = [][]byte{}
+			unique := make(map[string]bool)
+			for {
+				pubKey := gui.GUI.OutputReadBytes(fmt.Sprintf("PublicKey %d used in multisig payment", len(extra.MultisigPublicKeys)), func(val []byte) bool {
+					return len(val) == 0 || len(val) == cryptography.PublicKeySize
+				})
+				if len(pubKey) == 0 {
+					break
+				}
+				if unique[string(pubKey)] {
+					gui.GUI.OutputWrite("PublicKey already include")
+					continue
+				}
+				extra.MultisigPublicKeys = append(extra.MultisigPublicKeys, pubKey)
 			}
-			extra.MultisigPublicKeys = append(extra.MultisigPublicKeys, pubKey)
 		}
 
 		if _, txData.Payloads[1].Recipient, txData.Payloads[1].Amount, err = builder.readAddressOptional("Transfer Address (optional)", config_coins.NATIVE_ASSET_FULL, true); err != nil {
@@ -487,6 +715,7 @@ func (builder *TxsBuilderType) initCLI() {
 		if _, txData.Sender, _, err = builder.wallet.CliSelectAddress("Select Address to Publicly Update Asset Fee Liquidity", ctx); err != nil {
 			return
 		}
+		builder.readMultisigAccount(txData)
 
 		var addr *addresses.Address
 		if addr, err = builder.readAddress("Collector address. Leave empty for no new address", true); err != nil {
@@ -524,7 +753,7 @@ func (builder *TxsBuilderType) initCLI() {
 			return
 		}
 
-		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+		builder.outputSimpleTx(tx, cmd)
 		return
 	}
 
@@ -600,12 +829,132 @@ func (builder *TxsBuilderType) initCLI() {
 		return
 	}
 
+	cliResolutionConditionalPaymentHashlock := func(cmd string, ctx context.Context) (err error) {
+
+		builder.showWarningIfNotSyncCLI()
+
+		txExtra := &wizard.WizardTxSimpleExtraResolutionConditionalPaymentHashlock{}
+		txData := &TxBuilderCreateSimpleTx{
+			Extra:      txExtra,
+			Fee:        &wizard.WizardTransactionFee{0, 0, 0, false},
+			FeeVersion: true,
+		}
+
+		txExtra.TxId = gui.GUI.OutputReadBytes("Provide TxId", func(val []byte) bool {
+			return len(val) == cryptography.HashSize
+		})
+
+		txExtra.PayloadIndex = byte(gui.GUI.OutputReadInt("Payload index", false, 0, func(val int) bool {
+			return val >= 0 && val < 255
+		}))
+
+		txExtra.Preimage = gui.GUI.OutputReadBytes("Preimage", func(val []byte) bool {
+			return len(val) == config.HASHLOCK_PREIMAGE_SIZE
+		})
+
+		txData.Nonce = 0
+		txData.Data = builder.readData()
+
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.CreateSimpleTx(txData, propagate, true, true, false, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+		return
+	}
+
+	cliRegisterMultisigPlainAccount := func(cmd string, ctx context.Context) (err error) {
+
+		builder.showWarningIfNotSyncCLI()
+
+		txExtra := &wizard.WizardTxSimpleExtraPlainAccountMultisigRegister{}
+		txData := &TxBuilderCreateSimpleTx{
+			Extra:      txExtra,
+			FeeVersion: true,
+		}
+
+		if _, txData.Sender, _, err = builder.wallet.CliSelectAddress("Select Address to pay the Multisig registration", ctx); err != nil {
+			return
+		}
+		builder.readMultisigAccount(txData)
+
+		for i := 0; i < plain_account_multisig.MULTISIG_MAX_PUBLIC_KEYS; i++ {
+			key := gui.GUI.OutputReadBytes(fmt.Sprintf("Cosigner Public Key %d. Use enter to continue", i), func(key []byte) bool {
+				return len(key) == cryptography.PublicKeySize || len(key) == 0
+			})
+			if len(key) == 0 {
+				break
+			}
+			txExtra.PublicKeys = append(txExtra.PublicKeys, key)
+		}
+
+		txExtra.Threshold = byte(gui.GUI.OutputReadUint64("Threshold", false, 0, func(value uint64) bool {
+			return value > 0 && value <= uint64(len(txExtra.PublicKeys))
+		}))
+
+		txData.Nonce = gui.GUI.OutputReadUint64("Nonce. Leave empty for automatically detection", true, 0, nil)
+		txData.Data = builder.readData()
+		txData.Fee = builder.readFee(config_coins.NATIVE_ASSET_FULL)
+
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.CreateSimpleTx(txData, propagate, true, true, false, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		extra := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple).Extra.(*transaction_simple_extra.TransactionSimpleExtraPlainAccountMultisigRegister)
+		gui.GUI.OutputWrite(fmt.Sprintf("Multisig plain account: %s", base64.StdEncoding.EncodeToString(plain_account_multisig.ComputeMultisigKey(extra.Threshold, extra.PublicKeys))))
+		builder.outputSimpleTx(tx, cmd)
+		return
+	}
+
+	cliAggregateMultisigSignatures := func(cmd string, ctx context.Context) (err error) {
+
+		builder.showWarningIfNotSyncCLI()
+
+		var txs []*transaction.Transaction
+		for {
+			data := gui.GUI.OutputReadBytes(fmt.Sprintf("Signed Multisig Tx %d. Use enter to continue", len(txs)), nil)
+			if len(data) == 0 {
+				break
+			}
+			tx := &transaction.Transaction{}
+			if err = tx.Deserialize(advanced_buffers.NewBufferReader(data)); err != nil {
+				return
+			}
+			txs = append(txs, tx)
+		}
+
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.AggregateSimpleTxMultisig(txs, propagate, true, true, ctx)
+		if tx != nil {
+			builder.outputSimpleTx(tx, cmd)
+		}
+		return
+	}
+
 	gui.GUI.CommandDefineCallback("Private Transfer", cliPrivateTransfer, true)
 	gui.GUI.CommandDefineCallback("Private Asset Create", cliPrivateAssetCreate, true)
 	gui.GUI.CommandDefineCallback("Private Asset Supply Increase", cliPrivateAssetSupplyIncrease, true)
+	gui.GUI.CommandDefineCallback("Private Asset Supply Decrease", cliPrivateAssetSupplyDecrease, true)
+	gui.GUI.CommandDefineCallback("Private Asset Pause", cliPrivateAssetPause, true)
+	gui.GUI.CommandDefineCallback("Private Asset Freeze", cliPrivateAssetFreeze, true)
+	gui.GUI.CommandDefineCallback("Private Asset Update", cliPrivateAssetUpdate, true)
 	gui.GUI.CommandDefineCallback("Private Plain Account Fund", cliPrivatePlainAccountFund, true)
 	gui.GUI.CommandDefineCallback("Private Conditional Payment", cliPrivateConditionalPayment, true)
 	gui.GUI.CommandDefineCallback("Public Update Asset Fee Liquidity", cliUpdateAssetFeeLiquidity, true)
 	gui.GUI.CommandDefineCallback("Public Resolution Conditional Payment", cliResolutionConditionalPayment, true)
+	gui.GUI.CommandDefineCallback("Public Resolution Conditional Payment Hashlock", cliResolutionConditionalPaymentHashlock, true)
+	gui.GUI.CommandDefineCallback("Public Register Multisig Plain Account", cliRegisterMultisigPlainAccount, true)
+	gui.GUI.CommandDefineCallback("Aggregate Multisig Signatures", cliAggregateMultisigSignatures, true)
 
 }
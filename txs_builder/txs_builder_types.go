@@ -9,4 +9,6 @@ type TxBuilderCreateSimpleTx struct {
 	Fee        *wizard.WizardTransactionFee  `json:"fee" msgpack:"fee"`
 	FeeVersion bool                          `json:"feeVersion" msgpack:"feeVersion"`
 	Extra      wizard.WizardTxSimpleExtra    `json:"extra" msgpack:"sender"`
+	//when set, the multisig plain account pays and the Sender (optional) signs as one of its cosigners
+	MultisigAccount []byte `json:"multisigAccount,omitempty" msgpack:"multisigAccount,omitempty"`
 }
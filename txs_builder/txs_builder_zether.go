@@ -18,6 +18,7 @@ import (
 	"pandora-pay/blockchain/data_storage/registrations"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
 	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography/bn256"
 	"pandora-pay/cryptography/crypto"
@@ -625,7 +626,9 @@ func (builder *TxsBuilderType) CreateZetherTx(txData *TxBuilderCreateZetherTxDat
 
 	feesFinal := make([]*wizard.WizardTransactionFee, len(txData.Payloads))
 	for t, payload := range txData.Payloads {
-		feesFinal[t] = payload.Fee.WizardTransactionFee
+		if feesFinal[t], err = builder.estimateFee(payload.Fee.WizardTransactionFee, transaction_type.TX_ZETHER); err != nil {
+			return nil, err
+		}
 	}
 
 	var tx *transaction.Transaction
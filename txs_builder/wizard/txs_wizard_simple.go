@@ -1,6 +1,7 @@
 package wizard
 
 import (
+	"bytes"
 	"errors"
 	"pandora-pay/addresses"
 	"pandora-pay/blockchain/transactions/transaction"
@@ -10,6 +11,7 @@ import (
 	"pandora-pay/blockchain/transactions/transaction/transaction_type"
 	"pandora-pay/cryptography"
 	"pandora-pay/helpers"
+	"sort"
 )
 
 func CreateSimpleTx(transfer *WizardTxSimpleTransfer, validateTx bool, statusCallback func(string)) (tx2 *transaction.Transaction, err error) {
@@ -57,12 +59,46 @@ func CreateSimpleTx(transfer *WizardTxSimpleTransfer, validateTx bool, statusCal
 		}
 		txBase.TxScript = transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT
 		transfer.Fee = &WizardTransactionFee{0, 0, 0, false}
+	case *WizardTxSimpleExtraResolutionConditionalPaymentHashlock:
+		txBase.Extra = &transaction_simple_extra.TransactionSimpleExtraResolutionConditionalPaymentHashlock{nil,
+			txExtra.TxId,
+			txExtra.PayloadIndex,
+			txExtra.Preimage,
+		}
+		txBase.TxScript = transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK
+		transfer.Fee = &WizardTransactionFee{0, 0, 0, false}
+	case *WizardTxSimpleExtraPlainAccountMultisigRegister:
+		publicKeys := append([][]byte{}, txExtra.PublicKeys...)
+		sort.Slice(publicKeys, func(i, j int) bool {
+			return bytes.Compare(publicKeys[i], publicKeys[j]) < 0
+		})
+
+		txBase.Extra = &transaction_simple_extra.TransactionSimpleExtraPlainAccountMultisigRegister{nil,
+			txExtra.Threshold,
+			publicKeys,
+		}
+		txBase.TxScript = transaction_simple.SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER
+
+		spaceExtra += 1 + 1 + len(publicKeys)*cryptography.PublicKeySize
 	}
 
 	var privateKey *addresses.PrivateKey
 
 	switch txBase.TxScript {
-	case transaction_simple.SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY:
+	case transaction_simple.SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY, transaction_simple.SCRIPT_PLAIN_ACCOUNT_MULTISIG_REGISTER:
+		if transfer.MultisigAccount != nil {
+			//the multisig can be created without any cosigner key and signed offline later
+			if transfer.Key != nil {
+				if privateKey, err = addresses.NewPrivateKey(transfer.Key); err != nil {
+					return nil, err
+				}
+			}
+			txBase.Vin = &transaction_simple_parts.TransactionSimpleInput{
+				PublicKey: transfer.MultisigAccount,
+			}
+			break
+		}
+
 		if privateKey, err = addresses.NewPrivateKey(transfer.Key); err != nil {
 			return nil, err
 		}
@@ -71,7 +107,7 @@ func CreateSimpleTx(transfer *WizardTxSimpleTransfer, validateTx bool, statusCal
 			PublicKey: privateKey.GeneratePublicKey(),
 		}
 
-	case transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT:
+	case transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT, transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK:
 	default:
 		return nil, errors.New("Invalid Tx Script")
 	}
@@ -84,13 +120,20 @@ func CreateSimpleTx(transfer *WizardTxSimpleTransfer, validateTx bool, statusCal
 	statusCallback("Transaction Created")
 
 	extraBytes := cryptography.SignatureSize
+	if transfer.MultisigAccount != nil {
+		extraBytes = 1 + int(transfer.MultisigThreshold)*(cryptography.PublicKeySize+cryptography.SignatureSize)
+	}
 	txBase.Fee = setFee(tx, extraBytes, transfer.Fee.Clone(), true)
 	statusCallback("Transaction Fee set")
 
 	statusCallback("Transaction Signing...")
 
 	if privateKey != nil {
-		if txBase.Vin.Signature, err = privateKey.Sign(tx.SerializeForSigning()); err != nil {
+		if txBase.Vin.IsMultisig() {
+			if err = signSimpleTxMultisig(txBase, tx.SerializeForSigning(), privateKey); err != nil {
+				return nil, err
+			}
+		} else if txBase.Vin.Signature, err = privateKey.Sign(tx.SerializeForSigning()); err != nil {
 			return nil, err
 		}
 		statusCallback("Transaction Signed")
@@ -107,7 +150,8 @@ func CreateSimpleTx(transfer *WizardTxSimpleTransfer, validateTx bool, statusCal
 		return nil, err
 	}
 
-	if validateTx {
+	//a multisig without signatures will be signed offline by the cosigners
+	if validateTx && (privateKey != nil || txBase.Vin == nil || !txBase.Vin.IsMultisig()) {
 		if !tx.VerifySignatureManually() {
 			return nil, errors.New("Created Transaction is invalid. Possible there are wrong signatures.")
 		}
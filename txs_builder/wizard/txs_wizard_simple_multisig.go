@@ -0,0 +1,111 @@
+package wizard
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
+)
+
+func getSimpleTxMultisig(tx *transaction.Transaction) (*transaction_simple.TransactionSimple, error) {
+	txBase, ok := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+	if !ok || !txBase.HasVin() || !txBase.Vin.IsMultisig() {
+		return nil, errors.New("Transaction is not spending a multisig plain account")
+	}
+	return txBase, nil
+}
+
+func signSimpleTxMultisig(txBase *transaction_simple.TransactionSimple, hashForSignature []byte, privateKey *addresses.PrivateKey) error {
+
+	publicKey := privateKey.GeneratePublicKey()
+	for _, multisigPublicKey := range txBase.Vin.MultisigPublicKeys {
+		if bytes.Equal(multisigPublicKey, publicKey) {
+			return errors.New("Transaction was already signed by this key")
+		}
+	}
+
+	signature, err := privateKey.Sign(hashForSignature)
+	if err != nil {
+		return err
+	}
+
+	txBase.Vin.MultisigPublicKeys = append(txBase.Vin.MultisigPublicKeys, publicKey)
+	txBase.Vin.MultisigSignatures = append(txBase.Vin.MultisigSignatures, signature)
+	return nil
+}
+
+//signatures change the tx hash
+func rebloomSimpleTxMultisig(tx *transaction.Transaction, txBase *transaction_simple.TransactionSimple) error {
+	tx.Bloom = nil
+	txBase.Bloom = nil
+	if err := tx.BloomAll(); err != nil {
+		return err
+	}
+	if err := tx.Verify(); err != nil {
+		return err
+	}
+	if !tx.VerifySignatureManually() {
+		return errors.New("Multisig signatures are invalid")
+	}
+	return nil
+}
+
+// SignSimpleTxMultisig adds the signature of a cosigner to a transaction spending a multisig plain account
+func SignSimpleTxMultisig(tx *transaction.Transaction, key []byte) error {
+
+	txBase, err := getSimpleTxMultisig(tx)
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := addresses.NewPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err = signSimpleTxMultisig(txBase, tx.SerializeForSigning(), privateKey); err != nil {
+		return err
+	}
+
+	return rebloomSimpleTxMultisig(tx, txBase)
+}
+
+// AggregateSimpleTxMultisigSignatures merges into tx the cosigner signatures of other copies of the same transaction signed offline
+func AggregateSimpleTxMultisigSignatures(tx *transaction.Transaction, others []*transaction.Transaction) error {
+
+	txBase, err := getSimpleTxMultisig(tx)
+	if err != nil {
+		return err
+	}
+
+	hashForSignature := tx.SerializeForSigning()
+
+	unique := make(map[string]bool)
+	for _, publicKey := range txBase.Vin.MultisigPublicKeys {
+		unique[string(publicKey)] = true
+	}
+
+	for _, other := range others {
+
+		otherBase, err := getSimpleTxMultisig(other)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(other.SerializeForSigning(), hashForSignature) {
+			return errors.New("Transactions are different")
+		}
+
+		for i, publicKey := range otherBase.Vin.MultisigPublicKeys {
+			if unique[string(publicKey)] {
+				continue
+			}
+			unique[string(publicKey)] = true
+			txBase.Vin.MultisigPublicKeys = append(txBase.Vin.MultisigPublicKeys, publicKey)
+			txBase.Vin.MultisigSignatures = append(txBase.Vin.MultisigSignatures, otherBase.Vin.MultisigSignatures[i])
+		}
+	}
+
+	return rebloomSimpleTxMultisig(tx, txBase)
+}
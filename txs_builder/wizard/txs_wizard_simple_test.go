@@ -1 +1,127 @@
 package wizard
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage/conditional_payments_list/conditional_payment"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account_multisig"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers/advanced_buffers"
+	"testing"
+)
+
+func TestCreateSimpleTxResolutionConditionalPaymentHashlock(t *testing.T) {
+
+	preimage := cryptography.RandomHash()
+	txId := cryptography.RandomHash()
+
+	tx, err := CreateSimpleTx(&WizardTxSimpleTransfer{
+		&WizardTxSimpleExtraResolutionConditionalPaymentHashlock{nil, txId, 1, preimage},
+		&WizardTransactionData{},
+		&WizardTransactionFee{},
+		0,
+		nil,
+		nil,
+		0,
+	}, true, func(string) {})
+	assert.Nil(t, err)
+
+	tx2 := &transaction.Transaction{}
+	assert.Nil(t, tx2.Deserialize(advanced_buffers.NewBufferReader(tx.Bloom.Serialized)))
+
+	base := tx2.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+	assert.Equal(t, transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT_HASHLOCK, base.TxScript)
+	assert.Equal(t, uint64(0), base.Fee)
+
+	extra := base.Extra.(*transaction_simple_extra.TransactionSimpleExtraResolutionConditionalPaymentHashlock)
+	assert.Equal(t, txId, extra.TxId)
+	assert.Equal(t, byte(1), extra.PayloadIndex)
+	assert.Equal(t, preimage, extra.Preimage)
+
+	data, err := tx.MarshalJSON()
+	assert.Nil(t, err)
+	tx3 := &transaction.Transaction{}
+	assert.Nil(t, tx3.UnmarshalJSON(data))
+	assert.Equal(t, extra, tx3.TransactionBaseInterface.(*transaction_simple.TransactionSimple).Extra)
+
+	assert.Len(t, conditional_payment.ComputeHashlock(preimage), cryptography.HashSize)
+
+	_, err = CreateSimpleTx(&WizardTxSimpleTransfer{
+		&WizardTxSimpleExtraResolutionConditionalPaymentHashlock{nil, txId, 1, preimage[1:]},
+		&WizardTransactionData{},
+		&WizardTransactionFee{},
+		0,
+		nil,
+		nil,
+		0,
+	}, true, func(string) {})
+	assert.NotNil(t, err, "the preimage must have 32 bytes")
+}
+
+func TestCreateSimpleTxMultisig(t *testing.T) {
+
+	privateKeys := make([]*addresses.PrivateKey, 3)
+	publicKeys := make([][]byte, 3)
+	for i := range privateKeys {
+		privateKeys[i] = addresses.GenerateNewPrivateKey()
+		publicKeys[i] = privateKeys[i].GeneratePublicKey()
+	}
+
+	tx, err := CreateSimpleTx(&WizardTxSimpleTransfer{
+		&WizardTxSimpleExtraPlainAccountMultisigRegister{nil, 2, publicKeys},
+		&WizardTransactionData{},
+		&WizardTransactionFee{},
+		0,
+		privateKeys[0].Key,
+		nil,
+		0,
+	}, true, func(string) {})
+	assert.Nil(t, err)
+
+	extra := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple).Extra.(*transaction_simple_extra.TransactionSimpleExtraPlainAccountMultisigRegister)
+	assert.Nil(t, plain_account_multisig.ValidateKeys(extra.Threshold, extra.PublicKeys), "public keys must be sorted")
+
+	multisigKey := plain_account_multisig.ComputeMultisigKey(extra.Threshold, extra.PublicKeys)
+	assert.True(t, plain_account_multisig.IsMultisigKey(multisigKey))
+
+	//the first cosigner creates the tx and the second one signs it offline
+	tx, err = CreateSimpleTx(&WizardTxSimpleTransfer{
+		&WizardTxSimpleExtraUpdateAssetFeeLiquidity{nil, nil, true, publicKeys[0]},
+		&WizardTransactionData{},
+		&WizardTransactionFee{},
+		0,
+		privateKeys[0].Key,
+		multisigKey,
+		2,
+	}, true, func(string) {})
+	assert.Nil(t, err)
+
+	tx2 := &transaction.Transaction{}
+	assert.Nil(t, tx2.Deserialize(advanced_buffers.NewBufferReader(tx.Bloom.Serialized)))
+	tx2.TransactionBaseInterface.(*transaction_simple.TransactionSimple).Vin.MultisigPublicKeys = nil
+	tx2.TransactionBaseInterface.(*transaction_simple.TransactionSimple).Vin.MultisigSignatures = nil
+	assert.Nil(t, SignSimpleTxMultisig(tx2, privateKeys[2].Key))
+	assert.NotNil(t, SignSimpleTxMultisig(tx2, privateKeys[2].Key), "a cosigner can't sign twice")
+
+	assert.Nil(t, AggregateSimpleTxMultisigSignatures(tx, []*transaction.Transaction{tx2}))
+
+	multisig := &plain_account_multisig.PlainAccountMultisig{nil, 0, 0, extra.Threshold, extra.PublicKeys}
+	vin := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple).Vin
+	assert.Equal(t, 2, len(vin.MultisigSignatures))
+	assert.Nil(t, multisig.VerifySigners(vin.MultisigPublicKeys))
+	assert.True(t, tx.VerifySignatureManually())
+
+	tx3 := &transaction.Transaction{}
+	assert.Nil(t, tx3.Deserialize(advanced_buffers.NewBufferReader(tx.Bloom.Serialized)))
+	assert.True(t, tx3.VerifySignatureManually())
+
+	data, err := tx.MarshalJSON()
+	assert.Nil(t, err)
+	tx4 := &transaction.Transaction{}
+	assert.Nil(t, tx4.UnmarshalJSON(data))
+	assert.Nil(t, tx4.BloomAll())
+	assert.Equal(t, tx.Bloom.Hash, tx4.Bloom.Hash)
+}
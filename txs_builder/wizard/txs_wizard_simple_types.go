@@ -23,10 +23,25 @@ type WizardTxSimpleExtraResolutionConditionalPayment struct {
 	Signatures          [][]byte `json:"signatures" msgpack:"signatures"`
 }
 
+type WizardTxSimpleExtraResolutionConditionalPaymentHashlock struct {
+	WizardTxSimpleExtra `json:"-"  msgpack:"-"`
+	TxId                []byte `json:"txId" msgpack:"txId"`
+	PayloadIndex        byte   `json:"payloadIndex" msgpack:"payloadIndex"`
+	Preimage            []byte `json:"preimage" msgpack:"preimage"`
+}
+
+type WizardTxSimpleExtraPlainAccountMultisigRegister struct {
+	WizardTxSimpleExtra `json:"-"  msgpack:"-"`
+	Threshold           byte     `json:"threshold" msgpack:"threshold"`
+	PublicKeys          [][]byte `json:"publicKeys" msgpack:"publicKeys"`
+}
+
 type WizardTxSimpleTransfer struct {
-	Extra WizardTxSimpleExtra    `json:"extra" msgpack:"extra"`
-	Data  *WizardTransactionData `json:"data" msgpack:"data"`
-	Fee   *WizardTransactionFee  `json:"fee" msgpack:"fee"`
-	Nonce uint64                 `json:"nonce" msgpack:"nonce"`
-	Key   []byte                 `json:"key" msgpack:"key"`
+	Extra             WizardTxSimpleExtra    `json:"extra" msgpack:"extra"`
+	Data              *WizardTransactionData `json:"data" msgpack:"data"`
+	Fee               *WizardTransactionFee  `json:"fee" msgpack:"fee"`
+	Nonce             uint64                 `json:"nonce" msgpack:"nonce"`
+	Key               []byte                 `json:"key" msgpack:"key"`
+	MultisigAccount   []byte                 `json:"multisigAccount,omitempty" msgpack:"multisigAccount,omitempty"`     //when set, the vin is the multisig plain account and Key is one of its cosigners
+	MultisigThreshold byte                   `json:"multisigThreshold,omitempty" msgpack:"multisigThreshold,omitempty"` //used to estimate the fee
 }
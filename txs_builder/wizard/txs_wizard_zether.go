@@ -304,6 +304,58 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 
 				spaceExtra += 1 + len(payloadExtra.ReceiverPublicKey) + 66
 
+			case *WizardZetherPayloadExtraAssetSupplyDecrease:
+				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE
+				if privateKeysForSign[t], err = addresses.NewPrivateKey(payloadExtra.AssetSupplyPrivateKey); err != nil {
+					return
+				}
+				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease{nil,
+					privateKeysForSign[t].GeneratePublicKey(),
+					helpers.EmptyBytes(cryptography.SignatureSize),
+				}
+
+			case *WizardZetherPayloadExtraAssetPause:
+				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_ASSET_PAUSE
+				if privateKeysForSign[t], err = addresses.NewPrivateKey(payloadExtra.AssetUpdatePrivateKey); err != nil {
+					return
+				}
+				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause{nil,
+					payloadExtra.AssetId,
+					payloadExtra.Paused,
+					privateKeysForSign[t].GeneratePublicKey(),
+					helpers.EmptyBytes(cryptography.SignatureSize),
+				}
+
+			case *WizardZetherPayloadExtraAssetFreeze:
+				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_ASSET_FREEZE
+				if privateKeysForSign[t], err = addresses.NewPrivateKey(payloadExtra.AssetUpdatePrivateKey); err != nil {
+					return
+				}
+				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreeze{nil,
+					payloadExtra.AssetId,
+					privateKeysForSign[t].GeneratePublicKey(),
+					helpers.EmptyBytes(cryptography.SignatureSize),
+				}
+
+			case *WizardZetherPayloadExtraAssetUpdate:
+				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_ASSET_UPDATE
+				if privateKeysForSign[t], err = addresses.NewPrivateKey(payloadExtra.AssetUpdatePrivateKey); err != nil {
+					return
+				}
+				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdate{nil,
+					payloadExtra.AssetId,
+					payloadExtra.ChangeDescription,
+					payloadExtra.Description,
+					payloadExtra.ChangeData,
+					payloadExtra.Data,
+					payloadExtra.ChangeMaxSupply,
+					payloadExtra.MaxSupply,
+					payloadExtra.NewUpdatePublicKey,
+					payloadExtra.NewSupplyPublicKey,
+					privateKeysForSign[t].GeneratePublicKey(),
+					helpers.EmptyBytes(cryptography.SignatureSize),
+				}
+
 			case *WizardZetherPayloadExtraPlainAccountFund:
 				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND
 				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraPlainAccountFund{
@@ -317,6 +369,7 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 					payloadExtra.DefaultResolution,
 					payloadExtra.Threshold,
 					payloadExtra.MultisigPublicKeys,
+					payloadExtra.Hashlock,
 				}
 			default:
 				return errors.New("Invalid payload")
@@ -646,6 +699,14 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 			switch txBase.Payloads[t].PayloadScript {
 			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE:
 				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyIncrease).AssetSignature = signature
+			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
+				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease).AssetSignature = signature
+			case transaction_zether_payload_script.SCRIPT_ASSET_PAUSE:
+				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause).AssetSignature = signature
+			case transaction_zether_payload_script.SCRIPT_ASSET_FREEZE:
+				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreeze).AssetSignature = signature
+			case transaction_zether_payload_script.SCRIPT_ASSET_UPDATE:
+				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdate).AssetSignature = signature
 			case transaction_zether_payload_script.SCRIPT_SPEND:
 				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend).SenderSpendSignature = signature
 			}
@@ -16,6 +16,7 @@ import (
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/txs_validator"
 	"testing"
 )
 
@@ -38,7 +39,8 @@ func getInitialAmount() (amount uint64) {
 	return
 }
 
-func TestCreateZetherTx(t *testing.T) {
+//the sender pays count transfers of a random ring size
+func createTestZetherTx(t *testing.T, count int) (*transaction.Transaction, *addresses.Address) {
 
 	senderPrivateKey := addresses.GenerateNewPrivateKey()
 	senderAddress, err := senderPrivateKey.GenerateAddress(false, nil, true, nil, 0, nil)
@@ -46,7 +48,6 @@ func TestCreateZetherTx(t *testing.T) {
 
 	amount := getInitialAmount()
 
-	count := 5
 	emap := make(map[string]map[string][]byte)
 	ringsSenders := make([][]*bn256.G1, count)
 	ringsReceivers := make([][]*bn256.G1, count)
@@ -127,6 +128,14 @@ func TestCreateZetherTx(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, t, tx)
 
+	return tx, senderAddress
+}
+
+func TestCreateZetherTx(t *testing.T) {
+
+	count := 5
+	tx, senderAddress := createTestZetherTx(t, count)
+
 	serialized := tx.SerializeManualToBytes()
 
 	tx2 := &transaction.Transaction{}
@@ -165,4 +174,83 @@ func TestCreateZetherTx(t *testing.T) {
 	assert.Equal(t, true, tx.VerifySignatureManually())
 	assert.Equal(t, true, tx2.VerifySignatureManually())
 
+	//the proofs of both txs are verified in a single batch
+	batch := crypto.NewProofsBatchVerifier()
+	assert.Equal(t, -1, tx1Base.AddProofsToBatch(batch, tx.GetHashSigningManually()))
+	assert.Equal(t, -1, tx2Base.AddProofsToBatch(batch, tx2.GetHashSigningManually()))
+	assert.Equal(t, 2*count, batch.Count())
+	assert.Equal(t, true, batch.Verify())
+
+	assert.Equal(t, 0, tx1Base.AddProofsToBatch(crypto.NewProofsBatchVerifier(), helpers.RandomBytes(32)))
+
+}
+
+//the changed scalar is not hashed by the Sigma protocol, so only the postponed checks of the batch can detect it
+func tamperProof(t *testing.T, proof *crypto.Proof, innerProduct bool) *crypto.Proof {
+
+	ringPower := len(proof.CLnG)
+
+	w := advanced_buffers.NewBufferWriter()
+	proof.Serialize(w)
+	data := w.Bytes()
+
+	offset := (4+8*ringPower+1)*crypto.POINT_SIZE + 2*ringPower*crypto.FIELDELEMENT_SIZE //z_A
+	if innerProduct {
+		offset += crypto.FIELDELEMENT_SIZE + 2*crypto.POINT_SIZE + 7*crypto.FIELDELEMENT_SIZE //a of the inner product
+	}
+	data[offset+crypto.FIELDELEMENT_SIZE-1] ^= 1
+
+	proof2 := &crypto.Proof{}
+	assert.NoError(t, proof2.Deserialize(advanced_buffers.NewBufferReader(data), ringPower))
+	return proof2
+}
+
+func TestZetherProofsBatchVerifierTampered(t *testing.T) {
+
+	tx, _ := createTestZetherTx(t, 2)
+	base := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+	hash := tx.GetHashSigningManually()
+
+	for _, innerProduct := range []bool{false, true} {
+
+		batch := crypto.NewProofsBatchVerifier()
+		for i, payload := range base.Payloads {
+			proof := payload.Proof
+			if i == 1 {
+				proof = tamperProof(t, proof, innerProduct)
+			}
+			assert.True(t, batch.Add(proof, payload.Asset, i, base.ChainKernelHash, payload.Statement, hash, payload.BurnValue), "the Sigma protocol should be valid")
+		}
+		assert.Equal(t, 2, batch.Count())
+		assert.False(t, batch.Verify())
+
+		payload := base.Payloads[1]
+		assert.False(t, tamperProof(t, payload.Proof, innerProduct).Verify(payload.Asset, 1, base.ChainKernelHash, payload.Statement, hash, payload.BurnValue))
+	}
+}
+
+func TestValidateTxsBatchFallback(t *testing.T) {
+
+	if txs_validator.TxsValidator == nil {
+		assert.NoError(t, txs_validator.NewTxsValidator())
+	}
+
+	good, _ := createTestZetherTx(t, 2)
+	bad, _ := createTestZetherTx(t, 2)
+
+	badBase := bad.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+	badBase.Payloads[1].Proof = tamperProof(t, badBase.Payloads[1].Proof, false)
+
+	//the original txs are used as the deserialized statements miss the encrypted balances of the senders
+	txs := []*transaction.Transaction{good, bad}
+	for _, tx := range txs {
+		assert.NoError(t, tx.BloomAll())
+	}
+
+	//the batch fails, so the txs are verified one by one and only the bad tx is rejected
+	err := txs_validator.TxsValidator.ValidateTxs(txs)
+	assert.EqualError(t, err, "Proof payload 1 failed")
+
+	assert.NoError(t, txs_validator.TxsValidator.ValidateTx(txs[0]))
+	assert.EqualError(t, txs_validator.TxsValidator.ValidateTx(txs[1]), "Proof payload 1 failed")
 }
@@ -30,6 +30,38 @@ type WizardZetherPayloadExtraAssetSupplyIncrease struct {
 	AssetSupplyPrivateKey    []byte `json:"assetSupplyPublicKey" msgpack:"assetSupplyPublicKey"`
 }
 
+type WizardZetherPayloadExtraAssetSupplyDecrease struct {
+	WizardZetherPayloadExtra `json:"-" msgpack:""`
+	AssetSupplyPrivateKey    []byte `json:"assetSupplyPrivateKey" msgpack:"assetSupplyPrivateKey"`
+}
+
+type WizardZetherPayloadExtraAssetPause struct {
+	WizardZetherPayloadExtra `json:"-" msgpack:""`
+	AssetId                  []byte `json:"assetId" msgpack:"assetId"`
+	Paused                   bool   `json:"paused" msgpack:"paused"`
+	AssetUpdatePrivateKey    []byte `json:"assetUpdatePrivateKey" msgpack:"assetUpdatePrivateKey"`
+}
+
+type WizardZetherPayloadExtraAssetFreeze struct {
+	WizardZetherPayloadExtra `json:"-" msgpack:""`
+	AssetId                  []byte `json:"assetId" msgpack:"assetId"`
+	AssetUpdatePrivateKey    []byte `json:"assetUpdatePrivateKey" msgpack:"assetUpdatePrivateKey"`
+}
+
+type WizardZetherPayloadExtraAssetUpdate struct {
+	WizardZetherPayloadExtra `json:"-" msgpack:""`
+	AssetId                  []byte `json:"assetId" msgpack:"assetId"`
+	ChangeDescription        bool   `json:"changeDescription" msgpack:"changeDescription"`
+	Description              string `json:"description" msgpack:"description"`
+	ChangeData               bool   `json:"changeData" msgpack:"changeData"`
+	Data                     []byte `json:"data" msgpack:"data"`
+	ChangeMaxSupply          bool   `json:"changeMaxSupply" msgpack:"changeMaxSupply"`
+	MaxSupply                uint64 `json:"maxSupply" msgpack:"maxSupply"`
+	NewUpdatePublicKey       []byte `json:"newUpdatePublicKey" msgpack:"newUpdatePublicKey"`
+	NewSupplyPublicKey       []byte `json:"newSupplyPublicKey" msgpack:"newSupplyPublicKey"`
+	AssetUpdatePrivateKey    []byte `json:"assetUpdatePrivateKey" msgpack:"assetUpdatePrivateKey"`
+}
+
 type WizardZetherPayloadExtraPlainAccountFund struct {
 	WizardZetherPayloadExtra `json:"-" msgpack:""`
 	PlainAccountPublicKey    []byte `json:"plainAccountPublicKey" msgpack:"plainAccountPublicKey"`
@@ -41,6 +73,7 @@ type WizardZetherPayloadExtraConditionalPayment struct {
 	DefaultResolution        bool     `json:"defaultResolution" msgpack:"defaultResolution"`
 	Threshold                byte     `json:"threshold" msgpack:"threshold"`
 	MultisigPublicKeys       [][]byte `json:"multisigPublicKeys" msgpack:"multisigPublicKeys"`
+	Hashlock                 []byte   `json:"hashlock,omitempty" msgpack:"hashlock,omitempty"` //SHA256 of the preimage. Threshold must be zero
 }
 
 type WizardZetherPayloadExtra interface {
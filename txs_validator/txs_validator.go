@@ -10,9 +10,10 @@ import (
 )
 
 type TxsValidatorType struct {
-	all                 *generics.Map[string, *txValidatedWork]
-	workers             []*TxsValidatorWorker
-	newValidationWorkCn chan *txValidatedWork
+	all                  *generics.Map[string, *txValidatedWork]
+	workers              []*TxsValidatorWorker
+	newValidationWorkCn  chan *txValidatedWork
+	newValidationBatchCn chan []*txValidatedWork
 }
 
 var TxsValidator *TxsValidatorType
@@ -68,14 +69,23 @@ func (validator *TxsValidatorType) ValidateTx(tx *transaction.Transaction) error
 func (validator *TxsValidatorType) ValidateTxs(txs []*transaction.Transaction) error {
 
 	outputs := make([]*txValidatedWork, len(txs))
+	works := make([]*txValidatedWork, 0, len(txs))
 	for i, tx := range txs {
 		foundWork, loaded := validator.all.LoadOrStore(tx.Bloom.HashStr, &txValidatedWork{make(chan struct{}), TX_VALIDATED_INIT, tx, 0, nil, nil})
 		if !loaded {
-			validator.newValidationWorkCn <- foundWork
+			works = append(works, foundWork)
 		}
 		outputs[i] = foundWork
 	}
 
+	//the new txs are split among the workers and each worker verifies its zether proofs in a batch
+	if len(works) > 0 {
+		size := (len(works) + len(validator.workers) - 1) / len(validator.workers)
+		for start := 0; start < len(works); start += size {
+			validator.newValidationBatchCn <- works[start:generics.Min(start+size, len(works))]
+		}
+	}
+
 	for _, foundWork := range outputs {
 		<-foundWork.wait
 		if foundWork.result != nil {
@@ -129,10 +139,11 @@ func NewTxsValidator() error {
 		&generics.Map[string, *txValidatedWork]{},
 		make([]*TxsValidatorWorker, threadsCount),
 		make(chan *txValidatedWork, 1),
+		make(chan []*txValidatedWork, 1),
 	}
 
 	for i := range TxsValidator.workers {
-		TxsValidator.workers[i] = newTxsValidatorWorker(TxsValidator.newValidationWorkCn)
+		TxsValidator.workers[i] = newTxsValidatorWorker(TxsValidator.newValidationWorkCn, TxsValidator.newValidationBatchCn)
 	}
 
 	for _, worker := range TxsValidator.workers {
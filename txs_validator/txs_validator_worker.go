@@ -7,15 +7,18 @@ import (
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
 	"pandora-pay/config"
+	"pandora-pay/cryptography/crypto"
 	"sync/atomic"
 	"time"
 )
 
 type TxsValidatorWorker struct {
-	newValidationWorkCn chan *txValidatedWork
+	newValidationWorkCn  chan *txValidatedWork
+	newValidationBatchCn chan []*txValidatedWork
 }
 
-func (worker *TxsValidatorWorker) verifyTx(foundWork *txValidatedWork) error {
+// the zether proofs are only added to the batch, the batch must be verified afterwards
+func (worker *TxsValidatorWorker) verifyTxBatch(foundWork *txValidatedWork, batch *crypto.ProofsBatchVerifier) error {
 
 	if err := foundWork.tx.VerifyBloomAll(); err != nil {
 		return err
@@ -31,17 +34,13 @@ func (worker *TxsValidatorWorker) verifyTx(foundWork *txValidatedWork) error {
 
 		base := foundWork.tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
 		//verify signature
-		assetMap := map[string]int{}
-		for payloadIndex, payload := range base.Payloads {
-			if !payload.Proof.Verify(payload.Asset, assetMap[string(payload.Asset)], base.ChainKernelHash, payload.Statement, hashForSignature, payload.BurnValue) {
-				return fmt.Errorf("Proof payload %d failed", payloadIndex)
-			}
-			assetMap[string(payload.Asset)] = assetMap[string(payload.Asset)] + 1
+		if payloadIndex := base.AddProofsToBatch(batch, hashForSignature); payloadIndex != -1 {
+			return fmt.Errorf("Proof payload %d failed", payloadIndex)
 		}
 
 		for _, payload := range base.Payloads {
 			switch payload.PayloadScript {
-			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE, transaction_zether_payload_script.SCRIPT_SPEND:
+			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE, transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE, transaction_zether_payload_script.SCRIPT_ASSET_PAUSE, transaction_zether_payload_script.SCRIPT_ASSET_FREEZE, transaction_zether_payload_script.SCRIPT_ASSET_UPDATE, transaction_zether_payload_script.SCRIPT_SPEND:
 				if payload.Extra.VerifyExtraSignature(hashForSignature, payload.Statement) == false {
 					return errors.New("Extra signature failed")
 				}
@@ -54,25 +53,85 @@ func (worker *TxsValidatorWorker) verifyTx(foundWork *txValidatedWork) error {
 	return nil
 }
 
-func (worker *TxsValidatorWorker) run() {
+// the proofs of a zether tx are verified in a batch. If the batch fails, each proof is verified alone to find the invalid payload
+func (worker *TxsValidatorWorker) verifyTx(foundWork *txValidatedWork) error {
 
-	for {
-		foundWork, _ := <-worker.newValidationWorkCn
+	batch := crypto.NewProofsBatchVerifier()
+	if err := worker.verifyTxBatch(foundWork, batch); err != nil {
+		return err
+	}
+
+	if batch.Count() == 0 || batch.Verify() {
+		return nil
+	}
 
-		if err := foundWork.tx.BloomAll(); err != nil {
+	base := foundWork.tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+	hashForSignature := foundWork.tx.GetHashSigningManually()
+
+	assetMap := map[string]int{}
+	for payloadIndex, payload := range base.Payloads {
+		if !payload.Proof.Verify(payload.Asset, assetMap[string(payload.Asset)], base.ChainKernelHash, payload.Statement, hashForSignature, payload.BurnValue) {
+			return fmt.Errorf("Proof payload %d failed", payloadIndex)
+		}
+		assetMap[string(payload.Asset)] = assetMap[string(payload.Asset)] + 1
+	}
+
+	return errors.New("Proofs batch verification failed")
+}
+
+func (worker *TxsValidatorWorker) processWork(foundWork *txValidatedWork, verify func(foundWork *txValidatedWork) error) {
+	if err := foundWork.tx.BloomAll(); err != nil {
+		foundWork.result = err
+	} else {
+		foundWork.bloomExtra = foundWork.tx.TransactionBaseInterface.GetBloomExtra()
+		if err = verify(foundWork); err != nil {
 			foundWork.result = err
-		} else {
-			foundWork.bloomExtra = foundWork.tx.TransactionBaseInterface.GetBloomExtra()
-			if err = worker.verifyTx(foundWork); err != nil {
-				foundWork.result = err
+		}
+	}
+}
+
+func (worker *TxsValidatorWorker) finishWork(foundWork *txValidatedWork) {
+
+	foundWork.tx = nil
+	foundWork.time = time.Now().Add(EXPIRE_TIME_MS).Unix()
+	atomic.StoreInt32(&foundWork.status, TX_VALIDATED_PROCCESSED)
+
+	close(foundWork.wait)
+}
+
+// the zether proofs of all the txs are verified in a single batch. If the batch fails, the txs are verified one by one to find the invalid ones
+func (worker *TxsValidatorWorker) processBatch(works []*txValidatedWork) {
+
+	batch := crypto.NewProofsBatchVerifier()
+	for _, foundWork := range works {
+		worker.processWork(foundWork, func(foundWork *txValidatedWork) error {
+			return worker.verifyTxBatch(foundWork, batch)
+		})
+	}
+
+	if batch.Count() > 0 && !batch.Verify() {
+		for _, foundWork := range works {
+			if foundWork.result == nil && foundWork.tx.Version == transaction_type.TX_ZETHER {
+				foundWork.result = worker.verifyTx(foundWork)
 			}
 		}
+	}
+
+	for _, foundWork := range works {
+		worker.finishWork(foundWork)
+	}
+}
 
-		foundWork.tx = nil
-		foundWork.time = time.Now().Add(EXPIRE_TIME_MS).Unix()
-		atomic.StoreInt32(&foundWork.status, TX_VALIDATED_PROCCESSED)
+func (worker *TxsValidatorWorker) run() {
 
-		close(foundWork.wait)
+	for {
+		select {
+		case foundWork := <-worker.newValidationWorkCn:
+			worker.processWork(foundWork, worker.verifyTx)
+			worker.finishWork(foundWork)
+		case works := <-worker.newValidationBatchCn:
+			worker.processBatch(works)
+		}
 
 		if config.LIGHT_COMPUTATIONS {
 			time.Sleep(50 * time.Millisecond)
@@ -85,9 +144,10 @@ func (worker *TxsValidatorWorker) start() {
 	go worker.run()
 }
 
-func newTxsValidatorWorker(newValidationWorkCn chan *txValidatedWork) *TxsValidatorWorker {
+func newTxsValidatorWorker(newValidationWorkCn chan *txValidatedWork, newValidationBatchCn chan []*txValidatedWork) *TxsValidatorWorker {
 	worker := &TxsValidatorWorker{
 		newValidationWorkCn,
+		newValidationBatchCn,
 	}
 	return worker
 }
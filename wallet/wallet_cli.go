@@ -16,6 +16,7 @@ import (
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
 	"pandora-pay/blockchain/data_storage/registrations"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
 	"pandora-pay/config"
 	"pandora-pay/config/config_coins"
@@ -23,9 +24,11 @@ import (
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/gui"
 	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/helpers/files"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/txs_builder/wizard"
 	"pandora-pay/wallet/wallet_address"
 	"pandora-pay/wallet/wallet_address/shared_staked"
 	"strconv"
@@ -710,6 +713,29 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliSignMultisigTx := func(cmd string, ctx context.Context) (err error) {
+
+		walletAddress, _, _, err := wallet.CliSelectAddress("Select Cosigner Address", ctx)
+		if err != nil {
+			return
+		}
+		if walletAddress.PrivateKey == nil {
+			return errors.New("Can't be used for transactions as the private key is missing")
+		}
+
+		tx := &transaction.Transaction{}
+		if err = tx.Deserialize(advanced_buffers.NewBufferReader(gui.GUI.OutputReadBytes("Multisig Transaction", nil))); err != nil {
+			return
+		}
+
+		if err = wizard.SignSimpleTxMultisig(tx, walletAddress.PrivateKey.Key); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Signed Transaction: %s", base64.StdEncoding.EncodeToString(tx.Bloom.Serialized)))
+		return
+	}
+
 	gui.GUI.CommandDefineCallback("List Addresses", wallet.CliListAddresses, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Scan Addresses", wallet.CliScanAddresses, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Create New Address", cliCreateNewAddress, wallet.Loaded)
@@ -734,5 +760,6 @@ func (wallet *Wallet) initWalletCLI() {
 	gui.GUI.CommandDefineCallback("Create (PublicKey, PrivateKey) pair", cliCreatePair, true)
 	gui.GUI.CommandDefineCallback("Sign message using PrivateKey", cliSignMessage, true)
 	gui.GUI.CommandDefineCallback("Sign Resolution Conditional Payment", cliSignResolutionConditionalPayment, true)
+	gui.GUI.CommandDefineCallback("Sign Multisig Plain Account Transaction", cliSignMultisigTx, wallet.Loaded)
 
 }